@@ -29,6 +29,7 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 )
 
 const (
@@ -186,6 +187,10 @@ type PatchSpec struct {
 	Executor *PatchExecutor `protobuf:"bytes,1,opt,name=executor,proto3" json:"executor,omitempty"`
 	// Patch selector rules. Applied in the declaration order.
 	Rules []*PatchRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	// JSON schema used to validate values before patch execution.
+	ValuesSchema *structpb.Struct `protobuf:"bytes,3,opt,name=valuesSchema,proto3" json:"valuesSchema,omitempty"`
+	// Input bundle state expected before patch execution.
+	Preconditions *PatchPreconditions `protobuf:"bytes,4,opt,name=preconditions,proto3" json:"preconditions,omitempty"`
 }
 
 func (x *PatchSpec) Reset() {
@@ -232,6 +237,20 @@ func (x *PatchSpec) GetRules() []*PatchRule {
 	return nil
 }
 
+func (x *PatchSpec) GetValuesSchema() *structpb.Struct {
+	if x != nil {
+		return x.ValuesSchema
+	}
+	return nil
+}
+
+func (x *PatchSpec) GetPreconditions() *PatchPreconditions {
+	if x != nil {
+		return x.Preconditions
+	}
+	return nil
+}
+
 type PatchExecutor struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -278,6 +297,157 @@ func (x *PatchExecutor) GetDisableAnnotations() bool {
 	return false
 }
 
+// PatchPreconditions describes the input bundle state required to apply the
+// patch.
+type PatchPreconditions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Expected input bundle merkle tree root (base64url encoded).
+	MerkleTreeRoot string `protobuf:"bytes,1,opt,name=merkleTreeRoot,proto3" json:"merkleTreeRoot,omitempty"`
+	// Package paths which must exist in the input bundle.
+	RequiredPaths []string `protobuf:"bytes,2,rep,name=requiredPaths,proto3" json:"requiredPaths,omitempty"`
+	// Package paths which must not exist in the input bundle.
+	ForbiddenPaths []string `protobuf:"bytes,3,rep,name=forbiddenPaths,proto3" json:"forbiddenPaths,omitempty"`
+	// Expected secret value fingerprints.
+	Fingerprints []*PatchSecretFingerprint `protobuf:"bytes,4,rep,name=fingerprints,proto3" json:"fingerprints,omitempty"`
+	// HMAC key used to compute secret value fingerprints. Value can be
+	// templatized to avoid storing the key in the patch.
+	FingerprintKey string `protobuf:"bytes,5,opt,name=fingerprintKey,proto3" json:"fingerprintKey,omitempty"`
+}
+
+func (x *PatchPreconditions) Reset() {
+	*x = PatchPreconditions{}
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchPreconditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchPreconditions) ProtoMessage() {}
+
+func (x *PatchPreconditions) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchPreconditions.ProtoReflect.Descriptor instead.
+func (*PatchPreconditions) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PatchPreconditions) GetMerkleTreeRoot() string {
+	if x != nil {
+		return x.MerkleTreeRoot
+	}
+	return ""
+}
+
+func (x *PatchPreconditions) GetRequiredPaths() []string {
+	if x != nil {
+		return x.RequiredPaths
+	}
+	return nil
+}
+
+func (x *PatchPreconditions) GetForbiddenPaths() []string {
+	if x != nil {
+		return x.ForbiddenPaths
+	}
+	return nil
+}
+
+func (x *PatchPreconditions) GetFingerprints() []*PatchSecretFingerprint {
+	if x != nil {
+		return x.Fingerprints
+	}
+	return nil
+}
+
+func (x *PatchPreconditions) GetFingerprintKey() string {
+	if x != nil {
+		return x.FingerprintKey
+	}
+	return ""
+}
+
+// PatchSecretFingerprint represents an expected secret value fingerprint.
+type PatchSecretFingerprint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. Package path.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// REQUIRED. Secret key.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// REQUIRED. Secret value fingerprint (hex encoded HMAC-SHA256 of the packed
+	// value keyed by fingerprintKey).
+	Fingerprint string `protobuf:"bytes,3,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+}
+
+func (x *PatchSecretFingerprint) Reset() {
+	*x = PatchSecretFingerprint{}
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchSecretFingerprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchSecretFingerprint) ProtoMessage() {}
+
+func (x *PatchSecretFingerprint) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchSecretFingerprint.ProtoReflect.Descriptor instead.
+func (*PatchSecretFingerprint) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PatchSecretFingerprint) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PatchSecretFingerprint) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PatchSecretFingerprint) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
 // PatchRule represents an operation to apply to a given bundle.
 type PatchRule struct {
 	state         protoimpl.MessageState
@@ -294,7 +464,7 @@ type PatchRule struct {
 
 func (x *PatchRule) Reset() {
 	*x = PatchRule{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[4]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -306,7 +476,7 @@ func (x *PatchRule) String() string {
 func (*PatchRule) ProtoMessage() {}
 
 func (x *PatchRule) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[4]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -319,7 +489,7 @@ func (x *PatchRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchRule.ProtoReflect.Descriptor instead.
 func (*PatchRule) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{4}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PatchRule) GetId() string {
@@ -361,11 +531,26 @@ type PatchSelector struct {
 	MatchSecret *PatchSelectorMatchSecret `protobuf:"bytes,5,opt,name=matchSecret,proto3" json:"matchSecret,omitempty"`
 	// Match a package using CEL expressions.
 	Cel []string `protobuf:"bytes,6,rep,name=cel,proto3" json:"cel,omitempty"`
+	// Match a package satisfying all given selectors.
+	AllOf []*PatchSelector `protobuf:"bytes,7,rep,name=allOf,proto3" json:"allOf,omitempty"`
+	// Match a package satisfying at least one of the given selectors.
+	AnyOf []*PatchSelector `protobuf:"bytes,8,rep,name=anyOf,proto3" json:"anyOf,omitempty"`
+	// Match a package not satisfying the given selector.
+	Not *PatchSelector `protobuf:"bytes,9,opt,name=not,proto3" json:"not,omitempty"`
+	// Rego query evaluated by the policy. Default to `data.harp.matched`.
+	RegoQuery string `protobuf:"bytes,10,opt,name=regoQuery,proto3" json:"regoQuery,omitempty"`
+	// Match a package using Rego policy paths (files, directories or OPA
+	// bundle archives).
+	RegoPaths []string `protobuf:"bytes,11,rep,name=regoPaths,proto3" json:"regoPaths,omitempty"`
+	// Data document files (JSON or YAML) exposed to Rego policies.
+	RegoData []string `protobuf:"bytes,12,rep,name=regoData,proto3" json:"regoData,omitempty"`
+	// Match a package by secret value.
+	MatchValue *PatchSelectorMatchValue `protobuf:"bytes,13,opt,name=matchValue,proto3" json:"matchValue,omitempty"`
 }
 
 func (x *PatchSelector) Reset() {
 	*x = PatchSelector{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[5]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -377,7 +562,7 @@ func (x *PatchSelector) String() string {
 func (*PatchSelector) ProtoMessage() {}
 
 func (x *PatchSelector) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[5]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +575,7 @@ func (x *PatchSelector) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchSelector.ProtoReflect.Descriptor instead.
 func (*PatchSelector) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{5}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PatchSelector) GetMatchPath() *PatchSelectorMatchPath {
@@ -435,6 +620,55 @@ func (x *PatchSelector) GetCel() []string {
 	return nil
 }
 
+func (x *PatchSelector) GetAllOf() []*PatchSelector {
+	if x != nil {
+		return x.AllOf
+	}
+	return nil
+}
+
+func (x *PatchSelector) GetAnyOf() []*PatchSelector {
+	if x != nil {
+		return x.AnyOf
+	}
+	return nil
+}
+
+func (x *PatchSelector) GetNot() *PatchSelector {
+	if x != nil {
+		return x.Not
+	}
+	return nil
+}
+
+func (x *PatchSelector) GetRegoQuery() string {
+	if x != nil {
+		return x.RegoQuery
+	}
+	return ""
+}
+
+func (x *PatchSelector) GetRegoPaths() []string {
+	if x != nil {
+		return x.RegoPaths
+	}
+	return nil
+}
+
+func (x *PatchSelector) GetRegoData() []string {
+	if x != nil {
+		return x.RegoData
+	}
+	return nil
+}
+
+func (x *PatchSelector) GetMatchValue() *PatchSelectorMatchValue {
+	if x != nil {
+		return x.MatchValue
+	}
+	return nil
+}
+
 // PatchSelectorMatchPath represents package path matching strategies.
 type PatchSelectorMatchPath struct {
 	state         protoimpl.MessageState
@@ -454,7 +688,7 @@ type PatchSelectorMatchPath struct {
 
 func (x *PatchSelectorMatchPath) Reset() {
 	*x = PatchSelectorMatchPath{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[6]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -466,7 +700,7 @@ func (x *PatchSelectorMatchPath) String() string {
 func (*PatchSelectorMatchPath) ProtoMessage() {}
 
 func (x *PatchSelectorMatchPath) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[6]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -479,7 +713,7 @@ func (x *PatchSelectorMatchPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchSelectorMatchPath.ProtoReflect.Descriptor instead.
 func (*PatchSelectorMatchPath) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{6}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PatchSelectorMatchPath) GetStrict() string {
@@ -522,7 +756,7 @@ type PatchSelectorMatchSecret struct {
 
 func (x *PatchSelectorMatchSecret) Reset() {
 	*x = PatchSelectorMatchSecret{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[7]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -534,7 +768,7 @@ func (x *PatchSelectorMatchSecret) String() string {
 func (*PatchSelectorMatchSecret) ProtoMessage() {}
 
 func (x *PatchSelectorMatchSecret) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[7]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,7 +781,7 @@ func (x *PatchSelectorMatchSecret) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchSelectorMatchSecret.ProtoReflect.Descriptor instead.
 func (*PatchSelectorMatchSecret) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{7}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *PatchSelectorMatchSecret) GetStrict() string {
@@ -571,6 +805,187 @@ func (x *PatchSelectorMatchSecret) GetGlob() string {
 	return ""
 }
 
+// PatchSelectorMatchValue represents secret value matching strategies. All
+// defined criteria must be satisfied by the same secret.
+type PatchSelectorMatchValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Glob pattern restricting inspected secret keys. Default to all keys.
+	// Value can be templatized.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Decoded value type (string, bytes, map, list, number, bool).
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Minimum value size. String and bytes sizes are expressed in bytes, other
+	// types use their JSON encoded size.
+	MinSize uint32 `protobuf:"varint,3,opt,name=minSize,proto3" json:"minSize,omitempty"`
+	// Maximum value size.
+	MaxSize uint32 `protobuf:"varint,4,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+	// Regex matched against string and bytes values.
+	// Value can be templatized.
+	Regex string `protobuf:"bytes,5,opt,name=regex,proto3" json:"regex,omitempty"`
+	// Detected value format (pem, certificate, private_key, public_key, jwk,
+	// jwt, json, url, uuid, email, base64).
+	Format string `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	// Key algorithm of certificates, keys and JWK (rsa, ec, ed25519).
+	KeyType string `protobuf:"bytes,7,opt,name=keyType,proto3" json:"keyType,omitempty"`
+	// Key size in bits.
+	KeyBits uint32 `protobuf:"varint,8,opt,name=keyBits,proto3" json:"keyBits,omitempty"`
+	// Keyed hash equality check.
+	Hash *PatchSelectorMatchValueHash `protobuf:"bytes,9,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (x *PatchSelectorMatchValue) Reset() {
+	*x = PatchSelectorMatchValue{}
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchSelectorMatchValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchSelectorMatchValue) ProtoMessage() {}
+
+func (x *PatchSelectorMatchValue) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchSelectorMatchValue.ProtoReflect.Descriptor instead.
+func (*PatchSelectorMatchValue) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PatchSelectorMatchValue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValue) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValue) GetMinSize() uint32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *PatchSelectorMatchValue) GetMaxSize() uint32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *PatchSelectorMatchValue) GetRegex() string {
+	if x != nil {
+		return x.Regex
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValue) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValue) GetKeyType() string {
+	if x != nil {
+		return x.KeyType
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValue) GetKeyBits() uint32 {
+	if x != nil {
+		return x.KeyBits
+	}
+	return 0
+}
+
+func (x *PatchSelectorMatchValue) GetHash() *PatchSelectorMatchValueHash {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+// PatchSelectorMatchValueHash represents a keyed hash value comparison.
+type PatchSelectorMatchValueHash struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// HMAC key.
+	// Value can be templatized.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Hex encoded HMAC-SHA256 of the secret value.
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *PatchSelectorMatchValueHash) Reset() {
+	*x = PatchSelectorMatchValueHash{}
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchSelectorMatchValueHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchSelectorMatchValueHash) ProtoMessage() {}
+
+func (x *PatchSelectorMatchValueHash) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchSelectorMatchValueHash.ProtoReflect.Descriptor instead.
+func (*PatchSelectorMatchValueHash) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PatchSelectorMatchValueHash) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PatchSelectorMatchValueHash) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 // PatchPackagePath represents package path operations.
 type PatchPackagePath struct {
 	state         protoimpl.MessageState
@@ -583,7 +998,7 @@ type PatchPackagePath struct {
 
 func (x *PatchPackagePath) Reset() {
 	*x = PatchPackagePath{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[8]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -595,7 +1010,7 @@ func (x *PatchPackagePath) String() string {
 func (*PatchPackagePath) ProtoMessage() {}
 
 func (x *PatchPackagePath) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[8]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -608,7 +1023,7 @@ func (x *PatchPackagePath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchPackagePath.ProtoReflect.Descriptor instead.
 func (*PatchPackagePath) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{8}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *PatchPackagePath) GetTemplate() string {
@@ -636,11 +1051,17 @@ type PatchPackage struct {
 	Remove bool `protobuf:"varint,5,opt,name=remove,proto3" json:"remove,omitempty"`
 	// Flag to create if not exist.
 	Create bool `protobuf:"varint,6,opt,name=create,proto3" json:"create,omitempty"`
+	// Copy the package to a target path.
+	Copy *PatchPackageTarget `protobuf:"bytes,7,opt,name=copy,proto3" json:"copy,omitempty"`
+	// Move the package to a target path.
+	Move *PatchPackageTarget `protobuf:"bytes,8,opt,name=move,proto3" json:"move,omitempty"`
+	// Merge the package into a target package.
+	MergeInto *PatchPackageTarget `protobuf:"bytes,9,opt,name=mergeInto,proto3" json:"mergeInto,omitempty"`
 }
 
 func (x *PatchPackage) Reset() {
 	*x = PatchPackage{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[9]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -652,7 +1073,7 @@ func (x *PatchPackage) String() string {
 func (*PatchPackage) ProtoMessage() {}
 
 func (x *PatchPackage) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[9]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -665,7 +1086,7 @@ func (x *PatchPackage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchPackage.ProtoReflect.Descriptor instead.
 func (*PatchPackage) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{9}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PatchPackage) GetPath() *PatchPackagePath {
@@ -710,6 +1131,93 @@ func (x *PatchPackage) GetCreate() bool {
 	return false
 }
 
+func (x *PatchPackage) GetCopy() *PatchPackageTarget {
+	if x != nil {
+		return x.Copy
+	}
+	return nil
+}
+
+func (x *PatchPackage) GetMove() *PatchPackageTarget {
+	if x != nil {
+		return x.Move
+	}
+	return nil
+}
+
+func (x *PatchPackage) GetMergeInto() *PatchPackageTarget {
+	if x != nil {
+		return x.MergeInto
+	}
+	return nil
+}
+
+// PatchPackageTarget represents package copy, move and merge target.
+type PatchPackageTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Template used to build the target package path.
+	// Value can be templatized.
+	Template string `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// CEL expression used to build the target package path.
+	Cel string `protobuf:"bytes,2,opt,name=cel,proto3" json:"cel,omitempty"`
+	// Conflict resolution policy (error, skip, overwrite).
+	OnConflict string `protobuf:"bytes,3,opt,name=onConflict,proto3" json:"onConflict,omitempty"`
+}
+
+func (x *PatchPackageTarget) Reset() {
+	*x = PatchPackageTarget{}
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchPackageTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchPackageTarget) ProtoMessage() {}
+
+func (x *PatchPackageTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchPackageTarget.ProtoReflect.Descriptor instead.
+func (*PatchPackageTarget) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PatchPackageTarget) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *PatchPackageTarget) GetCel() string {
+	if x != nil {
+		return x.Cel
+	}
+	return ""
+}
+
+func (x *PatchPackageTarget) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
 // PatchSecret represents secret data operations.
 type PatchSecret struct {
 	state         protoimpl.MessageState
@@ -728,7 +1236,7 @@ type PatchSecret struct {
 
 func (x *PatchSecret) Reset() {
 	*x = PatchSecret{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[10]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -740,7 +1248,7 @@ func (x *PatchSecret) String() string {
 func (*PatchSecret) ProtoMessage() {}
 
 func (x *PatchSecret) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[10]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -753,7 +1261,7 @@ func (x *PatchSecret) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchSecret.ProtoReflect.Descriptor instead.
 func (*PatchSecret) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{10}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *PatchSecret) GetAnnotations() *PatchOperation {
@@ -808,7 +1316,7 @@ type PatchOperation struct {
 
 func (x *PatchOperation) Reset() {
 	*x = PatchOperation{}
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[11]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -820,7 +1328,7 @@ func (x *PatchOperation) String() string {
 func (*PatchOperation) ProtoMessage() {}
 
 func (x *PatchOperation) ProtoReflect() protoreflect.Message {
-	mi := &file_harp_bundle_v1_patch_proto_msgTypes[11]
+	mi := &file_harp_bundle_v1_patch_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,7 +1341,7 @@ func (x *PatchOperation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PatchOperation.ProtoReflect.Descriptor instead.
 func (*PatchOperation) Descriptor() ([]byte, []int) {
-	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{11}
+	return file_harp_bundle_v1_patch_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *PatchOperation) GetAdd() map[string]string {
@@ -876,145 +1384,237 @@ var File_harp_bundle_v1_patch_proto protoreflect.FileDescriptor
 var file_harp_bundle_v1_patch_proto_rawDesc = []byte{
 	0x0a, 0x1a, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31,
 	0x2f, 0x70, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x68, 0x61,
-	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x9a, 0x01, 0x0a,
-	0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x69, 0x5f, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69,
-	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x6d,
-	0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70,
-	0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x70,
-	0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
-	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53,
-	0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x57, 0x0a, 0x09, 0x50, 0x61, 0x74,
-	0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77,
-	0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72,
-	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x09, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x70, 0x65, 0x63, 0x12,
-	0x39, 0x0a, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1d, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e,
-	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72,
-	0x52, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x05, 0x72, 0x75,
-	0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70,
-	0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x3f, 0x0a, 0x0d, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x12,
+	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9a, 0x01, 0x0a, 0x05, 0x50,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x69, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62,
+	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x70, 0x65,
+	0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x57, 0x0a, 0x09, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0xfe, 0x01, 0x0a, 0x09, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x70, 0x65, 0x63, 0x12, 0x39,
+	0x0a, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x52,
+	0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0c, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0c, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x48, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x3f, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63, 0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x12, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x6e, 0x6e,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12,
 	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c,
-	0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x8e, 0x01, 0x0a,
-	0x09, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x08, 0x73, 0x65,
-	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x68,
+	0x6e, 0x73, 0x22, 0xfe, 0x01, 0x0a, 0x12, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x65, 0x72,
+	0x6b, 0x6c, 0x65, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x66, 0x6f, 0x72, 0x62, 0x69,
+	0x64, 0x64, 0x65, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12,
+	0x4a, 0x0a, 0x0c, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e,
+	0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x0c, 0x66,
+	0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x66,
+	0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x4b, 0x65, 0x79, 0x22, 0x60, 0x0a, 0x16, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72,
+	0x70, 0x72, 0x69, 0x6e, 0x74, 0x22, 0x8e, 0x01, 0x0a, 0x09, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x75, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e,
+	0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x36,
+	0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x22, 0xbb, 0x04, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x44, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x68, 0x61,
+	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x09, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1a,
+	0x0a, 0x08, 0x6a, 0x6d, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6a, 0x6d, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x65,
+	0x67, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x65, 0x67, 0x6f, 0x12, 0x1a,
+	0x0a, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x65, 0x6c, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x03, 0x63, 0x65, 0x6c, 0x12, 0x33, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x4f,
+	0x66, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62,
+	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x4f, 0x66, 0x12, 0x33, 0x0a,
+	0x05, 0x61, 0x6e, 0x79, 0x4f, 0x66, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x68,
 	0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
-	0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x73, 0x65, 0x6c,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x36, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
-	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63,
-	0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x22, 0xff, 0x01,
-	0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12,
-	0x44, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x52, 0x09, 0x6d, 0x61, 0x74, 0x63,
-	0x68, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x6d, 0x65, 0x73, 0x50, 0x61, 0x74,
-	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x6d, 0x65, 0x73, 0x50, 0x61, 0x74,
-	0x68, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x65, 0x67, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x72, 0x65, 0x67, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c,
-	0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
-	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x10, 0x0a,
-	0x03, 0x63, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x63, 0x65, 0x6c, 0x22,
-	0x5a, 0x0a, 0x16, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72,
-	0x4d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72,
-	0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63,
-	0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x22, 0x5c, 0x0a, 0x18, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63,
-	0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x22, 0x2e, 0x0a, 0x10, 0x50, 0x61, 0x74,
-	0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a,
-	0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x0c, 0x50, 0x61,
-	0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x04, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
-	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50,
-	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
-	0x12, 0x40, 0x0a, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e,
-	0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x12, 0x36, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x2f, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
-	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x72,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x22, 0xd3, 0x01, 0x0a, 0x0b,
-	0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x40, 0x0a, 0x0b, 0x61,
-	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a,
-	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x05, 0x61, 0x6e, 0x79,
+	0x4f, 0x66, 0x12, 0x2f, 0x0a, 0x03, 0x6e, 0x6f, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x03,
+	0x6e, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0b,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a, 0x0a, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0a, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x5a, 0x0a, 0x16, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04,
+	0x67, 0x6c, 0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62,
+	0x22, 0x5c, 0x0a, 0x18, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x72, 0x69, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x6c,
+	0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x22, 0x96,
+	0x02, 0x0a, 0x17, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61,
+	0x78, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x61, 0x78,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6b, 0x65, 0x79, 0x42, 0x69, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6b,
+	0x65, 0x79, 0x42, 0x69, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x61, 0x73,
+	0x68, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0x45, 0x0a, 0x1b, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2e,
+	0x0a, 0x10, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0xd1,
+	0x03, 0x0a, 0x0c, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
+	0x34, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
 	0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6c,
-	0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
-	0x65, 0x12, 0x2e, 0x0a, 0x02, 0x6b, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
-	0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x6b,
-	0x76, 0x22, 0xcd, 0x03, 0x0a, 0x0e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x03, 0x61, 0x64, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x27, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e,
-	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x2e, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x61, 0x64, 0x64, 0x12,
-	0x16, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x06, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x42, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x40, 0x0a, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72,
+	0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63,
+	0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62,
 	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70,
-	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x51, 0x0a, 0x0b, 0x72,
-	0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x2f, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1e,
-	0x0a, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x1a, 0x36,
-	0x0a, 0x08, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
-	0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12,
+	0x2f, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x12, 0x36, 0x0a, 0x04, 0x63, 0x6f, 0x70, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x52, 0x04, 0x63, 0x6f, 0x70, 0x79, 0x12, 0x36, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x04, 0x6d, 0x6f, 0x76, 0x65,
+	0x12, 0x40, 0x0a, 0x09, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x6f, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x09, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x49, 0x6e,
+	0x74, 0x6f, 0x22, 0x62, 0x0a, 0x12, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x63, 0x65, 0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
+	0x6c, 0x69, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x6e, 0x43, 0x6f,
+	0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x22, 0xd3, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x40, 0x0a, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61,
+	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x6e, 0x6e,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x02,
+	0x6b, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x6b, 0x76, 0x22, 0xcd, 0x03, 0x0a,
+	0x0e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x39, 0x0a, 0x03, 0x61, 0x64, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x68,
+	0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x41, 0x64, 0x64,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x61, 0x64, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x12, 0x42, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x51, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x68, 0x61,
+	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x1a, 0x36, 0x0a, 0x08, 0x41, 0x64, 0x64,
 	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
-	0x01, 0x42, 0x9e, 0x01, 0x0a, 0x2a, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x65, 0x6c, 0x61, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x73, 0x65,
-	0x63, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
-	0x42, 0x0a, 0x50, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3a,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x61, 0x73, 0x74,
-	0x69, 0x63, 0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
-	0x67, 0x6f, 0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76,
-	0x31, 0x3b, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x42, 0x58,
-	0xaa, 0x02, 0x0e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x56,
-	0x31, 0xca, 0x02, 0x0e, 0x68, 0x61, 0x72, 0x70, 0x5c, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5c,
-	0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x9e, 0x01, 0x0a,
+	0x2a, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x65, 0x6c, 0x61, 0x73,
+	0x74, 0x69, 0x63, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x73, 0x65, 0x63, 0x2e, 0x68, 0x61, 0x72,
+	0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x0a, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x61, 0x73, 0x74, 0x69, 0x63, 0x2f, 0x68, 0x61,
+	0x72, 0x70, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x68, 0x61,
+	0x72, 0x70, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x75, 0x6e,
+	0x64, 0x6c, 0x65, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x42, 0x58, 0xaa, 0x02, 0x0e, 0x68, 0x61,
+	0x72, 0x70, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x0e, 0x68,
+	0x61, 0x72, 0x70, 0x5c, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1030,49 +1630,66 @@ func file_harp_bundle_v1_patch_proto_rawDescGZIP() []byte {
 }
 
 var (
-	file_harp_bundle_v1_patch_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+	file_harp_bundle_v1_patch_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 	file_harp_bundle_v1_patch_proto_goTypes  = []any{
-		(*Patch)(nil),                    // 0: harp.bundle.v1.Patch
-		(*PatchMeta)(nil),                // 1: harp.bundle.v1.PatchMeta
-		(*PatchSpec)(nil),                // 2: harp.bundle.v1.PatchSpec
-		(*PatchExecutor)(nil),            // 3: harp.bundle.v1.PatchExecutor
-		(*PatchRule)(nil),                // 4: harp.bundle.v1.PatchRule
-		(*PatchSelector)(nil),            // 5: harp.bundle.v1.PatchSelector
-		(*PatchSelectorMatchPath)(nil),   // 6: harp.bundle.v1.PatchSelectorMatchPath
-		(*PatchSelectorMatchSecret)(nil), // 7: harp.bundle.v1.PatchSelectorMatchSecret
-		(*PatchPackagePath)(nil),         // 8: harp.bundle.v1.PatchPackagePath
-		(*PatchPackage)(nil),             // 9: harp.bundle.v1.PatchPackage
-		(*PatchSecret)(nil),              // 10: harp.bundle.v1.PatchSecret
-		(*PatchOperation)(nil),           // 11: harp.bundle.v1.PatchOperation
-		nil,                              // 12: harp.bundle.v1.PatchOperation.AddEntry
-		nil,                              // 13: harp.bundle.v1.PatchOperation.UpdateEntry
-		nil,                              // 14: harp.bundle.v1.PatchOperation.ReplaceKeysEntry
+		(*Patch)(nil),                       // 0: harp.bundle.v1.Patch
+		(*PatchMeta)(nil),                   // 1: harp.bundle.v1.PatchMeta
+		(*PatchSpec)(nil),                   // 2: harp.bundle.v1.PatchSpec
+		(*PatchExecutor)(nil),               // 3: harp.bundle.v1.PatchExecutor
+		(*PatchPreconditions)(nil),          // 4: harp.bundle.v1.PatchPreconditions
+		(*PatchSecretFingerprint)(nil),      // 5: harp.bundle.v1.PatchSecretFingerprint
+		(*PatchRule)(nil),                   // 6: harp.bundle.v1.PatchRule
+		(*PatchSelector)(nil),               // 7: harp.bundle.v1.PatchSelector
+		(*PatchSelectorMatchPath)(nil),      // 8: harp.bundle.v1.PatchSelectorMatchPath
+		(*PatchSelectorMatchSecret)(nil),    // 9: harp.bundle.v1.PatchSelectorMatchSecret
+		(*PatchSelectorMatchValue)(nil),     // 10: harp.bundle.v1.PatchSelectorMatchValue
+		(*PatchSelectorMatchValueHash)(nil), // 11: harp.bundle.v1.PatchSelectorMatchValueHash
+		(*PatchPackagePath)(nil),            // 12: harp.bundle.v1.PatchPackagePath
+		(*PatchPackage)(nil),                // 13: harp.bundle.v1.PatchPackage
+		(*PatchPackageTarget)(nil),          // 14: harp.bundle.v1.PatchPackageTarget
+		(*PatchSecret)(nil),                 // 15: harp.bundle.v1.PatchSecret
+		(*PatchOperation)(nil),              // 16: harp.bundle.v1.PatchOperation
+		nil,                                 // 17: harp.bundle.v1.PatchOperation.AddEntry
+		nil,                                 // 18: harp.bundle.v1.PatchOperation.UpdateEntry
+		nil,                                 // 19: harp.bundle.v1.PatchOperation.ReplaceKeysEntry
+		(*structpb.Struct)(nil),             // 20: google.protobuf.Struct
 	}
 )
 var file_harp_bundle_v1_patch_proto_depIdxs = []int32{
 	1,  // 0: harp.bundle.v1.Patch.meta:type_name -> harp.bundle.v1.PatchMeta
 	2,  // 1: harp.bundle.v1.Patch.spec:type_name -> harp.bundle.v1.PatchSpec
 	3,  // 2: harp.bundle.v1.PatchSpec.executor:type_name -> harp.bundle.v1.PatchExecutor
-	4,  // 3: harp.bundle.v1.PatchSpec.rules:type_name -> harp.bundle.v1.PatchRule
-	5,  // 4: harp.bundle.v1.PatchRule.selector:type_name -> harp.bundle.v1.PatchSelector
-	9,  // 5: harp.bundle.v1.PatchRule.package:type_name -> harp.bundle.v1.PatchPackage
-	6,  // 6: harp.bundle.v1.PatchSelector.matchPath:type_name -> harp.bundle.v1.PatchSelectorMatchPath
-	7,  // 7: harp.bundle.v1.PatchSelector.matchSecret:type_name -> harp.bundle.v1.PatchSelectorMatchSecret
-	8,  // 8: harp.bundle.v1.PatchPackage.path:type_name -> harp.bundle.v1.PatchPackagePath
-	11, // 9: harp.bundle.v1.PatchPackage.annotations:type_name -> harp.bundle.v1.PatchOperation
-	11, // 10: harp.bundle.v1.PatchPackage.labels:type_name -> harp.bundle.v1.PatchOperation
-	10, // 11: harp.bundle.v1.PatchPackage.data:type_name -> harp.bundle.v1.PatchSecret
-	11, // 12: harp.bundle.v1.PatchSecret.annotations:type_name -> harp.bundle.v1.PatchOperation
-	11, // 13: harp.bundle.v1.PatchSecret.labels:type_name -> harp.bundle.v1.PatchOperation
-	11, // 14: harp.bundle.v1.PatchSecret.kv:type_name -> harp.bundle.v1.PatchOperation
-	12, // 15: harp.bundle.v1.PatchOperation.add:type_name -> harp.bundle.v1.PatchOperation.AddEntry
-	13, // 16: harp.bundle.v1.PatchOperation.update:type_name -> harp.bundle.v1.PatchOperation.UpdateEntry
-	14, // 17: harp.bundle.v1.PatchOperation.replaceKeys:type_name -> harp.bundle.v1.PatchOperation.ReplaceKeysEntry
-	18, // [18:18] is the sub-list for method output_type
-	18, // [18:18] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	6,  // 3: harp.bundle.v1.PatchSpec.rules:type_name -> harp.bundle.v1.PatchRule
+	20, // 4: harp.bundle.v1.PatchSpec.valuesSchema:type_name -> google.protobuf.Struct
+	4,  // 5: harp.bundle.v1.PatchSpec.preconditions:type_name -> harp.bundle.v1.PatchPreconditions
+	5,  // 6: harp.bundle.v1.PatchPreconditions.fingerprints:type_name -> harp.bundle.v1.PatchSecretFingerprint
+	7,  // 7: harp.bundle.v1.PatchRule.selector:type_name -> harp.bundle.v1.PatchSelector
+	13, // 8: harp.bundle.v1.PatchRule.package:type_name -> harp.bundle.v1.PatchPackage
+	8,  // 9: harp.bundle.v1.PatchSelector.matchPath:type_name -> harp.bundle.v1.PatchSelectorMatchPath
+	9,  // 10: harp.bundle.v1.PatchSelector.matchSecret:type_name -> harp.bundle.v1.PatchSelectorMatchSecret
+	7,  // 11: harp.bundle.v1.PatchSelector.allOf:type_name -> harp.bundle.v1.PatchSelector
+	7,  // 12: harp.bundle.v1.PatchSelector.anyOf:type_name -> harp.bundle.v1.PatchSelector
+	7,  // 13: harp.bundle.v1.PatchSelector.not:type_name -> harp.bundle.v1.PatchSelector
+	10, // 14: harp.bundle.v1.PatchSelector.matchValue:type_name -> harp.bundle.v1.PatchSelectorMatchValue
+	11, // 15: harp.bundle.v1.PatchSelectorMatchValue.hash:type_name -> harp.bundle.v1.PatchSelectorMatchValueHash
+	12, // 16: harp.bundle.v1.PatchPackage.path:type_name -> harp.bundle.v1.PatchPackagePath
+	16, // 17: harp.bundle.v1.PatchPackage.annotations:type_name -> harp.bundle.v1.PatchOperation
+	16, // 18: harp.bundle.v1.PatchPackage.labels:type_name -> harp.bundle.v1.PatchOperation
+	15, // 19: harp.bundle.v1.PatchPackage.data:type_name -> harp.bundle.v1.PatchSecret
+	14, // 20: harp.bundle.v1.PatchPackage.copy:type_name -> harp.bundle.v1.PatchPackageTarget
+	14, // 21: harp.bundle.v1.PatchPackage.move:type_name -> harp.bundle.v1.PatchPackageTarget
+	14, // 22: harp.bundle.v1.PatchPackage.mergeInto:type_name -> harp.bundle.v1.PatchPackageTarget
+	16, // 23: harp.bundle.v1.PatchSecret.annotations:type_name -> harp.bundle.v1.PatchOperation
+	16, // 24: harp.bundle.v1.PatchSecret.labels:type_name -> harp.bundle.v1.PatchOperation
+	16, // 25: harp.bundle.v1.PatchSecret.kv:type_name -> harp.bundle.v1.PatchOperation
+	17, // 26: harp.bundle.v1.PatchOperation.add:type_name -> harp.bundle.v1.PatchOperation.AddEntry
+	18, // 27: harp.bundle.v1.PatchOperation.update:type_name -> harp.bundle.v1.PatchOperation.UpdateEntry
+	19, // 28: harp.bundle.v1.PatchOperation.replaceKeys:type_name -> harp.bundle.v1.PatchOperation.ReplaceKeysEntry
+	29, // [29:29] is the sub-list for method output_type
+	29, // [29:29] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_harp_bundle_v1_patch_proto_init() }
@@ -1086,7 +1703,7 @@ func file_harp_bundle_v1_patch_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_harp_bundle_v1_patch_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -234,7 +234,7 @@ type Rule struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// OPTIONAL. Rule description.
 	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	// REQUIRED. Rule path matcher filter.
+	// REQUIRED for package scoped rules. Rule path matcher filter.
 	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
 	// OPTIONAL. CEL Constraint collection.
 	Constraints []string `protobuf:"bytes,4,rep,name=constraints,proto3" json:"constraints,omitempty"`
@@ -242,6 +242,18 @@ type Rule struct {
 	Rego string `protobuf:"bytes,5,opt,name=rego,proto3" json:"rego,omitempty"`
 	// OPTIONAL. Rego policy file.
 	RegoFile string `protobuf:"bytes,6,opt,name=rego_file,json=regoFile,proto3" json:"rego_file,omitempty"`
+	// OPTIONAL. Rule severity (error, warning, info). Default to error.
+	Severity string `protobuf:"bytes,7,opt,name=severity,proto3" json:"severity,omitempty"`
+	// OPTIONAL. Rule scope (package, bundle). Default to package. Bundle scoped
+	// rules are evaluated once with the complete bundle and ignore the path.
+	Scope string `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`
+	// OPTIONAL. Rego query evaluated by the policy. Default to
+	// `data.harp.compliant`.
+	RegoQuery string `protobuf:"bytes,9,opt,name=rego_query,json=regoQuery,proto3" json:"rego_query,omitempty"`
+	// OPTIONAL. Rego policy paths (files, directories or OPA bundle archives).
+	RegoPaths []string `protobuf:"bytes,10,rep,name=rego_paths,json=regoPaths,proto3" json:"rego_paths,omitempty"`
+	// OPTIONAL. Data document files (JSON or YAML) exposed to Rego policies.
+	RegoData []string `protobuf:"bytes,11,rep,name=rego_data,json=regoData,proto3" json:"rego_data,omitempty"`
 }
 
 func (x *Rule) Reset() {
@@ -316,6 +328,534 @@ func (x *Rule) GetRegoFile() string {
 	return ""
 }
 
+func (x *Rule) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Rule) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Rule) GetRegoQuery() string {
+	if x != nil {
+		return x.RegoQuery
+	}
+	return ""
+}
+
+func (x *Rule) GetRegoPaths() []string {
+	if x != nil {
+		return x.RegoPaths
+	}
+	return nil
+}
+
+func (x *Rule) GetRegoData() []string {
+	if x != nil {
+		return x.RegoData
+	}
+	return nil
+}
+
+// RuleSetTest represents ruleset unit test definition.
+type RuleSetTest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Default to ""
+	ApiVersion string `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	// Default to "RuleSetTest"
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// RuleSetTest metadata
+	Meta *RuleSetTestMeta `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	// RuleSetTest specification
+	Spec *RuleSetTestSpec `protobuf:"bytes,4,opt,name=spec,proto3" json:"spec,omitempty"`
+}
+
+func (x *RuleSetTest) Reset() {
+	*x = RuleSetTest{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTest) ProtoMessage() {}
+
+func (x *RuleSetTest) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTest.ProtoReflect.Descriptor instead.
+func (*RuleSetTest) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RuleSetTest) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *RuleSetTest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RuleSetTest) GetMeta() *RuleSetTestMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *RuleSetTest) GetSpec() *RuleSetTestSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+// RuleSetTestMeta handles ruleset test metadata.
+type RuleSetTestMeta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. Test suite name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// OPTIONAL. Short description for the test suite.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+}
+
+func (x *RuleSetTestMeta) Reset() {
+	*x = RuleSetTestMeta{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestMeta) ProtoMessage() {}
+
+func (x *RuleSetTestMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestMeta.ProtoReflect.Descriptor instead.
+func (*RuleSetTestMeta) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RuleSetTestMeta) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleSetTestMeta) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// RuleSetTestSpec represents ruleset test specification holder.
+type RuleSetTestSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. RuleSet file path, relative to the test file.
+	RuleSet string `protobuf:"bytes,1,opt,name=rule_set,json=ruleSet,proto3" json:"rule_set,omitempty"`
+	// REQUIRED. Test case collection.
+	Cases []*RuleSetTestCase `protobuf:"bytes,2,rep,name=cases,proto3" json:"cases,omitempty"`
+}
+
+func (x *RuleSetTestSpec) Reset() {
+	*x = RuleSetTestSpec{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestSpec) ProtoMessage() {}
+
+func (x *RuleSetTestSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestSpec.ProtoReflect.Descriptor instead.
+func (*RuleSetTestSpec) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RuleSetTestSpec) GetRuleSet() string {
+	if x != nil {
+		return x.RuleSet
+	}
+	return ""
+}
+
+func (x *RuleSetTestSpec) GetCases() []*RuleSetTestCase {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+// RuleSetTestCase represents a ruleset evaluation with an input fixture and
+// the expected outcomes.
+type RuleSetTestCase struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. Test case name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// OPTIONAL. Test case description.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// OPTIONAL. Names of the rules under test. Default to all rules.
+	Rules []string `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	// REQUIRED. Input fixture.
+	Input *RuleSetTestInput `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`
+	// REQUIRED. Expected outcomes.
+	Expect []*RuleSetTestExpectation `protobuf:"bytes,5,rep,name=expect,proto3" json:"expect,omitempty"`
+}
+
+func (x *RuleSetTestCase) Reset() {
+	*x = RuleSetTestCase{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestCase) ProtoMessage() {}
+
+func (x *RuleSetTestCase) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestCase.ProtoReflect.Descriptor instead.
+func (*RuleSetTestCase) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RuleSetTestCase) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleSetTestCase) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RuleSetTestCase) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *RuleSetTestCase) GetInput() *RuleSetTestInput {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *RuleSetTestCase) GetExpect() []*RuleSetTestExpectation {
+	if x != nil {
+		return x.Expect
+	}
+	return nil
+}
+
+// RuleSetTestInput represents the bundle evaluated by a test case.
+type RuleSetTestInput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// OPTIONAL. Bundle file path (container or JSON dump), relative to the test
+	// file.
+	BundleFile string `protobuf:"bytes,1,opt,name=bundle_file,json=bundleFile,proto3" json:"bundle_file,omitempty"`
+	// OPTIONAL. Bundle labels.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OPTIONAL. Bundle annotations.
+	Annotations map[string]string `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OPTIONAL. Inline packages, appended to the bundle file packages.
+	Packages []*RuleSetTestPackage `protobuf:"bytes,4,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *RuleSetTestInput) Reset() {
+	*x = RuleSetTestInput{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestInput) ProtoMessage() {}
+
+func (x *RuleSetTestInput) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestInput.ProtoReflect.Descriptor instead.
+func (*RuleSetTestInput) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RuleSetTestInput) GetBundleFile() string {
+	if x != nil {
+		return x.BundleFile
+	}
+	return ""
+}
+
+func (x *RuleSetTestInput) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *RuleSetTestInput) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *RuleSetTestInput) GetPackages() []*RuleSetTestPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+// RuleSetTestPackage represents an inline package fixture.
+type RuleSetTestPackage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. Package name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// OPTIONAL. Package labels.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OPTIONAL. Package annotations.
+	Annotations map[string]string `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// OPTIONAL. Package secrets.
+	Secrets map[string]string `protobuf:"bytes,4,rep,name=secrets,proto3" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *RuleSetTestPackage) Reset() {
+	*x = RuleSetTestPackage{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestPackage) ProtoMessage() {}
+
+func (x *RuleSetTestPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestPackage.ProtoReflect.Descriptor instead.
+func (*RuleSetTestPackage) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RuleSetTestPackage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleSetTestPackage) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *RuleSetTestPackage) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *RuleSetTestPackage) GetSecrets() map[string]string {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+// RuleSetTestExpectation represents the expected outcome of a rule.
+type RuleSetTestExpectation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// REQUIRED. Rule name.
+	Rule string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// OPTIONAL. Package name. Default to all packages evaluated by the rule.
+	Package string `protobuf:"bytes,2,opt,name=package,proto3" json:"package,omitempty"`
+	// REQUIRED. Expected outcome (pass, fail).
+	Outcome string `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// OPTIONAL. Expected violation messages (substring match).
+	Messages []string `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"`
+}
+
+func (x *RuleSetTestExpectation) Reset() {
+	*x = RuleSetTestExpectation{}
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSetTestExpectation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSetTestExpectation) ProtoMessage() {}
+
+func (x *RuleSetTestExpectation) ProtoReflect() protoreflect.Message {
+	mi := &file_harp_bundle_v1_ruleset_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSetTestExpectation.ProtoReflect.Descriptor instead.
+func (*RuleSetTestExpectation) Descriptor() ([]byte, []int) {
+	return file_harp_bundle_v1_ruleset_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RuleSetTestExpectation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *RuleSetTestExpectation) GetPackage() string {
+	if x != nil {
+		return x.Package
+	}
+	return ""
+}
+
+func (x *RuleSetTestExpectation) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *RuleSetTestExpectation) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
 var File_harp_bundle_v1_ruleset_proto protoreflect.FileDescriptor
 
 var file_harp_bundle_v1_ruleset_proto_rawDesc = []byte{
@@ -341,7 +881,7 @@ var file_harp_bundle_v1_ruleset_proto_rawDesc = []byte{
 	0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x53, 0x70, 0x65, 0x63, 0x12, 0x2a, 0x0a, 0x05, 0x72,
 	0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x61, 0x72,
 	0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65,
-	0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65,
+	0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xb0, 0x02, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65,
 	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
 	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
 	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
@@ -351,18 +891,123 @@ var file_harp_bundle_v1_ruleset_proto_rawDesc = []byte{
 	0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04,
 	0x72, 0x65, 0x67, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x65, 0x67, 0x6f,
 	0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x42, 0xa0, 0x01,
-	0x0a, 0x2a, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x65, 0x6c, 0x61,
-	0x73, 0x74, 0x69, 0x63, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x73, 0x65, 0x63, 0x2e, 0x68, 0x61,
-	0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x0c, 0x52, 0x75,
-	0x6c, 0x65, 0x53, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3a, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x61, 0x73, 0x74, 0x69, 0x63,
-	0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f,
-	0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x3b,
-	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x42, 0x58, 0xaa, 0x02,
-	0x0e, 0x48, 0x61, 0x72, 0x70, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x56, 0x31, 0xca,
-	0x02, 0x0e, 0x48, 0x61, 0x72, 0x70, 0x5c, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5c, 0x56, 0x31,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x67, 0x6f, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x65, 0x67, 0x6f, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0a, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x67, 0x6f, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x65, 0x67, 0x6f, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x72, 0x65, 0x67, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x22, 0xac, 0x01, 0x0a, 0x0b, 0x52,
+	0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70,
+	0x69, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12,
+	0x33, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04,
+	0x6d, 0x65, 0x74, 0x61, 0x12, 0x33, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x53,
+	0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0x47, 0x0a, 0x0f, 0x52, 0x75, 0x6c,
+	0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x63, 0x0a, 0x0f, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73,
+	0x74, 0x53, 0x70, 0x65, 0x63, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x73, 0x65,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74,
+	0x12, 0x35, 0x0a, 0x05, 0x63, 0x61, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x43, 0x61, 0x73, 0x65,
+	0x52, 0x05, 0x63, 0x61, 0x73, 0x65, 0x73, 0x22, 0xd5, 0x01, 0x0a, 0x0f, 0x52, 0x75, 0x6c, 0x65,
+	0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x43, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54,
+	0x65, 0x73, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12,
+	0x3e, 0x0a, 0x06, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x45, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x22,
+	0x89, 0x03, 0x0a, 0x10, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x44, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e,
+	0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65,
+	0x73, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x53, 0x0a, 0x0b, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x31, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x3e, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x41,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc9, 0x03, 0x0a, 0x12,
+	0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x46, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54,
+	0x65, 0x73, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x55,
+	0x0a, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54, 0x65, 0x73, 0x74,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x49, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x54,
+	0x65, 0x73, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x41,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x7c, 0x0a, 0x16, 0x52, 0x75, 0x6c, 0x65, 0x53,
+	0x65, 0x74, 0x54, 0x65, 0x73, 0x74, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0xa0, 0x01, 0x0a, 0x2a, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x65, 0x6c, 0x61, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x73, 0x65, 0x63, 0x2e, 0x68, 0x61, 0x72, 0x70, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x42, 0x0c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x6c, 0x61, 0x73, 0x74, 0x69, 0x63, 0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x68, 0x61, 0x72, 0x70, 0x2f, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x76, 0x31,
+	0xa2, 0x02, 0x03, 0x53, 0x42, 0x58, 0xaa, 0x02, 0x0e, 0x48, 0x61, 0x72, 0x70, 0x2e, 0x42, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x0e, 0x48, 0x61, 0x72, 0x70, 0x5c, 0x42,
+	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -378,23 +1023,46 @@ func file_harp_bundle_v1_ruleset_proto_rawDescGZIP() []byte {
 }
 
 var (
-	file_harp_bundle_v1_ruleset_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+	file_harp_bundle_v1_ruleset_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 	file_harp_bundle_v1_ruleset_proto_goTypes  = []any{
-		(*RuleSet)(nil),     // 0: harp.bundle.v1.RuleSet
-		(*RuleSetMeta)(nil), // 1: harp.bundle.v1.RuleSetMeta
-		(*RuleSetSpec)(nil), // 2: harp.bundle.v1.RuleSetSpec
-		(*Rule)(nil),        // 3: harp.bundle.v1.Rule
+		(*RuleSet)(nil),                // 0: harp.bundle.v1.RuleSet
+		(*RuleSetMeta)(nil),            // 1: harp.bundle.v1.RuleSetMeta
+		(*RuleSetSpec)(nil),            // 2: harp.bundle.v1.RuleSetSpec
+		(*Rule)(nil),                   // 3: harp.bundle.v1.Rule
+		(*RuleSetTest)(nil),            // 4: harp.bundle.v1.RuleSetTest
+		(*RuleSetTestMeta)(nil),        // 5: harp.bundle.v1.RuleSetTestMeta
+		(*RuleSetTestSpec)(nil),        // 6: harp.bundle.v1.RuleSetTestSpec
+		(*RuleSetTestCase)(nil),        // 7: harp.bundle.v1.RuleSetTestCase
+		(*RuleSetTestInput)(nil),       // 8: harp.bundle.v1.RuleSetTestInput
+		(*RuleSetTestPackage)(nil),     // 9: harp.bundle.v1.RuleSetTestPackage
+		(*RuleSetTestExpectation)(nil), // 10: harp.bundle.v1.RuleSetTestExpectation
+		nil,                            // 11: harp.bundle.v1.RuleSetTestInput.LabelsEntry
+		nil,                            // 12: harp.bundle.v1.RuleSetTestInput.AnnotationsEntry
+		nil,                            // 13: harp.bundle.v1.RuleSetTestPackage.LabelsEntry
+		nil,                            // 14: harp.bundle.v1.RuleSetTestPackage.AnnotationsEntry
+		nil,                            // 15: harp.bundle.v1.RuleSetTestPackage.SecretsEntry
 	}
 )
 var file_harp_bundle_v1_ruleset_proto_depIdxs = []int32{
-	1, // 0: harp.bundle.v1.RuleSet.meta:type_name -> harp.bundle.v1.RuleSetMeta
-	2, // 1: harp.bundle.v1.RuleSet.spec:type_name -> harp.bundle.v1.RuleSetSpec
-	3, // 2: harp.bundle.v1.RuleSetSpec.rules:type_name -> harp.bundle.v1.Rule
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	1,  // 0: harp.bundle.v1.RuleSet.meta:type_name -> harp.bundle.v1.RuleSetMeta
+	2,  // 1: harp.bundle.v1.RuleSet.spec:type_name -> harp.bundle.v1.RuleSetSpec
+	3,  // 2: harp.bundle.v1.RuleSetSpec.rules:type_name -> harp.bundle.v1.Rule
+	5,  // 3: harp.bundle.v1.RuleSetTest.meta:type_name -> harp.bundle.v1.RuleSetTestMeta
+	6,  // 4: harp.bundle.v1.RuleSetTest.spec:type_name -> harp.bundle.v1.RuleSetTestSpec
+	7,  // 5: harp.bundle.v1.RuleSetTestSpec.cases:type_name -> harp.bundle.v1.RuleSetTestCase
+	8,  // 6: harp.bundle.v1.RuleSetTestCase.input:type_name -> harp.bundle.v1.RuleSetTestInput
+	10, // 7: harp.bundle.v1.RuleSetTestCase.expect:type_name -> harp.bundle.v1.RuleSetTestExpectation
+	11, // 8: harp.bundle.v1.RuleSetTestInput.labels:type_name -> harp.bundle.v1.RuleSetTestInput.LabelsEntry
+	12, // 9: harp.bundle.v1.RuleSetTestInput.annotations:type_name -> harp.bundle.v1.RuleSetTestInput.AnnotationsEntry
+	9,  // 10: harp.bundle.v1.RuleSetTestInput.packages:type_name -> harp.bundle.v1.RuleSetTestPackage
+	13, // 11: harp.bundle.v1.RuleSetTestPackage.labels:type_name -> harp.bundle.v1.RuleSetTestPackage.LabelsEntry
+	14, // 12: harp.bundle.v1.RuleSetTestPackage.annotations:type_name -> harp.bundle.v1.RuleSetTestPackage.AnnotationsEntry
+	15, // 13: harp.bundle.v1.RuleSetTestPackage.secrets:type_name -> harp.bundle.v1.RuleSetTestPackage.SecretsEntry
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_harp_bundle_v1_ruleset_proto_init() }
@@ -408,7 +1076,7 @@ func file_harp_bundle_v1_ruleset_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_harp_bundle_v1_ruleset_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
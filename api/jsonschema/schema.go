@@ -45,6 +45,14 @@ func BundleV1RuleSetSchema() []byte {
 	return bundleV1RuleSetSchemaDefinition
 }
 
+//go:embed harp.bundle.v1/RuleSetTest.json
+var bundleV1RuleSetTestSchemaDefinition []byte
+
+// BundleV1RuleSetTestSchema returns the `harp.bundle.v1.RuleSetTest` jsonschema content.
+func BundleV1RuleSetTestSchema() []byte {
+	return bundleV1RuleSetTestSchemaDefinition
+}
+
 //go:embed harp.bundle.v1/Template.json
 var bundleV1TemplateSchemaDefinition []byte
 
@@ -37,6 +37,7 @@ var bundleCmd = func() *cobra.Command {
 	cmd.AddCommand(bundleDecryptCmd())
 	cmd.AddCommand(bundleDiffCmd())
 	cmd.AddCommand(bundlePatchCmd())
+	cmd.AddCommand(bundleJSONPatchCmd())
 	cmd.AddCommand(bundleFilterCmd())
 	cmd.AddCommand(bundleLintCmd())
 	cmd.AddCommand(bundlePrefixerCmd())
@@ -31,6 +31,9 @@ type bundleDiffParams struct {
 	sourcePath      string
 	destinationPath string
 	generatePatch   bool
+	preconditions   bool
+	fingerprintKey  string
+	format          string
 	outputPath      string
 }
 
@@ -48,7 +51,16 @@ var bundleDiffCmd = func() *cobra.Command {
 	harp bundle diff --old - --new rotated.bundle
 
 	# Generate a BundlePatch from differences
-	harp bundle diff --old - --new rotated.bundle --patch --out rotation.yaml`)
+	harp bundle diff --old - --new rotated.bundle --patch --out rotation.yaml
+
+	# Generate a RFC 6902 JSON Patch from differences
+	harp bundle diff --old - --new rotated.bundle --format jsonpatch
+
+	# Generate a BundlePatch applicable only on the old bundle
+	harp bundle diff --old - --new rotated.bundle --patch --with-preconditions --out rotation.yaml
+
+	# Generate a BundlePatch also bound to the old values of altered secrets
+	harp bundle diff --old - --new rotated.bundle --patch --with-preconditions --fingerprint-key $FINGERPRINT_KEY --out rotation.yaml`)
 
 	cmd := &cobra.Command{
 		Use:     "diff",
@@ -66,6 +78,9 @@ var bundleDiffCmd = func() *cobra.Command {
 				DestinationReader: cmdutil.FileReader(params.destinationPath),
 				OutputWriter:      cmdutil.FileWriter(params.outputPath),
 				GeneratePatch:     params.generatePatch,
+				WithPreconditions: params.preconditions,
+				FingerprintKey:    params.fingerprintKey,
+				Format:            params.format,
 			}
 
 			// Run the task
@@ -82,6 +97,9 @@ var bundleDiffCmd = func() *cobra.Command {
 	log.CheckErr("unable to mark 'new' flag as required.", cmd.MarkFlagRequired("new"))
 	cmd.Flags().StringVar(&params.outputPath, "out", "-", "Output ('-' for stdout or filename)")
 	cmd.Flags().BoolVar(&params.generatePatch, "patch", false, "Output as a bundle patch")
+	cmd.Flags().StringVar(&params.format, "format", "oplog", "Output format (oplog, jsonpatch, mergepatch)")
+	cmd.Flags().BoolVar(&params.preconditions, "with-preconditions", false, "Bind the generated patch to the old bundle state")
+	cmd.Flags().StringVar(&params.fingerprintKey, "fingerprint-key", "", "Secret fingerprint key used to bind the generated patch to the altered secret values")
 
 	return cmd
 }
@@ -34,6 +34,7 @@ type bundleDumpParams struct {
 	pathOnly       bool
 	jmesPathFilter string
 	skipTemplate   bool
+	selectorPath   string
 }
 
 var bundleDumpCmd = func() *cobra.Command {
@@ -66,6 +67,12 @@ var bundleDumpCmd = func() *cobra.Command {
 	# Dump a Bundle using a JMEFilter query
 	harp bundle dump --query <jmesfilter query>
 
+	# Dump only packages matching a BundlePatch selector file
+	harp bundle dump --selector selector.yaml --path-only
+
+	# Query only packages matching a secret value selector
+	harp bundle dump --selector rsa-2048.yaml --query "[].name"
+
 	# Dump a bundle content excluding the template used to generate
 	harp bundle dump --skip-template`)
 
@@ -90,6 +97,11 @@ var bundleDumpCmd = func() *cobra.Command {
 				IgnoreTemplate:  params.skipTemplate,
 			}
 
+			// Use selector file if specified
+			if params.selectorPath != "" {
+				t.SelectorReader = cmdutil.FileReader(params.selectorPath)
+			}
+
 			// Run the task
 			if err := t.Run(ctx); err != nil {
 				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
@@ -104,6 +116,7 @@ var bundleDumpCmd = func() *cobra.Command {
 	cmd.Flags().BoolVar(&params.metadataOnly, "metadata-only", false, "Display metadata only")
 	cmd.Flags().BoolVar(&params.pathOnly, "path-only", false, "Display path only")
 	cmd.Flags().StringVar(&params.jmesPathFilter, "query", "", "Specify a JMESPath query to format output")
+	cmd.Flags().StringVar(&params.selectorPath, "selector", "", "BundlePatch selector file used to restrict dumped packages")
 	cmd.Flags().BoolVar(&params.skipTemplate, "skip-template", false, "Drop template from dump")
 
 	return cmd
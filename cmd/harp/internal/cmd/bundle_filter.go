@@ -35,6 +35,7 @@ type bundleFilterParams struct {
 	jmesPath       string
 	regoPolicy     string
 	celExpressions []string
+	selectorPath   string
 	reverseLogic   bool
 }
 
@@ -53,6 +54,7 @@ var bundleFilterCmd = func() *cobra.Command {
 	* a JMES query
 	* a REGO policy
 	* a Set of CEL expressions
+	* a BundlePatch selector file (combinators allowed)
 
 	Bundle package filtering capabilities are the root of the secret management
 	by contract. Filter commands can be pipelined to produce complex filtering
@@ -82,6 +84,12 @@ var bundleFilterCmd = func() *cobra.Command {
 	# Filter packages using a CEL matcher expressions (associated with AND logic if multiple)
 	harp bundle filter --cel "p.match_secret('*Key')"
 
+	# Filter packages using a BundlePatch selector file
+	harp bundle filter --selector selector.yaml
+
+	# Keep packages holding RSA-2048 keys (selector with matchValue)
+	harp bundle filter --selector rsa-2048.yaml
+
 	# Reverse the matcher logic
 	harp bundle filter --not <matcher>`)
 
@@ -108,6 +116,11 @@ var bundleFilterCmd = func() *cobra.Command {
 				ReverseLogic:    params.reverseLogic,
 			}
 
+			// Use selector file if specified
+			if params.selectorPath != "" {
+				t.SelectorReader = cmdutil.FileReader(params.selectorPath)
+			}
+
 			// Run the task
 			if err := t.Run(ctx); err != nil {
 				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
@@ -123,6 +136,7 @@ var bundleFilterCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.jmesPath, "query", "", "JMESPath query used as package filter")
 	cmd.Flags().StringVar(&params.regoPolicy, "policy", "", "OPA Rego policy file as package filter")
 	cmd.Flags().StringArrayVar(&params.celExpressions, "cel", []string{}, "CEL expression as package filter (multiple)")
+	cmd.Flags().StringVar(&params.selectorPath, "selector", "", "BundlePatch selector file as package filter")
 	cmd.Flags().BoolVar(&params.reverseLogic, "not", false, "Reverse filter logic expression")
 
 	return cmd
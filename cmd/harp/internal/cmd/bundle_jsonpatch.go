@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/tasks/bundle"
+)
+
+// -----------------------------------------------------------------------------
+type bundleJSONPatchParams struct {
+	inputPath  string
+	outputPath string
+	patchPath  string
+	merge      bool
+}
+
+var bundleJSONPatchCmd = func() *cobra.Command {
+	params := &bundleJSONPatchParams{}
+
+	longDesc := cmdutil.LongDesc(`
+	Apply a JSON Patch document to a bundle.
+
+	The document targets the bundle map representation, where each package path
+	is a member of the root object holding the secret key/value map. RFC 6902
+	JSON Patch and RFC 7386 JSON Merge Patch documents are supported. Bundle and
+	package metadata are preserved.
+	`)
+
+	examples := cmdutil.Examples(`
+	# Apply a JSON Patch document generated by bundle diff
+	harp bundle diff --old v1.bundle --new v2.bundle --format jsonpatch > v2.json
+	harp bundle jsonpatch --in v1.bundle --spec v2.json --out v2.bundle
+
+	# Apply a JSON Merge Patch document
+	echo '{"app/production/db":{"password":"changeme"}}' | harp bundle jsonpatch --in v1.bundle --spec - --merge --out v2.bundle`)
+
+	cmd := &cobra.Command{
+		Use:     "jsonpatch",
+		Short:   "Apply a JSON Patch document to a bundle",
+		Long:    longDesc,
+		Example: examples,
+		Run: func(cmd *cobra.Command, _ []string) {
+			// Initialize logger and context
+			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-bundle-jsonpatch", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Prepare task
+			t := &bundle.JSONPatchTask{
+				PatchReader:     cmdutil.FileReader(params.patchPath),
+				ContainerReader: cmdutil.FileReader(params.inputPath),
+				OutputWriter:    cmdutil.FileWriter(params.outputPath),
+				MergePatch:      params.merge,
+			}
+
+			// Run the task
+			if err := t.Run(ctx); err != nil {
+				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
+			}
+		},
+	}
+
+	// Parameters
+	cmd.Flags().StringVar(&params.inputPath, "in", "-", "Container input ('-' for stdin or filename)")
+	cmd.Flags().StringVar(&params.outputPath, "out", "", "Container output ('-' for stdout or a filename)")
+	cmd.Flags().StringVar(&params.patchPath, "spec", "", "JSON Patch document path ('-' for stdin or filename)")
+	log.CheckErr("unable to mark 'spec' flag as required.", cmd.MarkFlagRequired("spec"))
+	cmd.Flags().BoolVar(&params.merge, "merge", false, "Process the document as a RFC 7386 JSON Merge Patch")
+
+	return cmd
+}
@@ -28,8 +28,10 @@ import (
 
 // -----------------------------------------------------------------------------
 type bundleLintParams struct {
-	inputPath string
-	specPath  string
+	inputPath    string
+	specPath     string
+	outputPath   string
+	reportFormat string
 }
 
 var bundleLintCmd = func() *cobra.Command {
@@ -40,11 +42,31 @@ var bundleLintCmd = func() *cobra.Command {
 
 	This command is used to check a Bundle structure (Package => Secrets).
 	A control gate could be implemented with this command to enforce a bundle
-	structure by decoupling the bundle content and the usage contract.`)
+	structure by decoupling the bundle content and the usage contract.
+
+	By default, the evaluation stops at the first violation. When a report format
+	is specified, all rules are evaluated and every violation is reported with
+	the rule name, package path and failing constraint.
+
+	Supported report formats are text, json, sarif (for code-scanning UIs) and
+	junit (for CI test reports).
+
+	Only error severity violations fail the evaluation. Packages can be exempted
+	from a rule until a given date with the harp.elastic.co/v1/ruleset#exempt
+	annotation.`)
 
 	examples := cmdutil.Examples(`
 	# Lint a bundle from STDIN
-	harp bundle lint --spec cso.yaml`)
+	harp bundle lint --spec cso.yaml
+
+	# Report all violations as text
+	harp bundle lint --in customer.bundle --spec cso.yaml --format text
+
+	# Generate a SARIF report for code-scanning
+	harp bundle lint --in customer.bundle --spec cso.yaml --format sarif --out lint.sarif
+
+	# Generate a JUnit report for CI
+	harp bundle lint --in customer.bundle --spec cso.yaml --format junit --out lint.xml`)
 
 	cmd := &cobra.Command{
 		Use:     "lint",
@@ -60,6 +82,10 @@ var bundleLintCmd = func() *cobra.Command {
 			t := &bundle.LintTask{
 				ContainerReader: cmdutil.FileReader(params.inputPath),
 				RuleSetReader:   cmdutil.FileReader(params.specPath),
+				OutputWriter:    cmdutil.FileWriter(params.outputPath),
+				ReportFormat:    params.reportFormat,
+				ContainerPath:   params.inputPath,
+				RuleSetPath:     params.specPath,
 			}
 
 			// Run the task
@@ -72,6 +98,8 @@ var bundleLintCmd = func() *cobra.Command {
 	// Parameters
 	cmd.Flags().StringVar(&params.inputPath, "in", "-", "Container input ('-' for stdin or filename)")
 	cmd.Flags().StringVar(&params.specPath, "spec", "", "RuleSet specification path ('-' for stdin or filename)")
+	cmd.Flags().StringVar(&params.outputPath, "out", "-", "Report output ('-' for stdout or filename)")
+	cmd.Flags().StringVar(&params.reportFormat, "format", "", "Report format (text, json, sarif, junit), collects all violations when specified")
 	log.CheckErr("unable to mark 'spec' flag as required.", cmd.MarkFlagRequired("spec"))
 
 	return cmd
@@ -37,6 +37,7 @@ type bundlePatchParams struct {
 	values            []string
 	stringValues      []string
 	fileValues        []string
+	valuesSchema      string
 	stopAtRuleIndex   int
 	stopAtRuleID      string
 	ignoreRuleIDs     []string
@@ -60,6 +61,7 @@ var bundlePatchCmd = func() *cobra.Command {
 				Values:       params.values,
 				StringValues: params.stringValues,
 				FileValues:   params.fileValues,
+				SchemaFile:   params.valuesSchema,
 			}
 			values, err := valueOpts.MergeValues()
 			if err != nil {
@@ -99,6 +101,7 @@ var bundlePatchCmd = func() *cobra.Command {
 	cmd.Flags().StringArrayVar(&params.values, "set", []string{}, "Specifies value (k=v)")
 	cmd.Flags().StringArrayVar(&params.stringValues, "set-string", []string{}, "Specifies value (k=string)")
 	cmd.Flags().StringArrayVar(&params.fileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&params.valuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values")
 	cmd.Flags().StringVar(&params.stopAtRuleID, "stop-at-rule-id", "", "Stop patch evaluation before the given rule ID")
 	cmd.Flags().IntVar(&params.stopAtRuleIndex, "stop-at-rule-index", -1, "Stop patch evaluation before the given rule index (0 for first rule)")
 	cmd.Flags().StringArrayVar(&params.ignoreRuleIDs, "ignore-rule-id", []string{}, "List of Rule identifier to ignore during evaluation")
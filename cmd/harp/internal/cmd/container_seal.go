@@ -42,6 +42,7 @@ type containerSealParams struct {
 	noContainerIdentity bool
 	jsonOutput          bool
 	sealVersion         uint
+	enforceRuleSets     []string
 }
 
 var containerSealCmd = func() *cobra.Command {
@@ -106,6 +107,7 @@ var containerSealCmd = func() *cobra.Command {
 				JSONOutput:            params.jsonOutput,
 				PeerPublicKeys:        sealingPublicKeys,
 				SealVersion:           params.sealVersion,
+				PolicyGate:            ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -131,6 +133,7 @@ var containerSealCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.masterKey, "dckd-master-key", "", "Master key used for deterministic container key derivation")
 	cmd.Flags().StringVar(&params.target, "dckd-target", "", "Target parameter for deterministic container key derivation")
 	cmd.Flags().UintVar(&params.sealVersion, "seal-version", sealVersion, "Select the sealing strategy version (1:modern, 2:fips-compliant)")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	return cmd
 }
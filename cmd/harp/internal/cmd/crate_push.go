@@ -30,13 +30,14 @@ import (
 // -----------------------------------------------------------------------------
 
 type cratePushParams struct {
-	inputPath   string
-	outputPath  string
-	contextPath string
-	to          string
-	ref         string
-	json        bool
-	opts        content.RegistryOptions
+	inputPath       string
+	outputPath      string
+	contextPath     string
+	to              string
+	ref             string
+	json            bool
+	opts            content.RegistryOptions
+	enforceRuleSets []string
 }
 
 var cratePushCmd = func() *cobra.Command {
@@ -66,6 +67,7 @@ var cratePushCmd = func() *cobra.Command {
 				JSONOutput:   params.json,
 				RegistryOpts: params.opts,
 				ContextPath:  params.contextPath,
+				PolicyGate:   ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -87,6 +89,7 @@ var cratePushCmd = func() *cobra.Command {
 	cmd.Flags().BoolVarP(&params.opts.Insecure, "insecure", "", false, "Allow connections to SSL registry without certs")
 	cmd.Flags().BoolVarP(&params.opts.PlainHTTP, "plain-http", "", false, "Use plain http and not https")
 	cmd.Flags().StringVar(&params.contextPath, "root", ".", "Defines the root context path")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	return cmd
 }
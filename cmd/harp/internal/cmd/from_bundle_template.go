@@ -35,13 +35,18 @@ import (
 
 var fromTemplateCmd = func() *cobra.Command {
 	var (
-		inputPath    string
-		outputPath   string
-		rootPath     string
-		valueFiles   []string
-		values       []string
-		stringValues []string
-		fileValues   []string
+		inputPath     string
+		outputPath    string
+		rootPath      string
+		libraryPaths  []string
+		valueFiles    []string
+		secretLoaders []string
+		values        []string
+		stringValues  []string
+		fileValues    []string
+		valuesSchema  string
+		sandbox       templateSandboxParams
+		deterministic templateDeterministicParams
 	)
 
 	cmd := &cobra.Command{
@@ -59,6 +64,7 @@ var fromTemplateCmd = func() *cobra.Command {
 				Values:       values,
 				StringValues: stringValues,
 				FileValues:   fileValues,
+				SchemaFile:   valuesSchema,
 			}
 			values, err := valueOpts.MergeValues()
 			if err != nil {
@@ -79,6 +85,24 @@ var fromTemplateCmd = func() *cobra.Command {
 				}
 			}
 
+			// Load template libraries
+			libraries, err := tplcmdutil.Libraries(libraryPaths)
+			if err != nil {
+				log.For(ctx).Fatal("unable to load template libraries", zap.Error(err))
+			}
+
+			// Load secret readers
+			secretReaders, err := tplcmdutil.SecretReaders(ctx, secretLoaders)
+			if err != nil {
+				log.For(ctx).Fatal("unable to initialize secret readers", zap.Error(err))
+			}
+
+			// Check deterministic mode
+			deterministicMode, err := deterministic.deterministic()
+			if err != nil {
+				log.For(ctx).Fatal("unable to enable deterministic mode", zap.Error(err))
+			}
+
 			// Prepare task
 			t := &from.BundleTemplateTask{
 				TemplateReader: cmdutil.FileReader(inputPath),
@@ -87,6 +111,10 @@ var fromTemplateCmd = func() *cobra.Command {
 					engine.WithName(inputPath),
 					engine.WithValues(values),
 					engine.WithFiles(files),
+					engine.WithLibraries(libraries),
+					engine.WithSecretReaders(secretReaders...),
+					engine.WithSandbox(sandbox.sandbox()),
+					engine.WithDeterministic(deterministicMode),
 				),
 			}
 
@@ -101,10 +129,15 @@ var fromTemplateCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&inputPath, "in", "-", "Template input path ('-' for stdin or filename)")
 	cmd.Flags().StringVar(&outputPath, "out", "", "Container output ('-' for stdout or a filename)")
 	cmd.Flags().StringVar(&rootPath, "root", "", "Defines file loader root base path")
+	cmd.Flags().StringArrayVar(&libraryPaths, "lib", []string{}, "Template library path (directory or archive) loaded before rendering to share named templates (repeatable)")
 	cmd.Flags().StringArrayVarP(&valueFiles, "values", "f", []string{}, "Specifies value files to load")
+	cmd.Flags().StringArrayVarP(&secretLoaders, "secrets-from", "s", []string{}, "Specifies secret loaders in precedence order ('vault', 'consul://', 'etcd3://', 'zk://', 'crate://', 'sealed://' URIs, '-' for stdin or container filename)")
 	cmd.Flags().StringArrayVar(&values, "set", []string{}, "Specifies value (k=v)")
 	cmd.Flags().StringArrayVar(&stringValues, "set-string", []string{}, "Specifies value (k=string)")
 	cmd.Flags().StringArrayVar(&fileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&valuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values")
+	addTemplateSandboxFlags(cmd, &sandbox)
+	addTemplateDeterministicFlags(cmd, &deterministic)
 
 	return cmd
 }
@@ -37,6 +37,7 @@ var keygenCmd = func() *cobra.Command {
 	cmd.AddCommand(keygenAESCmd())
 	cmd.AddCommand(keygenMasterKeyCmd())
 	cmd.AddCommand(keygenKeypairCmd())
+	cmd.AddCommand(keygenSSHCmd())
 
 	if !fips.Enabled() {
 		cmd.AddCommand(keygenSecretBoxCmd())
@@ -45,6 +46,8 @@ var keygenCmd = func() *cobra.Command {
 		cmd.AddCommand(keygenAESPMACSIVCmd())
 		cmd.AddCommand(keygenAESSIVCmd())
 		cmd.AddCommand(keygenPasetoCmd())
+		cmd.AddCommand(keygenAgeCmd())
+		cmd.AddCommand(keygenWireGuardCmd())
 	}
 	return cmd
 }
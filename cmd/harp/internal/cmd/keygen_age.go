@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+)
+
+// -----------------------------------------------------------------------------
+
+var keygenAgeCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "age",
+		Short: "Generate and print an age X25519 identity",
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-keygen-age", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Generate identity
+			pub, priv, err := nativeKeypair("age")
+			if err != nil {
+				log.For(ctx).Fatal("unable to generate age identity", zap.Error(err))
+			}
+
+			// Same layout as age-keygen
+			fmt.Fprintf(os.Stdout, "# created: %s\n# public key: %s\n%s\n", time.Now().Format(time.RFC3339), pub, priv)
+		},
+	}
+
+	return cmd
+}
+
+// nativeKeypair generates a key pair for key types encoded as strings.
+func nativeKeypair(keyType string) (pub, priv string, err error) {
+	kp, err := crypto.Keypair(keyType)
+	if err != nil {
+		return "", "", err
+	}
+
+	pair, ok := kp.(struct {
+		Private interface{}
+		Public  interface{}
+	})
+	if !ok {
+		return "", "", fmt.Errorf("unexpected key pair type %T", kp)
+	}
+
+	pub, okPub := pair.Public.(string)
+	priv, okPriv := pair.Private.(string)
+	if !okPub || !okPriv {
+		return "", "", fmt.Errorf("key type '%s' is not natively encoded", keyType)
+	}
+
+	return pub, priv, nil
+}
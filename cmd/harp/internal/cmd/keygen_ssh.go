@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/tasks/keygen"
+)
+
+// -----------------------------------------------------------------------------
+
+type keygenSSHParams struct {
+	outputPath       string
+	publicOutputPath string
+	keyType          string
+}
+
+var keygenSSHCmd = func() *cobra.Command {
+	params := &keygenSSHParams{}
+
+	cmd := &cobra.Command{
+		Use:   "ssh",
+		Short: "Generate an OpenSSH key pair",
+		Run: func(cmd *cobra.Command, _ []string) {
+			// Initialize logger and context
+			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-keygen-ssh", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Prepare task
+			t := &keygen.OpenSSHTask{
+				KeyType:      params.keyType,
+				OutputWriter: cmdutil.FileWriter(params.outputPath),
+			}
+			if params.publicOutputPath != "" {
+				t.PublicKeyWriter = cmdutil.FileWriter(params.publicOutputPath)
+			}
+
+			// Run the task
+			if err := t.Run(ctx); err != nil {
+				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
+			}
+		},
+	}
+
+	// Add parameters
+	cmd.Flags().StringVar(&params.keyType, "type", "ed25519", "Key type to generate (ed25519, ecdsa, rsa)")
+	cmd.Flags().StringVar(&params.outputPath, "out", "-", "Private key output ('-' for stdout or a filename)")
+	cmd.Flags().StringVar(&params.publicOutputPath, "public-out", "", "Public key output ('-' for stdout or a filename)")
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+)
+
+// -----------------------------------------------------------------------------
+
+var keygenWireGuardCmd = func() *cobra.Command {
+	var preshared bool
+
+	cmd := &cobra.Command{
+		Use:     "wireguard",
+		Aliases: []string{"wg"},
+		Short:   "Generate and print a WireGuard key pair or preshared key",
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-keygen-wireguard", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Preshared key
+			if preshared {
+				psk, err := crypto.Key("wireguard:psk")
+				if err != nil {
+					log.For(ctx).Fatal("unable to generate wireguard preshared key", zap.Error(err))
+				}
+
+				fmt.Fprintf(os.Stdout, "PresharedKey = %s\n", psk)
+				return
+			}
+
+			// Generate key pair
+			pub, priv, err := nativeKeypair("wireguard")
+			if err != nil {
+				log.For(ctx).Fatal("unable to generate wireguard key pair", zap.Error(err))
+			}
+
+			// WireGuard configuration syntax
+			fmt.Fprintf(os.Stdout, "PrivateKey = %s\nPublicKey = %s\n", priv, pub)
+		},
+	}
+
+	// Parameters
+	cmd.Flags().BoolVar(&preshared, "preshared", false, "Generate a preshared key")
+
+	return cmd
+}
@@ -34,11 +34,17 @@ type renderParams struct {
 	Values        []string
 	StringValues  []string
 	FileValues    []string
+	ValuesSchema  string
 	LeftDelims    string
 	RightDelims   string
 	AltDelims     bool
 	RootPath      string
+	LibraryPaths  []string
+	Partials      []string
+	ManifestPath  string
 	DryRun        bool
+	Sandbox       templateSandboxParams
+	Deterministic templateDeterministicParams
 }
 
 // -----------------------------------------------------------------------------
@@ -48,6 +54,10 @@ var renderCmd = func() *cobra.Command {
 
 	longDesc := cmdutil.LongDesc(`
 	Generate a config filesytem from a template hierarchy or archive.
+
+	With a render manifest, only declared templates are rendered to their
+	destinations. Unchanged files are not written, changed files are replaced
+	atomically and the hooks they notify are executed once all files are written.
 	`)
 	examples := cmdutil.Examples(`
 	# Generate a configuration filesystem from a folder hierarchy
@@ -58,6 +68,21 @@ var renderCmd = func() *cobra.Command {
 
 	# Test template generation
 	harp render --in templates.tar.gz --dry-run
+
+	# Validate values before generation
+	harp render --in templates/database --values-schema values.schema.json --values values.yaml --out postgres
+
+	# Share named templates defined in a helper library
+	harp render --in templates/database --lib templates/helpers --out postgres
+
+	# Share named templates defined in partial templates of the input
+	harp render --in templates/database --partials "_*.tpl" --partials "helpers/*" --out postgres
+
+	# Render files declared in a manifest and run hooks when outputs change
+	harp render --in templates/nginx --manifest render.yaml --values values.yaml
+
+	# Render an untrusted template archive with access to a secret subtree only
+	harp render --in third-party.tar.gz --sandbox --sandbox-secret-path "app/production/billing/**" --out config
 	`)
 
 	cmd := &cobra.Command{
@@ -71,6 +96,12 @@ var renderCmd = func() *cobra.Command {
 			ctx, cancel := cmdutil.Context(cmd.Context(), "template-render", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
 			defer cancel()
 
+			// Check deterministic mode
+			deterministic, err := params.Deterministic.deterministic()
+			if err != nil {
+				log.For(ctx).Fatal("unable to enable deterministic mode", zap.Error(err))
+			}
+
 			// Prepare task
 			t := &template.FileSystemTask{
 				InputPath:          params.InputPath,
@@ -79,12 +110,18 @@ var renderCmd = func() *cobra.Command {
 				Values:             params.Values,
 				StringValues:       params.StringValues,
 				FileValues:         params.FileValues,
+				ValuesSchema:       params.ValuesSchema,
 				FileLoaderRootPath: params.RootPath,
+				LibraryPaths:       params.LibraryPaths,
+				PartialPatterns:    params.Partials,
+				ManifestPath:       params.ManifestPath,
 				SecretLoaders:      params.SecretLoaders,
 				LeftDelims:         params.LeftDelims,
 				RightDelims:        params.RightDelims,
 				AltDelims:          params.AltDelims,
 				DryRun:             params.DryRun,
+				Sandbox:            params.Sandbox.sandbox(),
+				Deterministic:      deterministic,
 			}
 
 			// Run the task
@@ -99,15 +136,21 @@ var renderCmd = func() *cobra.Command {
 	log.CheckErr("unable to mark 'in' flag as required.", cmd.MarkFlagRequired("in"))
 	cmd.Flags().StringVar(&params.OutputPath, "out", "", "Output path")
 	cmd.Flags().StringVar(&params.RootPath, "root", "", "Defines file loader root base path")
-	cmd.Flags().StringArrayVarP(&params.SecretLoaders, "secrets-from", "s", []string{"vault"}, "Specifies secret containers to load ('vault' for Vault loader or '-' for stdin or filename)")
+	cmd.Flags().StringArrayVar(&params.LibraryPaths, "lib", []string{}, "Template library path (directory or archive) loaded before rendering to share named templates (repeatable)")
+	cmd.Flags().StringArrayVar(&params.Partials, "partials", []string{}, "Partial template glob, matching files are not rendered and share their named templates (repeatable)")
+	cmd.Flags().StringArrayVarP(&params.SecretLoaders, "secrets-from", "s", []string{"vault"}, "Specifies secret loaders in precedence order ('vault', 'consul://', 'etcd3://', 'zk://', 'crate://', 'sealed://' URIs, '-' for stdin or container filename)")
 	cmd.Flags().StringArrayVarP(&params.ValueFiles, "values", "f", []string{}, "Specifies value files to load")
 	cmd.Flags().StringArrayVar(&params.Values, "set", []string{}, "Specifies value (k=v)")
 	cmd.Flags().StringArrayVar(&params.StringValues, "set-string", []string{}, "Specifies value (k=string)")
 	cmd.Flags().StringArrayVar(&params.FileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&params.ValuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values (default to 'values.schema.json' from input if exists)")
 	cmd.Flags().StringVar(&params.LeftDelims, "left-delimiter", "{{", "Template left delimiter (default to '{{')")
 	cmd.Flags().StringVar(&params.RightDelims, "right-delimiter", "}}", "Template right delimiter (default to '}}')")
 	cmd.Flags().BoolVar(&params.AltDelims, "alt-delims", false, "Define '[[' and ']]' as template delimiters.")
+	cmd.Flags().StringVar(&params.ManifestPath, "manifest", "", "Render manifest path mapping templates to destinations with file attributes and post-render hooks")
 	cmd.Flags().BoolVar(&params.DryRun, "dry-run", false, "Generate in-memory only.")
+	addTemplateSandboxFlags(cmd, &params.Sandbox)
+	addTemplateDeterministicFlags(cmd, &params.Deterministic)
 
 	return cmd
 }
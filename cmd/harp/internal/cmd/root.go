@@ -56,6 +56,7 @@ var mainCmd = func() *cobra.Command {
 	cmd.AddCommand(configcmd.NewConfigCommand(conf, "HARP"))
 
 	cmd.AddCommand(bundleCmd())
+	cmd.AddCommand(rulesetCmd())
 	cmd.AddCommand(containerCmd())
 	cmd.AddCommand(crateCmd())
 	cmd.AddCommand(keygenCmd())
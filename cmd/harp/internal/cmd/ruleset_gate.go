@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+// addEnforceRuleSetFlag registers the shared publication policy gate flag.
+func addEnforceRuleSetFlag(cmd *cobra.Command, paths *[]string) {
+	cmd.Flags().StringArrayVar(paths, "enforce-ruleset", []string{}, "RuleSet evaluated against the bundle before publication (repeatable)")
+}
+
+// ruleSetGate builds the publication policy gate from the given ruleset paths.
+// The violation report is written to stderr.
+func ruleSetGate(paths []string) *tasks.RuleSetGate {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	readers := make([]tasks.ReaderProvider, 0, len(paths))
+	for _, p := range paths {
+		readers = append(readers, cmdutil.FileReader(p))
+	}
+
+	return &tasks.RuleSetGate{
+		RuleSetReaders: readers,
+		RuleSetPaths:   paths,
+		ReportWriter:   cmdutil.DirectWriter(os.Stderr),
+	}
+}
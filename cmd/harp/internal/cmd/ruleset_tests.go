@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/tasks/ruleset"
+)
+
+// -----------------------------------------------------------------------------
+type rulesetTestParams struct {
+	outputPath string
+	verbose    bool
+}
+
+var rulesetTestCmd = func() *cobra.Command {
+	params := &rulesetTestParams{}
+
+	longDesc := cmdutil.LongDesc(`
+	Run RuleSet unit tests.
+
+	Test files declare the tested RuleSet, input fixtures (bundle files and
+	inline packages) and the expected outcome of each rule (pass or fail, with
+	the expected violation messages).
+
+	Directories are walked recursively to find *_test.yaml and *_test.yml files.
+	Results are reported like go test with the coverage of rules asserted by at
+	least one expectation.`)
+
+	examples := cmdutil.Examples(`
+	# Run all ruleset tests from a directory
+	harp ruleset test ./policies
+
+	# Run a test file with verbose output
+	harp ruleset test -v ./policies/cso_test.yaml`)
+
+	cmd := &cobra.Command{
+		Use:     "test <path>...",
+		Short:   "Run RuleSet unit tests",
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Initialize logger and context
+			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-ruleset-test", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Prepare task
+			t := &ruleset.TestTask{
+				Paths:        args,
+				OutputWriter: cmdutil.FileWriter(params.outputPath),
+				Verbose:      params.verbose,
+			}
+
+			// Run the task
+			if err := t.Run(ctx); err != nil {
+				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
+			}
+		},
+	}
+
+	// Parameters
+	cmd.Flags().StringVar(&params.outputPath, "out", "-", "Test report output ('-' for stdout or filename)")
+	cmd.Flags().BoolVarP(&params.verbose, "verbose", "v", false, "Display all test cases and uncovered rules")
+
+	return cmd
+}
@@ -34,10 +34,14 @@ type templateParams struct {
 	Values        []string
 	StringValues  []string
 	FileValues    []string
+	ValuesSchema  string
 	LeftDelims    string
 	RightDelims   string
 	AltDelims     bool
 	RootPath      string
+	LibraryPaths  []string
+	Sandbox       templateSandboxParams
+	Deterministic templateDeterministicParams
 }
 
 // -----------------------------------------------------------------------------
@@ -54,6 +58,12 @@ var templateCmd = func() *cobra.Command {
 			ctx, cancel := cmdutil.Context(cmd.Context(), "template-render", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
 			defer cancel()
 
+			// Check deterministic mode
+			deterministic, err := params.Deterministic.deterministic()
+			if err != nil {
+				log.For(ctx).Fatal("unable to enable deterministic mode", zap.Error(err))
+			}
+
 			// Prepare task
 			t := &template.RenderTask{
 				InputReader:   cmdutil.FileReader(params.InputPath),
@@ -62,11 +72,15 @@ var templateCmd = func() *cobra.Command {
 				Values:        params.Values,
 				StringValues:  params.StringValues,
 				FileValues:    params.FileValues,
+				ValuesSchema:  params.ValuesSchema,
 				RootPath:      params.RootPath,
+				LibraryPaths:  params.LibraryPaths,
 				SecretLoaders: params.SecretLoaders,
 				LeftDelims:    params.LeftDelims,
 				RightDelims:   params.RightDelims,
 				AltDelims:     params.AltDelims,
+				Sandbox:       params.Sandbox.sandbox(),
+				Deterministic: deterministic,
 			}
 
 			// Run the task
@@ -80,14 +94,21 @@ var templateCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.InputPath, "in", "-", "Template input path ('-' for stdin or filename)")
 	cmd.Flags().StringVar(&params.OutputPath, "out", "", "Output file ('-' for stdout or a filename)")
 	cmd.Flags().StringVar(&params.RootPath, "root", "", "Defines file loader root base path")
-	cmd.Flags().StringArrayVarP(&params.SecretLoaders, "secrets-from", "s", []string{"vault"}, "Specifies secret containers to load ('vault' for Vault loader or '-' for stdin or filename)")
+	cmd.Flags().StringArrayVar(&params.LibraryPaths, "lib", []string{}, "Template library path (directory or archive) loaded before rendering to share named templates (repeatable)")
+	cmd.Flags().StringArrayVarP(&params.SecretLoaders, "secrets-from", "s", []string{"vault"}, "Specifies secret loaders in precedence order ('vault', 'consul://', 'etcd3://', 'zk://', 'crate://', 'sealed://' URIs, '-' for stdin or container filename)")
 	cmd.Flags().StringArrayVarP(&params.ValueFiles, "values", "f", []string{}, "Specifies value files to load")
 	cmd.Flags().StringArrayVar(&params.Values, "set", []string{}, "Specifies value (k=v)")
 	cmd.Flags().StringArrayVar(&params.StringValues, "set-string", []string{}, "Specifies value (k=string)")
 	cmd.Flags().StringArrayVar(&params.FileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&params.ValuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values")
 	cmd.Flags().StringVar(&params.LeftDelims, "left-delimiter", "{{", "Template left delimiter (default to '{{')")
 	cmd.Flags().StringVar(&params.RightDelims, "right-delimiter", "}}", "Template right delimiter (default to '}}')")
 	cmd.Flags().BoolVar(&params.AltDelims, "alt-delims", false, "Define '[[' and ']]' as template delimiters.")
+	addTemplateSandboxFlags(cmd, &params.Sandbox)
+	addTemplateDeterministicFlags(cmd, &params.Deterministic)
+
+	// Subcommands
+	cmd.AddCommand(templateDepsCmd())
 
 	return cmd
 }
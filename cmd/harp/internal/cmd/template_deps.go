@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/tasks/template"
+)
+
+// -----------------------------------------------------------------------------
+
+type templateDepsParams struct {
+	InputPath   string
+	OutputPath  string
+	Format      string
+	LeftDelims  string
+	RightDelims string
+	AltDelims   bool
+}
+
+var templateDepsCmd = func() *cobra.Command {
+	params := &templateDepsParams{}
+
+	longDesc := cmdutil.LongDesc(`
+	Statically analyze templates and list their external dependencies.
+
+	Templates are parsed without being executed, so that no secret backend is
+	contacted. Every 'secret' lookup, '.Values' key and '.Files' access is
+	reported with its template locations. References built from runtime
+	expressions can't be resolved and are reported as 'unresolved'.
+	`)
+
+	examples := cmdutil.Examples(`
+	# List all dependencies of a template directory as JSON
+	harp template deps --in templates/
+
+	# List all secret paths used by a template
+	harp template deps --in template.yaml | jq -r '.secrets[] | select(.unresolved | not) | .name'
+
+	# Render the dependency graph
+	harp template deps --in templates/ --format dot | dot -Tsvg > deps.svg`)
+
+	cmd := &cobra.Command{
+		Use:     "deps",
+		Short:   "Analyze template dependencies",
+		Long:    longDesc,
+		Example: examples,
+		Run: func(cmd *cobra.Command, _ []string) {
+			// Initialize logger and context
+			ctx, cancel := cmdutil.Context(cmd.Context(), "template-deps", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
+			defer cancel()
+
+			// Prepare task
+			t := &template.DependenciesTask{
+				InputPath:    params.InputPath,
+				OutputWriter: cmdutil.FileWriter(params.OutputPath),
+				OutputFormat: params.Format,
+				LeftDelims:   params.LeftDelims,
+				RightDelims:  params.RightDelims,
+				AltDelims:    params.AltDelims,
+			}
+
+			// Run the task
+			if err := t.Run(ctx); err != nil {
+				log.For(ctx).Fatal("unable to execute task", zap.Error(err))
+			}
+		},
+	}
+
+	// Parameters
+	cmd.Flags().StringVar(&params.InputPath, "in", "", "Template input path (directory, archive or filename)")
+	log.CheckErr("unable to mark 'in' flag as required.", cmd.MarkFlagRequired("in"))
+	cmd.Flags().StringVar(&params.OutputPath, "out", "", "Output file ('-' for stdout or a filename)")
+	cmd.Flags().StringVar(&params.Format, "format", "json", "Output format (json, dot)")
+	cmd.Flags().StringVar(&params.LeftDelims, "left-delimiter", "{{", "Template left delimiter (default to '{{')")
+	cmd.Flags().StringVar(&params.RightDelims, "right-delimiter", "}}", "Template right delimiter (default to '}}')")
+	cmd.Flags().BoolVar(&params.AltDelims, "alt-delims", false, "Define '[[' and ']]' as template delimiters.")
+
+	return cmd
+}
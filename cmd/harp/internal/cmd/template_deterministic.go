@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+type templateDeterministicParams struct {
+	seed string
+}
+
+// addTemplateDeterministicFlags registers the shared deterministic rendering
+// flags.
+func addTemplateDeterministicFlags(cmd *cobra.Command, params *templateDeterministicParams) {
+	cmd.Flags().StringVar(&params.seed, "deterministic-seed", "", "Seed all random generators for reproducible outputs (test builds only, built with '-tags testbuild')")
+}
+
+// deterministic returns the deterministic rendering settings, nil if the
+// deterministic mode is disabled.
+func (p *templateDeterministicParams) deterministic() (*engine.Deterministic, error) {
+	if p.seed == "" {
+		return nil, nil
+	}
+
+	return engine.NewDeterministic(p.seed)
+}
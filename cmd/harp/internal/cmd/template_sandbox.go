@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+type templateSandboxParams struct {
+	enabled       bool
+	secretPaths   []string
+	maxOutputSize int
+	timeout       time.Duration
+}
+
+// addTemplateSandboxFlags registers the shared template sandbox flags.
+func addTemplateSandboxFlags(cmd *cobra.Command, params *templateSandboxParams) {
+	cmd.Flags().BoolVar(&params.enabled, "sandbox", false, "Execute untrusted templates without environment, files, clock and network access")
+	cmd.Flags().StringArrayVar(&params.secretPaths, "sandbox-secret-path", []string{}, "Secret path glob allowed for 'secret' lookups in sandbox mode (repeatable)")
+	cmd.Flags().IntVar(&params.maxOutputSize, "sandbox-max-output", engine.DefaultSandboxMaxOutputSize, "Rendered output size limit in bytes in sandbox mode (0 for unlimited)")
+	cmd.Flags().DurationVar(&params.timeout, "sandbox-timeout", engine.DefaultSandboxTimeout, "Template execution time limit in sandbox mode (0 for unlimited)")
+}
+
+// sandbox returns the sandbox profile, nil if sandbox mode is disabled.
+func (p *templateSandboxParams) sandbox() *engine.Sandbox {
+	if !p.enabled {
+		return nil
+	}
+
+	return &engine.Sandbox{
+		MaxOutputSize: p.maxOutputSize,
+		Timeout:       p.timeout,
+		SecretPaths:   p.secretPaths,
+	}
+}
@@ -31,9 +31,10 @@ import (
 // -----------------------------------------------------------------------------
 
 type toConsulParams struct {
-	inputPath    string
-	secretAsLeaf bool
-	prefix       string
+	inputPath       string
+	secretAsLeaf    bool
+	prefix          string
+	enforceRuleSets []string
 }
 
 var toConsulCmd = func() *cobra.Command {
@@ -66,6 +67,7 @@ var toConsulCmd = func() *cobra.Command {
 				Store:           store,
 				ContainerReader: cmdutil.FileReader(params.inputPath),
 				SecretAsKey:     params.secretAsLeaf,
+				PolicyGate:      ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -80,6 +82,7 @@ var toConsulCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.inputPath, "in", "-", "Container path ('-' for stdin or filename)")
 	cmd.Flags().BoolVarP(&params.secretAsLeaf, "secret-as-leaf", "s", false, "Expand package path to secrets for provisioning")
 	cmd.Flags().StringVar(&params.prefix, "prefix", "", "Path prefix for insertion")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	return cmd
 }
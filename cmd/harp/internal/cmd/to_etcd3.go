@@ -49,6 +49,7 @@ type toEtcd3Params struct {
 	keyFile            string
 	passphrase         string
 	insecureSkipVerify bool
+	enforceRuleSets    []string
 }
 
 var toEtcd3Cmd = func() *cobra.Command {
@@ -105,6 +106,7 @@ var toEtcd3Cmd = func() *cobra.Command {
 				ContainerReader: cmdutil.FileReader(params.inputPath),
 				SecretAsKey:     params.secretAsLeaf,
 				Prefix:          params.prefix,
+				PolicyGate:      ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -119,6 +121,7 @@ var toEtcd3Cmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.inputPath, "in", "-", "Container path ('-' for stdin or filename)")
 	cmd.Flags().BoolVarP(&params.secretAsLeaf, "secret-as-leaf", "s", false, "Expand package path to secrets for provisioning")
 	cmd.Flags().StringVar(&params.prefix, "prefix", "", "Path prefix for insertion")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	cmd.Flags().StringArrayVar(&params.endpoints, "endpoints", []string{"http://localhost:2379"}, "Etcd cluster endpoints")
 	cmd.Flags().DurationVar(&params.dialTimeout, "dial-timeout", 15*time.Second, "Etcd cluster dial timeout")
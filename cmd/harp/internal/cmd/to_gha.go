@@ -29,10 +29,11 @@ import (
 // -----------------------------------------------------------------------------
 
 type toGithubActionParams struct {
-	inputPath    string
-	owner        string
-	repository   string
-	secretFilter string
+	inputPath       string
+	owner           string
+	repository      string
+	secretFilter    string
+	enforceRuleSets []string
 }
 
 var toGithubActionCmd = func() *cobra.Command {
@@ -55,6 +56,7 @@ $ harp to gha --in secret.container --owner elastic --owner harp --secret-filter
 				Owner:           params.owner,
 				Repository:      params.repository,
 				SecretFilter:    params.secretFilter,
+				PolicyGate:      ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -69,6 +71,7 @@ $ harp to gha --in secret.container --owner elastic --owner harp --secret-filter
 	cmd.Flags().StringVar(&params.owner, "owner", "", "Github owner/organization")
 	cmd.Flags().StringVar(&params.repository, "repository", "", "Github repository")
 	cmd.Flags().StringVar(&params.secretFilter, "secret-filter", "*", "Specify secret filter as Glob (*_KEY, private*)")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	return cmd
 }
@@ -18,25 +18,67 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/elastic/harp/pkg/sdk/cmdutil"
 	"github.com/elastic/harp/pkg/sdk/log"
 	"github.com/elastic/harp/pkg/tasks/to"
+	tplcmdutil "github.com/elastic/harp/pkg/template/cmdutil"
+	"github.com/elastic/harp/pkg/template/engine"
 )
 
 // -----------------------------------------------------------------------------
 
 var toRulesetCmd = func() *cobra.Command {
 	var (
-		inputPath  string
-		outputPath string
+		inputPath    string
+		outputPath   string
+		templatePath string
+		rootPath     string
+		valueFiles   []string
+		values       []string
+		stringValues []string
+		fileValues   []string
+		valuesSchema string
+		inferValues  bool
+		collapse     bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "ruleset",
 		Short: "Genereate a RuleSet descriptor from a Bundle",
+		Long: `Generate a RuleSet descriptor from a Bundle or a BundleTemplate.
+
+When generated from a Bundle, one rule is generated for each package with the
+package labels, annotations and secret keys. Value inference inspects secret
+values to add type constraints (base64, UUID, URL, JSON, email, certificate,
+private key, JWT, JWK), length and entropy bounds. Similar packages can be
+collapsed as one glob rule. The result is meant to be reviewed and tightened
+by hand.
+
+When generated from a BundleTemplate, one rule is generated for each secret
+suffix. Each rule requires the CSO path built by the template, the secret keys
+declared by the suffix template and the declared labels and annotations.
+Rules are named from the secret path. Secret values are not generated:
+template actions calling functions, such as generators or secret lookups, are
+skipped while extracting the secret keys. Imported bundles can then be checked
+against the same contract as generated ones.`,
+		Example: `  # Generate a RuleSet from a bundle
+  harp to ruleset --in customer.bundle
+
+  # Generate a reviewable RuleSet with value constraints and glob rules
+  harp to ruleset --in customer.bundle --infer-values --collapse
+
+  # Generate a RuleSet from a BundleTemplate
+  harp to ruleset --from-template customer.yaml --values values.yaml
+
+  # Check an imported bundle against the template contract
+  harp to ruleset --from-template customer.yaml --values values.yaml --out customer-ruleset.yaml
+  harp bundle lint --spec customer-ruleset.yaml --in imported.bundle`,
 		Run: func(cmd *cobra.Command, _ []string) {
 			// Initialize logger and context
 			ctx, cancel := cmdutil.Context(cmd.Context(), "harp-ruleset-from-bundle", conf.Debug.Enable, conf.Instrumentation.Logs.Level)
@@ -44,8 +86,47 @@ var toRulesetCmd = func() *cobra.Command {
 
 			// Prepare task
 			t := &to.RuleSetTask{
-				ContainerReader: cmdutil.FileReader(inputPath),
-				OutputWriter:    cmdutil.FileWriter(outputPath),
+				ContainerReader:  cmdutil.FileReader(inputPath),
+				OutputWriter:     cmdutil.FileWriter(outputPath),
+				InferValues:      inferValues,
+				CollapsePackages: collapse,
+			}
+
+			// Generate from template
+			if templatePath != "" {
+				// Load values
+				valueOpts := tplcmdutil.ValueOptions{
+					ValueFiles:   valueFiles,
+					Values:       values,
+					StringValues: stringValues,
+					FileValues:   fileValues,
+					SchemaFile:   valuesSchema,
+				}
+				values, err := valueOpts.MergeValues()
+				if err != nil {
+					log.For(ctx).Fatal("unable to process values", zap.Error(err))
+				}
+
+				// Load files
+				var files engine.Files
+				if rootPath != "" {
+					absRootPath, err := filepath.Abs(rootPath)
+					if err != nil {
+						log.For(ctx).Fatal("unable to get absolute file path for root path", zap.Error(err))
+					}
+
+					files, err = tplcmdutil.Files(os.DirFS(absRootPath), ".")
+					if err != nil {
+						log.For(ctx).Fatal("unable to process files", zap.Error(err))
+					}
+				}
+
+				t.TemplateReader = cmdutil.FileReader(templatePath)
+				t.TemplateContext = engine.NewContext(
+					engine.WithName(templatePath),
+					engine.WithValues(values),
+					engine.WithFiles(files),
+				)
 			}
 
 			// Run the task
@@ -58,6 +139,15 @@ var toRulesetCmd = func() *cobra.Command {
 	// Parameters
 	cmd.Flags().StringVar(&inputPath, "in", "-", "Container input ('-' for stdin or filename)")
 	cmd.Flags().StringVar(&outputPath, "out", "", "Output RuleSet specification path ('' for stdout or filename)")
+	cmd.Flags().BoolVar(&inferValues, "infer-values", false, "Inspect secret values to generate type, length and entropy constraints")
+	cmd.Flags().BoolVar(&collapse, "collapse", false, "Collapse packages with the same structure as one glob rule")
+	cmd.Flags().StringVar(&templatePath, "from-template", "", "Generate the RuleSet from a BundleTemplate path ('-' for stdin or filename)")
+	cmd.Flags().StringVar(&rootPath, "root", "", "Defines file loader root base path")
+	cmd.Flags().StringArrayVarP(&valueFiles, "values", "f", []string{}, "Specifies value files to load")
+	cmd.Flags().StringArrayVar(&values, "set", []string{}, "Specifies value (k=v)")
+	cmd.Flags().StringArrayVar(&stringValues, "set-string", []string{}, "Specifies value (k=string)")
+	cmd.Flags().StringArrayVar(&fileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&valuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values")
 
 	return cmd
 }
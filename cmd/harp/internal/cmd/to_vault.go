@@ -36,6 +36,7 @@ var toVaultCmd = func() *cobra.Command {
 		withMetadata      bool
 		withVaultMetadata bool
 		maxWorkerCount    int64
+		enforceRuleSets   []string
 	)
 
 	cmd := &cobra.Command{
@@ -54,6 +55,7 @@ var toVaultCmd = func() *cobra.Command {
 				AsVaultMetadata: withVaultMetadata,
 				VaultNamespace:  namespace,
 				MaxWorkerCount:  maxWorkerCount,
+				PolicyGate:      ruleSetGate(enforceRuleSets),
 			}
 
 			// Run the task
@@ -70,6 +72,7 @@ var toVaultCmd = func() *cobra.Command {
 	cmd.Flags().BoolVar(&withMetadata, "with-metadata", false, "Push container metadata as secret data")
 	cmd.Flags().BoolVar(&withVaultMetadata, "with-vault-metadata", false, "Push container metadata as secret metadata (requires Vault >=1.9)")
 	cmd.Flags().Int64Var(&maxWorkerCount, "worker-count", 4, "Active worker count limit")
+	addEnforceRuleSetFlag(cmd, &enforceRuleSets)
 
 	return cmd
 }
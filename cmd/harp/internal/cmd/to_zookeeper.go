@@ -37,8 +37,9 @@ type toZookeeperParams struct {
 	secretAsLeaf bool
 	prefix       string
 
-	endpoints   []string
-	dialTimeout time.Duration
+	endpoints       []string
+	dialTimeout     time.Duration
+	enforceRuleSets []string
 }
 
 var toZookeeperCmd = func() *cobra.Command {
@@ -70,6 +71,7 @@ var toZookeeperCmd = func() *cobra.Command {
 				ContainerReader: cmdutil.FileReader(params.inputPath),
 				SecretAsKey:     params.secretAsLeaf,
 				Prefix:          params.prefix,
+				PolicyGate:      ruleSetGate(params.enforceRuleSets),
 			}
 
 			// Run the task
@@ -84,6 +86,7 @@ var toZookeeperCmd = func() *cobra.Command {
 	cmd.Flags().StringVar(&params.inputPath, "in", "-", "Container path ('-' for stdin or filename)")
 	cmd.Flags().BoolVarP(&params.secretAsLeaf, "secret-as-leaf", "s", false, "Expand package path to secrets for provisioning")
 	cmd.Flags().StringVar(&params.prefix, "prefix", "", "Path prefix for insertion")
+	addEnforceRuleSetFlag(cmd, &params.enforceRuleSets)
 
 	cmd.Flags().StringArrayVar(&params.endpoints, "endpoints", []string{"127.0.0.1:2181"}, "Zookeeper client endpoints")
 	cmd.Flags().DurationVar(&params.dialTimeout, "dial-timeout", 15*time.Second, "Zookeeper client dial timeout")
@@ -32,6 +32,7 @@ type valuesParams struct {
 	Values       []string
 	StringValues []string
 	FileValues   []string
+	ValuesSchema string
 }
 
 // -----------------------------------------------------------------------------
@@ -55,6 +56,7 @@ var valuesCmd = func() *cobra.Command {
 				Values:       params.Values,
 				StringValues: params.StringValues,
 				FileValues:   params.FileValues,
+				ValuesSchema: params.ValuesSchema,
 			}
 
 			// Run the task
@@ -70,6 +72,7 @@ var valuesCmd = func() *cobra.Command {
 	cmd.Flags().StringArrayVar(&params.Values, "set", []string{}, "Specifies value (k=v)")
 	cmd.Flags().StringArrayVar(&params.StringValues, "set-string", []string{}, "Specifies value (k=string)")
 	cmd.Flags().StringArrayVar(&params.FileValues, "set-file", []string{}, "Specifies value (k=filepath)")
+	cmd.Flags().StringVar(&params.ValuesSchema, "values-schema", "", "Specifies the JSON schema used to validate values")
 
 	return cmd
 }
@@ -18,11 +18,15 @@
 package bundle
 
 import (
+	"errors"
 	"io"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
 
+// ErrSecretNotFound is raised when a secret path doesn't exist in the bundle.
+var ErrSecretNotFound = errors.New("secret not found")
+
 // Reader exposes bundle reader contract
 type Reader interface {
 	Read(reader io.Reader) (*bundlev1.Bundle, error)
@@ -124,7 +124,7 @@ func Read(b *bundlev1.Bundle, secretPath string) (map[string]interface{}, error)
 		}
 	}
 	if found == nil {
-		return nil, fmt.Errorf("unable to lookup secret with path '%s'", secretPath)
+		return nil, fmt.Errorf("unable to lookup secret with path '%s': %w", secretPath, ErrSecretNotFound)
 	}
 
 	// Transform secret value
@@ -209,6 +209,17 @@ func AsMap(b *bundlev1.Bundle) (KV, error) {
 
 	res := KV{}
 	for _, p := range b.Packages {
+		if p == nil {
+			// Ignore nil package
+			continue
+		}
+
+		// Package without secrets
+		if p.Secrets == nil {
+			res[p.Name] = KV{}
+			continue
+		}
+
 		// Check if secret is locked
 		if p.Secrets.Locked != nil {
 			// Encode value
@@ -0,0 +1,570 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// Move describes a RFC 6902 operation to move a value to another location.
+	Move string = "move"
+	// Copy describes a RFC 6902 operation to copy a value to another location.
+	Copy string = "copy"
+	// Test describes a RFC 6902 operation to assert a value.
+	Test string = "test"
+)
+
+// JSONPatch represents a RFC 6902 JSON Patch document.
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOperation represents a RFC 6902 JSON Patch operation.
+type JSONPatchOperation struct {
+	Operation string      `json:"op"`
+	Path      string      `json:"path"`
+	From      string      `json:"from,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+
+// ToJSONPatch converts an oplog to a RFC 6902 JSON Patch document applicable
+// to the bundle map representation (package path => secret key => value).
+func ToJSONPatch(oplog OpLog) (JSONPatch, error) {
+	res := JSONPatch{}
+
+	for _, op := range oplog {
+		switch op.Type {
+		case "package":
+			path := jsonPointer(op.Path)
+			switch op.Operation {
+			case Add:
+				res = append(res, JSONPatchOperation{Operation: Add, Path: path, Value: map[string]interface{}{}})
+			case Remove:
+				res = append(res, JSONPatchOperation{Operation: Remove, Path: path})
+			default:
+				return nil, fmt.Errorf("unsupported package operation '%s'", op.Operation)
+			}
+		case "secret":
+			pathParts := strings.SplitN(op.Path, "#", 2)
+			if len(pathParts) != 2 {
+				return nil, fmt.Errorf("invalid secret path '%s'", op.Path)
+			}
+			path := jsonPointer(pathParts...)
+			switch op.Operation {
+			case Add, Replace:
+				res = append(res, JSONPatchOperation{Operation: op.Operation, Path: path, Value: op.Value})
+			case Remove:
+				res = append(res, JSONPatchOperation{Operation: Remove, Path: path})
+			default:
+				return nil, fmt.Errorf("unsupported secret operation '%s'", op.Operation)
+			}
+		default:
+			return nil, fmt.Errorf("unknown oplog type '%s'", op.Type)
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// FromJSONPatch converts a RFC 6902 JSON Patch document to an oplog. Only add,
+// remove and replace operations can be converted. Non-string values are
+// converted to their JSON encoding.
+func FromJSONPatch(patch JSONPatch) (OpLog, error) {
+	res := OpLog{}
+
+	for i, op := range patch {
+		tokens, err := parseJSONPointer(op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path for operation %d: %w", i, err)
+		}
+
+		switch len(tokens) {
+		case 1:
+			switch op.Operation {
+			case Remove:
+				res = append(res, DiffItem{Operation: Remove, Type: "package", Path: tokens[0]})
+			case Add, Replace:
+				secrets, ok := op.Value.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("package value of operation %d must be an object", i)
+				}
+				if op.Operation == Replace {
+					res = append(res, DiffItem{Operation: Remove, Type: "package", Path: tokens[0]})
+				}
+				res = append(res, DiffItem{Operation: Add, Type: "package", Path: tokens[0]})
+
+				items, err := secretItems(Add, tokens[0], secrets)
+				if err != nil {
+					return nil, fmt.Errorf("unable to convert operation %d: %w", i, err)
+				}
+				res = append(res, items...)
+			default:
+				return nil, fmt.Errorf("'%s' operation %d can't be represented as an oplog", op.Operation, i)
+			}
+		case 2:
+			path := fmt.Sprintf("%s#%s", tokens[0], tokens[1])
+			switch op.Operation {
+			case Remove:
+				res = append(res, DiffItem{Operation: Remove, Type: "secret", Path: path})
+			case Add, Replace:
+				value, err := stringValue(op.Value)
+				if err != nil {
+					return nil, fmt.Errorf("unable to convert operation %d value: %w", i, err)
+				}
+				res = append(res, DiffItem{Operation: op.Operation, Type: "secret", Path: path, Value: value})
+			default:
+				return nil, fmt.Errorf("'%s' operation %d can't be represented as an oplog", op.Operation, i)
+			}
+		default:
+			return nil, fmt.Errorf("path '%s' of operation %d doesn't target a package or a secret", op.Path, i)
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// ToMergePatch converts an oplog to a RFC 7386 JSON Merge Patch document
+// applicable to the bundle map representation.
+func ToMergePatch(oplog OpLog) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+
+	for _, op := range oplog {
+		switch op.Type {
+		case "package":
+			switch op.Operation {
+			case Add:
+				if _, ok := res[op.Path].(map[string]interface{}); !ok {
+					res[op.Path] = map[string]interface{}{}
+				}
+			case Remove:
+				res[op.Path] = nil
+			default:
+				return nil, fmt.Errorf("unsupported package operation '%s'", op.Operation)
+			}
+		case "secret":
+			pathParts := strings.SplitN(op.Path, "#", 2)
+			if len(pathParts) != 2 {
+				return nil, fmt.Errorf("invalid secret path '%s'", op.Path)
+			}
+
+			secrets, ok := res[pathParts[0]].(map[string]interface{})
+			if !ok {
+				secrets = map[string]interface{}{}
+				res[pathParts[0]] = secrets
+			}
+
+			switch op.Operation {
+			case Add, Replace:
+				secrets[pathParts[1]] = op.Value
+			case Remove:
+				secrets[pathParts[1]] = nil
+			default:
+				return nil, fmt.Errorf("unsupported secret operation '%s'", op.Operation)
+			}
+		default:
+			return nil, fmt.Errorf("unknown oplog type '%s'", op.Type)
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// FromMergePatch converts a RFC 7386 JSON Merge Patch document to an oplog.
+// The source bundle map representation is used to distinguish added and
+// replaced entries, when nil all entries are considered as added.
+func FromMergePatch(src, patch map[string]interface{}) (OpLog, error) {
+	res := OpLog{}
+
+	for _, pkgName := range sortedKeys(patch) {
+		srcSecrets, exists := src[pkgName].(map[string]interface{})
+
+		switch secrets := patch[pkgName].(type) {
+		case nil:
+			res = append(res, DiffItem{Operation: Remove, Type: "package", Path: pkgName})
+		case map[string]interface{}:
+			if !exists {
+				res = append(res, DiffItem{Operation: Add, Type: "package", Path: pkgName})
+			}
+
+			for _, key := range sortedKeys(secrets) {
+				path := fmt.Sprintf("%s#%s", pkgName, key)
+				if secrets[key] == nil {
+					if _, ok := srcSecrets[key]; ok || src == nil {
+						res = append(res, DiffItem{Operation: Remove, Type: "secret", Path: path})
+					}
+					continue
+				}
+
+				value, err := stringValue(secrets[key])
+				if err != nil {
+					return nil, fmt.Errorf("unable to convert '%s' value: %w", path, err)
+				}
+
+				op := Add
+				if _, ok := srcSecrets[key]; ok {
+					op = Replace
+				}
+				res = append(res, DiffItem{Operation: op, Type: "secret", Path: path, Value: value})
+			}
+		default:
+			return nil, fmt.Errorf("package '%s' value must be an object or null", pkgName)
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// ApplyJSONPatch applies the given RFC 6902 JSON Patch document to a JSON
+// document. The input document is not modified.
+func ApplyJSONPatch(doc interface{}, patch JSONPatch) (interface{}, error) {
+	res := deepCopy(doc)
+
+	for i, op := range patch {
+		tokens, err := parseJSONPointer(op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path for operation %d: %w", i, err)
+		}
+
+		switch op.Operation {
+		case Add:
+			res, err = addValue(res, tokens, deepCopy(op.Value))
+		case Remove:
+			res, _, err = removeValue(res, tokens)
+		case Replace:
+			if len(tokens) == 0 {
+				res = deepCopy(op.Value)
+			} else if _, err = getValue(res, tokens); err == nil {
+				if res, _, err = removeValue(res, tokens); err == nil {
+					res, err = addValue(res, tokens, deepCopy(op.Value))
+				}
+			}
+		case Move, Copy:
+			var from []string
+			from, err = parseJSONPointer(op.From)
+			if err != nil {
+				return nil, fmt.Errorf("invalid from path for operation %d: %w", i, err)
+			}
+
+			var value interface{}
+			if op.Operation == Move {
+				if isPrefix(from, tokens) && len(from) < len(tokens) {
+					return nil, fmt.Errorf("operation %d can't move a value into one of its children", i)
+				}
+				if res, value, err = removeValue(res, from); err == nil {
+					res, err = addValue(res, tokens, value)
+				}
+			} else if value, err = getValue(res, from); err == nil {
+				res, err = addValue(res, tokens, deepCopy(value))
+			}
+		case Test:
+			var value interface{}
+			if value, err = getValue(res, tokens); err == nil && !jsonEqual(value, op.Value) {
+				err = fmt.Errorf("value at '%s' doesn't match", op.Path)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported operation '%s'", op.Operation)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply '%s' operation %d: %w", op.Operation, i, err)
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// ApplyMergePatch applies the given RFC 7386 JSON Merge Patch document to a
+// JSON document. The input document is not modified.
+func ApplyMergePatch(doc, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	target, ok := deepCopy(doc).(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+
+	for k, v := range patchObj {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		target[k] = ApplyMergePatch(target[k], v)
+	}
+
+	return target
+}
+
+// -----------------------------------------------------------------------------
+
+func jsonPointer(tokens ...string) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+
+	return sb.String()
+}
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer '%s' must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func isPrefix(prefix, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index '%s'", token)
+	}
+
+	idx := 0
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid array index '%s'", token)
+		}
+		idx = idx*10 + int(c-'0')
+		if idx > length {
+			return 0, fmt.Errorf("array index '%s' is out of bounds", token)
+		}
+	}
+
+	if idx > length || (!allowEnd && idx == length) {
+		return 0, fmt.Errorf("array index '%s' is out of bounds", token)
+	}
+
+	return idx, nil
+}
+
+func getValue(node interface{}, tokens []string) (interface{}, error) {
+	for _, t := range tokens {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[t]
+			if !ok {
+				return nil, fmt.Errorf("member '%s' doesn't exist", t)
+			}
+			node = child
+		case []interface{}:
+			idx, err := arrayIndex(t, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, fmt.Errorf("unable to resolve '%s' in a scalar value", t)
+		}
+	}
+
+	return node, nil
+}
+
+func addValue(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			n[tokens[0]] = value
+			return n, nil
+		}
+
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("member '%s' doesn't exist", tokens[0])
+		}
+		updated, err := addValue(child, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = updated
+
+		return n, nil
+	case []interface{}:
+		idx, err := arrayIndex(tokens[0], len(n), len(tokens) == 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			n = append(n, nil)
+			copy(n[idx+1:], n[idx:])
+			n[idx] = value
+			return n, nil
+		}
+
+		updated, err := addValue(n[idx], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = updated
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unable to resolve '%s' in a scalar value", tokens[0])
+	}
+}
+
+func removeValue(node interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("unable to remove the document root")
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, nil, fmt.Errorf("member '%s' doesn't exist", tokens[0])
+		}
+		if len(tokens) == 1 {
+			delete(n, tokens[0])
+			return n, child, nil
+		}
+
+		updated, removed, err := removeValue(child, tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[tokens[0]] = updated
+
+		return n, removed, nil
+	case []interface{}:
+		idx, err := arrayIndex(tokens[0], len(n), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(tokens) == 1 {
+			removed := n[idx]
+			return append(n[:idx], n[idx+1:]...), removed, nil
+		}
+
+		updated, removed, err := removeValue(n[idx], tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[idx] = updated
+
+		return n, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("unable to resolve '%s' in a scalar value", tokens[0])
+	}
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			res[k] = deepCopy(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = deepCopy(item)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	return string(aJSON) == string(bJSON)
+}
+
+func stringValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode value as JSON: %w", err)
+	}
+
+	return string(out), nil
+}
+
+func secretItems(op, pkgName string, secrets map[string]interface{}) (OpLog, error) {
+	res := OpLog{}
+	for _, key := range sortedKeys(secrets) {
+		value, err := stringValue(secrets[key])
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert '%s#%s' value: %w", pkgName, key, err)
+		}
+		res = append(res, DiffItem{Operation: op, Type: "secret", Path: fmt.Sprintf("%s#%s", pkgName, key), Value: value})
+	}
+
+	return res, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,319 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		oplog   OpLog
+		want    JSONPatch
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			oplog: OpLog{},
+			want:  JSONPatch{},
+		},
+		{
+			name: "operations",
+			oplog: OpLog{
+				{Operation: Add, Type: "package", Path: "application/new"},
+				{Operation: Add, Type: "secret", Path: "application/new#key", Value: "value"},
+				{Operation: Remove, Type: "package", Path: "application/old"},
+				{Operation: Replace, Type: "secret", Path: "application/test#a/b~c", Value: ""},
+				{Operation: Remove, Type: "secret", Path: "application/test#removed"},
+			},
+			want: JSONPatch{
+				{Operation: Add, Path: "/application~1new", Value: map[string]interface{}{}},
+				{Operation: Add, Path: "/application~1new/key", Value: "value"},
+				{Operation: Remove, Path: "/application~1old"},
+				{Operation: Replace, Path: "/application~1test/a~1b~0c", Value: ""},
+				{Operation: Remove, Path: "/application~1test/removed"},
+			},
+		},
+		{
+			name: "invalid type",
+			oplog: OpLog{
+				{Operation: Add, Type: "label", Path: "application/new"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid secret path",
+			oplog: OpLog{
+				{Operation: Add, Type: "secret", Path: "application/new"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToJSONPatch(tt.oplog)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("%q. ToJSONPatch():\n-got/+want\ndiff %s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestFromJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    OpLog
+		wantErr bool
+	}{
+		{
+			name:  "operations",
+			patch: `[{"op":"add","path":"/application~1new","value":{"b":"2","a":1}},{"op":"replace","path":"/application~1test/key","value":"value"},{"op":"remove","path":"/application~1old"}]`,
+			want: OpLog{
+				{Operation: Add, Type: "package", Path: "application/new"},
+				{Operation: Add, Type: "secret", Path: "application/new#a", Value: "1"},
+				{Operation: Add, Type: "secret", Path: "application/new#b", Value: "2"},
+				{Operation: Replace, Type: "secret", Path: "application/test#key", Value: "value"},
+				{Operation: Remove, Type: "package", Path: "application/old"},
+			},
+		},
+		{
+			name:    "move",
+			patch:   `[{"op":"move","from":"/application~1old","path":"/application~1new"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "package value",
+			patch:   `[{"op":"add","path":"/application~1new","value":"invalid"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "too deep",
+			patch:   `[{"op":"add","path":"/application~1new/key/sub","value":"invalid"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid pointer",
+			patch:   `[{"op":"add","path":"application","value":{}}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch JSONPatch
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("unable to decode patch: %v", err)
+			}
+
+			got, err := FromJSONPatch(patch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("%q. FromJSONPatch():\n-got/+want\ndiff %s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestMergePatch_RoundTrip(t *testing.T) {
+	src := map[string]interface{}{
+		"application/old": map[string]interface{}{
+			"key": "value",
+		},
+		"application/test": map[string]interface{}{
+			"key":     "value",
+			"removed": "value",
+		},
+	}
+	oplog := OpLog{
+		{Operation: Add, Type: "package", Path: "application/new"},
+		{Operation: Add, Type: "secret", Path: "application/new#key", Value: "value"},
+		{Operation: Remove, Type: "package", Path: "application/old"},
+		{Operation: Replace, Type: "secret", Path: "application/test#key", Value: "updated"},
+		{Operation: Remove, Type: "secret", Path: "application/test#removed"},
+	}
+
+	patch, err := ToMergePatch(oplog)
+	if err != nil {
+		t.Fatalf("ToMergePatch() error = %v", err)
+	}
+	if diff := cmp.Diff(patch, map[string]interface{}{
+		"application/new": map[string]interface{}{
+			"key": "value",
+		},
+		"application/old": nil,
+		"application/test": map[string]interface{}{
+			"key":     "updated",
+			"removed": nil,
+		},
+	}); diff != "" {
+		t.Errorf("ToMergePatch():\n-got/+want\ndiff %s", diff)
+	}
+
+	got, err := FromMergePatch(src, patch)
+	if err != nil {
+		t.Fatalf("FromMergePatch() error = %v", err)
+	}
+	if diff := cmp.Diff(got, oplog); diff != "" {
+		t.Errorf("FromMergePatch():\n-got/+want\ndiff %s", diff)
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := `{"a":{"b":"c","list":["x","y"]},"d":"e"}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "add",
+			patch: `[{"op":"add","path":"/a/f","value":"g"},{"op":"add","path":"/a/list/1","value":"z"},{"op":"add","path":"/a/list/-","value":"w"}]`,
+			want:  `{"a":{"b":"c","f":"g","list":["x","z","y","w"]},"d":"e"}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op":"remove","path":"/a/b"},{"op":"remove","path":"/a/list/0"}]`,
+			want:  `{"a":{"list":["y"]},"d":"e"}`,
+		},
+		{
+			name:  "replace",
+			patch: `[{"op":"replace","path":"/d","value":{"h":"i"}}]`,
+			want:  `{"a":{"b":"c","list":["x","y"]},"d":{"h":"i"}}`,
+		},
+		{
+			name:  "move and copy",
+			patch: `[{"op":"move","from":"/a/b","path":"/b"},{"op":"copy","from":"/d","path":"/a/d"}]`,
+			want:  `{"a":{"d":"e","list":["x","y"]},"b":"c","d":"e"}`,
+		},
+		{
+			name:  "test",
+			patch: `[{"op":"test","path":"/a/list","value":["x","y"]},{"op":"remove","path":"/d"}]`,
+			want:  `{"a":{"b":"c","list":["x","y"]}}`,
+		},
+		{
+			name:    "test failed",
+			patch:   `[{"op":"test","path":"/d","value":"f"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace missing",
+			patch:   `[{"op":"replace","path":"/z","value":"f"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "remove missing",
+			patch:   `[{"op":"remove","path":"/a/z"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "add to missing parent",
+			patch:   `[{"op":"add","path":"/z/y","value":"f"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid index",
+			patch:   `[{"op":"add","path":"/a/list/01","value":"f"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "out of bounds",
+			patch:   `[{"op":"add","path":"/a/list/3","value":"f"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "move into child",
+			patch:   `[{"op":"move","from":"/a","path":"/a/b"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported",
+			patch:   `[{"op":"merge","path":"/a","value":{}}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				input interface{}
+				patch JSONPatch
+			)
+			if err := json.Unmarshal([]byte(doc), &input); err != nil {
+				t.Fatalf("unable to decode document: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("unable to decode patch: %v", err)
+			}
+
+			got, err := ApplyJSONPatch(input, patch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("unable to decode expected document: %v", err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("%q. ApplyJSONPatch():\n-got/+want\ndiff %s", tt.name, diff)
+			}
+
+			// Input must not be altered
+			var original interface{}
+			if err := json.Unmarshal([]byte(doc), &original); err != nil {
+				t.Fatalf("unable to decode document: %v", err)
+			}
+			if diff := cmp.Diff(input, original); diff != "" {
+				t.Errorf("%q. ApplyJSONPatch() altered the input document:\ndiff %s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	var doc, patch, want interface{}
+	if err := json.Unmarshal([]byte(`{"a":"b","c":{"d":"e","f":"g"}}`), &doc); err != nil {
+		t.Fatalf("unable to decode document: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"a":"z","c":{"f":null}}`), &patch); err != nil {
+		t.Fatalf("unable to decode patch: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"a":"z","c":{"d":"e"}}`), &want); err != nil {
+		t.Fatalf("unable to decode expected document: %v", err)
+	}
+
+	if diff := cmp.Diff(ApplyMergePatch(doc, patch), want); diff != "" {
+		t.Errorf("ApplyMergePatch():\n-got/+want\ndiff %s", diff)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
@@ -83,7 +84,12 @@ func TestFromBundle(t *testing.T) {
 func TestFromBundle_Fuzz(t *testing.T) {
 	// Making sure the descrption never panics
 	for i := 0; i < 50; i++ {
-		f := fuzz.New()
+		f := fuzz.New().Funcs(
+			// Protobuf struct values use oneof interfaces not supported by the fuzzer
+			func(s **structpb.Struct, c fuzz.Continue) {
+				*s = nil
+			},
+		)
 
 		var (
 			src bundlev1.Bundle
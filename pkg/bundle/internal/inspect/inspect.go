@@ -0,0 +1,198 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package inspect provides secret value format detection shared by ruleset
+// generation and package selectors.
+package inspect
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"go.step.sm/crypto/pemutil"
+
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+)
+
+const (
+	// FormatCertificate is a PEM encoded certificate or certificate chain.
+	FormatCertificate = "certificate"
+	// FormatPrivateKey is a PEM encoded private key.
+	FormatPrivateKey = "private_key"
+	// FormatPublicKey is a PEM encoded public key.
+	FormatPublicKey = "public_key"
+	// FormatPEM is any other PEM encoded content.
+	FormatPEM = "pem"
+	// FormatJWT is a JSON Web Token.
+	FormatJWT = "jwt"
+	// FormatJWK is a JSON Web Key.
+	FormatJWK = "jwk"
+	// FormatJSON is a JSON object or array.
+	FormatJSON = "json"
+	// FormatUUID is an UUID.
+	FormatUUID = "uuid"
+	// FormatEmail is an email address.
+	FormatEmail = "email"
+	// FormatURL is an URL with a scheme.
+	FormatURL = "url"
+	// FormatBase64 is a base64 encoded value.
+	FormatBase64 = "base64"
+	// FormatString is an unclassified string.
+	FormatString = "string"
+
+	// Shorter values are not considered as base64 encoded
+	minBase64Length = 16
+)
+
+// Info describes the detected value format.
+type Info struct {
+	// Format is the detected format.
+	Format string
+	// KeyType is the key algorithm (rsa, ec, ed25519) of certificates, keys
+	// and JWK.
+	KeyType string
+	// KeyBits is the key size in bits.
+	KeyBits int
+}
+
+// IsPEM returns true if the format is PEM encoded.
+func (i *Info) IsPEM() bool {
+	switch i.Format {
+	case FormatPEM, FormatCertificate, FormatPrivateKey, FormatPublicKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// Inspect detects the format of the given value.
+//
+//nolint:gocyclo // detection cascade
+func Inspect(value string) *Info {
+	res := &Info{
+		Format: FormatString,
+	}
+
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(trimmed, "-----BEGIN"):
+		inspectPEM(trimmed, res)
+	case strings.Count(trimmed, ".") == 2 && isJWT(trimmed):
+		res.Format = FormatJWT
+	case strings.HasPrefix(trimmed, "{") && strings.Contains(trimmed, `"kty"`) && inspectJWK(trimmed, res):
+		res.Format = FormatJWK
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		res.Format = FormatJSON
+	case validation.Validate(value, is.UUID) == nil:
+		res.Format = FormatUUID
+	case validation.Validate(value, is.EmailFormat) == nil:
+		res.Format = FormatEmail
+	case strings.Contains(value, "://") && validation.Validate(value, is.URL) == nil:
+		res.Format = FormatURL
+	case len(value) >= minBase64Length && validation.Validate(value, is.Base64) == nil:
+		res.Format = FormatBase64
+	}
+
+	return res
+}
+
+// KeyInfo returns the key algorithm and size in bits of the given public or
+// private key.
+func KeyInfo(key interface{}) (keyType string, bits int, ok bool) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return "rsa", k.N.BitLen(), true
+	case *rsa.PublicKey:
+		return "rsa", k.N.BitLen(), true
+	case *ecdsa.PrivateKey:
+		return "ec", k.Curve.Params().BitSize, true
+	case *ecdsa.PublicKey:
+		return "ec", k.Curve.Params().BitSize, true
+	case ed25519.PrivateKey, *ed25519.PrivateKey, ed25519.PublicKey, *ed25519.PublicKey:
+		return "ed25519", 256, true
+	default:
+		return "", 0, false
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func inspectPEM(value string, res *Info) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return
+	}
+	res.Format = FormatPEM
+
+	// Certificate chain
+	if block.Type == "CERTIFICATE" {
+		certs, err := pemutil.ParseCertificateBundle([]byte(value))
+		if err != nil || len(certs) == 0 {
+			return
+		}
+		res.Format = FormatCertificate
+		res.KeyType, res.KeyBits, _ = KeyInfo(certs[0].PublicKey)
+		return
+	}
+
+	// Keys
+	key, err := pemutil.Parse([]byte(value))
+	if err != nil {
+		return
+	}
+	keyType, bits, ok := KeyInfo(key)
+	if !ok {
+		return
+	}
+	res.KeyType, res.KeyBits = keyType, bits
+
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *ed25519.PrivateKey:
+		res.Format = FormatPrivateKey
+	default:
+		res.Format = FormatPublicKey
+	}
+}
+
+func inspectJWK(value string, res *Info) bool {
+	key, err := crypto.FromJWK(value)
+	if err != nil {
+		return false
+	}
+
+	// Private keys are returned as key pairs
+	if pair, ok := key.(struct {
+		Private interface{}
+		Public  interface{}
+	}); ok {
+		key = pair.Private
+	}
+	res.KeyType, res.KeyBits, _ = KeyInfo(key)
+
+	return true
+}
+
+func isJWT(value string) bool {
+	_, err := crypto.ParseJWT(value)
+	return err == nil
+}
@@ -0,0 +1,242 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package policy provides shared Rego policy loading and evaluation used by
+// ruleset engines and package selectors.
+package policy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	//nolint:staticcheck // deprecated package requires refactor
+	"github.com/open-policy-agent/opa/rego"
+)
+
+const (
+	// ModuleName is the module name used for inline policies.
+	ModuleName = "harp.rego"
+)
+
+// Source describes where the Rego policy is loaded from.
+type Source struct {
+	// Query evaluated by the policy.
+	Query string
+	// Module is an inline policy content.
+	Module string
+	// Paths are policy files, directories or OPA bundle archives.
+	Paths []string
+	// Data are JSON or YAML document files exposed as `data`.
+	Data []string
+}
+
+// Decision is the policy evaluation result.
+type Decision struct {
+	// Defined is false when the query result is undefined.
+	Defined bool
+	// Allowed is true when the query returns true or an empty collection.
+	Allowed bool
+	// Messages holds violation messages returned by `deny[msg]` style
+	// queries.
+	Messages []string
+}
+
+// Prepare loads all policy sources and prepares the query for evaluation.
+func Prepare(ctx context.Context, src *Source) (rego.PreparedEvalQuery, error) {
+	// Check arguments
+	if src == nil {
+		return rego.PreparedEvalQuery{}, errors.New("unable to prepare a nil policy source")
+	}
+	if src.Query == "" {
+		return rego.PreparedEvalQuery{}, errors.New("policy query must not be blank")
+	}
+	if src.Module == "" && len(src.Paths) == 0 {
+		return rego.PreparedEvalQuery{}, errors.New("an inline policy or policy paths must be defined")
+	}
+
+	opts := []func(*rego.Rego){
+		rego.Query(src.Query),
+	}
+	if src.Module != "" {
+		opts = append(opts, rego.Module(ModuleName, src.Module))
+	}
+
+	// Dispatch paths between bundles and raw files
+	loadPaths := []string{}
+	for _, p := range src.Paths {
+		isBundle, err := isBundlePath(p)
+		if err != nil {
+			return rego.PreparedEvalQuery{}, err
+		}
+		if isBundle {
+			opts = append(opts, rego.LoadBundle(p))
+			continue
+		}
+		loadPaths = append(loadPaths, p)
+	}
+
+	// Data documents
+	for _, p := range src.Data {
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return rego.PreparedEvalQuery{}, fmt.Errorf("data document '%s' must be a JSON or YAML file", p)
+		}
+		loadPaths = append(loadPaths, p)
+	}
+	if len(loadPaths) > 0 {
+		opts = append(opts, rego.Load(loadPaths, nil))
+	}
+
+	// Parse and prepare the policy
+	query, err := rego.New(opts...).PrepareForEval(ctx)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("unable to prepare for eval: %w", err)
+	}
+
+	// No error
+	return query, nil
+}
+
+// Evaluate the prepared query with the given input.
+func Evaluate(ctx context.Context, query rego.PreparedEvalQuery, opts ...rego.EvalOption) (*Decision, error) {
+	results, err := query.Eval(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate the policy: %w", err)
+	}
+
+	res := &Decision{
+		Allowed:  true,
+		Messages: []string{},
+	}
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			res.Defined = true
+
+			switch value := expression.Value.(type) {
+			case bool:
+				res.Allowed = res.Allowed && value
+			case []interface{}:
+				// Set or array of violations
+				for _, item := range value {
+					res.Messages = append(res.Messages, message(item))
+				}
+			case map[string]interface{}:
+				// Partial object keyed by violation message
+				keys := make([]string, 0, len(value))
+				for k := range value {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				res.Messages = append(res.Messages, keys...)
+			default:
+				return nil, errors.New("the policy must return a boolean or a collection of messages")
+			}
+		}
+	}
+	if len(res.Messages) > 0 {
+		res.Allowed = false
+	}
+
+	// No error
+	return res, nil
+}
+
+// Digest writes the content of the given policy files, directories and
+// bundle archives to the given writer, in a stable order.
+func Digest(w io.Writer, paths ...string) error {
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			// Read file content
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			// Length prefixed file path and content
+			for _, field := range [][]byte{[]byte(path), content} {
+				if err := binary.Write(w, binary.BigEndian, uint64(len(field))); err != nil {
+					return err
+				}
+				if _, err := w.Write(field); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("unable to digest policy path '%s': %w", root, err)
+		}
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func isBundlePath(path string) (bool, error) {
+	// Compressed bundle archive
+	if strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz") {
+		return true, nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("unable to access policy path '%s': %w", path, err)
+	}
+	if !fi.IsDir() {
+		return false, nil
+	}
+
+	// Directory bundle are identified by their manifest
+	if _, err := os.Stat(filepath.Join(path, ".manifest")); err == nil {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func message(item interface{}) string {
+	switch v := item.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		// Structured violation
+		if msg, ok := v["msg"].(string); ok {
+			return msg
+		}
+	}
+
+	return fmt.Sprintf("%v", item)
+}
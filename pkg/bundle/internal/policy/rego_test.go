@@ -0,0 +1,195 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package policy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	//nolint:staticcheck // deprecated package requires refactor
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDenyPolicy = `package harp
+
+deny[msg] {
+	not input.labels.owner
+	msg := "owner label is required"
+}
+
+deny[msg] {
+	input.labels.owner
+	not data.owners[input.labels.owner]
+	msg := sprintf("owner '%s' is not registered", [input.labels.owner])
+}
+`
+
+func mustWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func mustWriteBundleArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+}
+
+func TestPrepare(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "owners.json"), `{"owners": {"security": {}}}`)
+	mustWriteFile(t, filepath.Join(root, "owners.txt"), `security`)
+	mustWriteFile(t, filepath.Join(root, "policies", "deny.rego"), testDenyPolicy)
+	mustWriteFile(t, filepath.Join(root, "bundle", ".manifest"), `{"roots": [""]}`)
+	mustWriteFile(t, filepath.Join(root, "bundle", "deny.rego"), testDenyPolicy)
+	mustWriteFile(t, filepath.Join(root, "bundle", "owners", "data.json"), `{"security": {}}`)
+	mustWriteBundleArchive(t, filepath.Join(root, "bundle.tar.gz"), map[string]string{
+		"/.manifest":           `{"roots": [""]}`,
+		"/deny.rego":           testDenyPolicy,
+		"/owners/data.json":    `{"security": {}}`,
+		"/harp/compliant.rego": "package harp\n\ncompliant := count(deny) == 0\n",
+	})
+
+	tests := []struct {
+		name    string
+		src     *Source
+		input   map[string]interface{}
+		want    *Decision
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name: "blank query",
+			src: &Source{
+				Module: testDenyPolicy,
+			},
+			wantErr: true,
+		},
+		{
+			name: "no policy",
+			src: &Source{
+				Query: "data.harp.deny",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid data document",
+			src: &Source{
+				Query:  "data.harp.deny",
+				Module: testDenyPolicy,
+				Data:   []string{filepath.Join(root, "owners.txt")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing path",
+			src: &Source{
+				Query: "data.harp.deny",
+				Paths: []string{filepath.Join(root, "missing")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "undefined",
+			src: &Source{
+				Query:  "data.harp.compliant",
+				Module: testDenyPolicy,
+			},
+			want: &Decision{Allowed: true, Messages: []string{}},
+		},
+		{
+			name: "inline with data",
+			src: &Source{
+				Query:  "data.harp.deny",
+				Module: testDenyPolicy,
+				Data:   []string{filepath.Join(root, "owners.json")},
+			},
+			input: map[string]interface{}{"labels": map[string]interface{}{"owner": "unknown"}},
+			want:  &Decision{Defined: true, Allowed: false, Messages: []string{"owner 'unknown' is not registered"}},
+		},
+		{
+			name: "policy directory",
+			src: &Source{
+				Query: "data.harp.deny",
+				Paths: []string{filepath.Join(root, "policies")},
+				Data:  []string{filepath.Join(root, "owners.json")},
+			},
+			input: map[string]interface{}{"labels": map[string]interface{}{"owner": "security"}},
+			want:  &Decision{Defined: true, Allowed: true, Messages: []string{}},
+		},
+		{
+			name: "bundle directory",
+			src: &Source{
+				Query: "data.harp.deny",
+				Paths: []string{filepath.Join(root, "bundle")},
+			},
+			input: map[string]interface{}{"labels": map[string]interface{}{}},
+			want:  &Decision{Defined: true, Allowed: false, Messages: []string{"owner label is required"}},
+		},
+		{
+			name: "bundle archive",
+			src: &Source{
+				Query: "data.harp.compliant",
+				Paths: []string{filepath.Join(root, "bundle.tar.gz")},
+			},
+			input: map[string]interface{}{"labels": map[string]interface{}{"owner": "security"}},
+			want:  &Decision{Defined: true, Allowed: true, Messages: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := Prepare(context.Background(), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Prepare() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := Evaluate(context.Background(), query, rego.EvalInput(tt.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package jsonpatch applies RFC 6902 JSON Patch and RFC 7386 JSON Merge Patch
+// documents to the bundle map representation.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/compare"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+// JSONValueType is the secret value type used to store values without packed
+// representation (objects, arrays and decimal numbers) using their JSON
+// encoding.
+const JSONValueType = "json"
+
+// AsDocument returns the bundle map representation (package path => secret
+// key => value) as a generic JSON document.
+func AsDocument(b *bundlev1.Bundle) (map[string]interface{}, error) {
+	// Export as map
+	m, err := bundle.AsMap(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert nested maps
+	res := map[string]interface{}{}
+	for name, v := range m {
+		secrets, ok := v.(bundle.KV)
+		if !ok {
+			return nil, fmt.Errorf("unexpected package '%s' representation type %T", name, v)
+		}
+		res[name] = map[string]interface{}(secrets)
+	}
+
+	// Decode JSON encoded values
+	for _, p := range b.Packages {
+		if p == nil || p.Secrets == nil || p.Secrets.Locked != nil {
+			continue
+		}
+
+		secrets, _ := res[p.Name].(map[string]interface{})
+		for _, kv := range p.Secrets.Data {
+			if kv == nil || kv.Type != JSONValueType {
+				continue
+			}
+
+			raw, ok := secrets[kv.Key].(string)
+			if !ok {
+				return nil, fmt.Errorf("JSON value of `%s#%s` must be a string, got %T", p.Name, kv.Key, secrets[kv.Key])
+			}
+
+			var value interface{}
+			if err := json.Unmarshal([]byte(raw), &value); err != nil {
+				return nil, fmt.Errorf("unable to decode `%s#%s` JSON value: %w", p.Name, kv.Key, err)
+			}
+			secrets[kv.Key] = value
+		}
+	}
+
+	// No error
+	return res, nil
+}
+
+// Apply applies a RFC 6902 JSON Patch document to the bundle map
+// representation. Bundle and package metadata are preserved, including for
+// packages relocated using `move` and `copy` operations.
+func Apply(b *bundlev1.Bundle, patch compare.JSONPatch) (*bundlev1.Bundle, error) {
+	// Export bundle as a document
+	doc, err := AsDocument(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert bundle as a document: %w", err)
+	}
+
+	// Apply the patch
+	out, err := compare.ApplyJSONPatch(doc, patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply JSON patch: %w", err)
+	}
+
+	// Rebuild the bundle
+	return fromDocument(b, doc, out, relocations(patch))
+}
+
+// Merge applies a RFC 7386 JSON Merge Patch document to the bundle map
+// representation. Bundle and package metadata are preserved.
+func Merge(b *bundlev1.Bundle, patch map[string]interface{}) (*bundlev1.Bundle, error) {
+	// Export bundle as a document
+	doc, err := AsDocument(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert bundle as a document: %w", err)
+	}
+
+	// Rebuild the bundle
+	return fromDocument(b, doc, compare.ApplyMergePatch(doc, patch), map[string]string{})
+}
+
+// -----------------------------------------------------------------------------
+
+// relocations returns the original package name of all packages relocated by
+// the patch `move` and `copy` operations. Removed package names are mapped to
+// an empty origin so that a package added later at the same path starts
+// without metadata.
+func relocations(patch compare.JSONPatch) map[string]string {
+	origins := map[string]string{}
+	for _, op := range patch {
+		path, ok := packagePath(op.Path)
+		if !ok {
+			continue
+		}
+
+		switch op.Operation {
+		case compare.Remove:
+			origins[path] = ""
+		case compare.Move, compare.Copy:
+			from, ok := packagePath(op.From)
+			if !ok {
+				continue
+			}
+
+			// Follow relocation chains
+			origin, ok := origins[from]
+			if !ok {
+				origin = from
+			}
+			if op.Operation == compare.Move {
+				origins[from] = ""
+			}
+			origins[path] = origin
+		}
+	}
+
+	return origins
+}
+
+// packagePath returns the package name targeted by the given JSON pointer if
+// it references a whole package.
+func packagePath(pointer string) (string, bool) {
+	if !strings.HasPrefix(pointer, "/") {
+		return "", false
+	}
+
+	token := pointer[1:]
+	if strings.Contains(token, "/") {
+		return "", false
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"), true
+}
+
+func fromDocument(b *bundlev1.Bundle, src map[string]interface{}, out interface{}, origins map[string]string) (*bundlev1.Bundle, error) {
+	dst, ok := out.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("patched document must be an object, got %T", out)
+	}
+
+	// Copy bundle
+	bCopy, ok := proto.Clone(b).(*bundlev1.Bundle)
+	if !ok {
+		return nil, fmt.Errorf("the cloned bundle does not have the expected type: %T", bCopy)
+	}
+
+	// Index source packages
+	pkgIndex := map[string]*bundlev1.Package{}
+	for _, p := range bCopy.Packages {
+		if p == nil {
+			// Ignore nil package
+			continue
+		}
+		pkgIndex[p.Name] = p
+	}
+
+	// Resolve the package holding the metadata and the secrets of the target
+	origin := func(name string) *bundlev1.Package {
+		if from, ok := origins[name]; ok {
+			if from == "" {
+				return nil
+			}
+			return pkgIndex[from]
+		}
+		return pkgIndex[name]
+	}
+
+	packages := []*bundlev1.Package{}
+	for _, p := range bCopy.Packages {
+		if p == nil {
+			// Ignore nil package
+			continue
+		}
+
+		v, ok := dst[p.Name]
+		if !ok {
+			// Package has been removed
+			continue
+		}
+
+		updated, err := patchPackage(p.Name, origin(p.Name), src, v)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, updated)
+	}
+
+	// Add new packages
+	for _, name := range sortedKeys(dst) {
+		if _, ok := pkgIndex[name]; ok {
+			continue
+		}
+
+		p, err := patchPackage(name, origin(name), src, dst[name])
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, p)
+	}
+
+	// Assign packages
+	bCopy.Packages = packages
+
+	// No error
+	return bCopy, nil
+}
+
+//nolint:gocognit // To refactor
+func patchPackage(name string, base *bundlev1.Package, src map[string]interface{}, v interface{}) (*bundlev1.Package, error) {
+	// Start from the origin package to preserve its metadata
+	p := &bundlev1.Package{}
+	if base != nil {
+		var ok bool
+		if p, ok = proto.Clone(base).(*bundlev1.Package); !ok {
+			return nil, fmt.Errorf("the cloned package does not have the expected type: %T", p)
+		}
+	}
+	p.Name = name
+
+	// Unchanged package
+	var srcSecrets map[string]interface{}
+	if base != nil {
+		if reflect.DeepEqual(src[base.Name], v) {
+			return p, nil
+		}
+		srcSecrets, _ = src[base.Name].(map[string]interface{})
+	}
+
+	// Locked package can't be modified
+	if p.Secrets != nil && p.Secrets.Locked != nil {
+		return nil, fmt.Errorf("unable to modify locked package '%s'", name)
+	}
+
+	secrets, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("package '%s' value must be an object, got %T", name, v)
+	}
+
+	if p.Secrets == nil {
+		p.Secrets = &bundlev1.SecretChain{}
+	}
+
+	// Update existing secrets
+	data := []*bundlev1.KV{}
+	for _, kv := range p.Secrets.Data {
+		newValue, ok := secrets[kv.Key]
+		if !ok {
+			// Secret has been removed
+			continue
+		}
+		if !reflect.DeepEqual(srcSecrets[kv.Key], newValue) {
+			updated, err := packValue(name, kv.Key, newValue)
+			if err != nil {
+				return nil, err
+			}
+			kv = updated
+		}
+		data = append(data, kv)
+	}
+
+	// Add new secrets
+	for _, key := range sortedKeys(secrets) {
+		if _, ok := srcSecrets[key]; ok {
+			continue
+		}
+		kv, err := packValue(name, key, secrets[key])
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, kv)
+	}
+
+	p.Secrets.Data = data
+
+	// No error
+	return p, nil
+}
+
+func packValue(pkgName, key string, value interface{}) (*bundlev1.KV, error) {
+	// Keep native types supported by the secret packer
+	var data interface{}
+	switch v := value.(type) {
+	case string, bool, int64:
+		data = v
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			data = int64(v)
+		}
+	}
+
+	valueType := fmt.Sprintf("%T", data)
+
+	// Other values are stored using their JSON encoding
+	if data == nil {
+		out, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode `%s#%s` value as JSON: %w", pkgName, key, err)
+		}
+		data = string(out)
+		valueType = JSONValueType
+	}
+
+	// Pack secret value
+	packed, err := secret.Pack(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to pack secret value for `%s#%s`: %w", pkgName, key, err)
+	}
+
+	// No error
+	return &bundlev1.KV{
+		Key:   key,
+		Type:  valueType,
+		Value: packed,
+	}, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,256 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/compare"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+func testJSONPatchBundle() *bundlev1.Bundle {
+	return &bundlev1.Bundle{
+		Labels: map[string]string{
+			"owner": "security",
+		},
+		Packages: []*bundlev1.Package{
+			{
+				Name: "app/production/db",
+				Annotations: map[string]string{
+					"infosec.elastic.co/v1/SecretPolicy#rotationPeriod": "90d",
+				},
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "user", Type: "string", Value: secret.MustPack("admin")},
+						{Key: "password", Type: "string", Value: secret.MustPack("changeme")},
+					},
+				},
+			},
+			{
+				Name: "app/production/queue",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "token", Type: "string", Value: secret.MustPack("secret")},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	var patch compare.JSONPatch
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op":"replace","path":"/app~1production~1db/password","value":"updated"},
+		{"op":"add","path":"/app~1production~1db/port","value":5432},
+		{"op":"remove","path":"/app~1production~1queue"},
+		{"op":"add","path":"/app~1production~1cache","value":{"host":"localhost"}}
+	]`), &patch))
+
+	src := testJSONPatchBundle()
+	got, err := Apply(src, patch)
+	assert.NoError(t, err)
+
+	// Metadata must be preserved
+	assert.Equal(t, map[string]string{"owner": "security"}, got.Labels)
+	assert.Len(t, got.Packages, 2)
+	assert.Equal(t, "app/production/db", got.Packages[0].Name)
+	assert.Equal(t, "90d", got.Packages[0].Annotations["infosec.elastic.co/v1/SecretPolicy#rotationPeriod"])
+	assert.Equal(t, "app/production/cache", got.Packages[1].Name)
+
+	// Check the resulting map
+	out, err := bundle.AsMap(got)
+	assert.NoError(t, err)
+	if diff := cmp.Diff(out, bundle.KV{
+		"app/production/db": bundle.KV{
+			"user":     "admin",
+			"password": "updated",
+			"port":     int64(5432),
+		},
+		"app/production/cache": bundle.KV{
+			"host": "localhost",
+		},
+	}); diff != "" {
+		t.Errorf("Apply():\n-got/+want\ndiff %s", diff)
+	}
+
+	// Source bundle must not be altered
+	assert.Len(t, src.Packages, 2)
+	assert.Equal(t, "app/production/queue", src.Packages[1].Name)
+}
+
+func TestApply_Error(t *testing.T) {
+	var patch compare.JSONPatch
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op":"add","path":"/app~1production~1cache","value":"invalid"}
+	]`), &patch))
+
+	_, err := Apply(testJSONPatchBundle(), patch)
+	assert.Error(t, err)
+}
+
+func TestApply_NilPackage(t *testing.T) {
+	var patch compare.JSONPatch
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op":"replace","path":"/app~1production~1db/password","value":"updated"}
+	]`), &patch))
+
+	src := testJSONPatchBundle()
+	src.Packages = append([]*bundlev1.Package{nil}, src.Packages...)
+
+	got, err := Apply(src, patch)
+	assert.NoError(t, err)
+	assert.Len(t, got.Packages, 2)
+	assert.Equal(t, "app/production/db", got.Packages[0].Name)
+	assert.Equal(t, "app/production/queue", got.Packages[1].Name)
+
+	// Merge patches must ignore nil packages too
+	_, err = Merge(src, map[string]interface{}{})
+	assert.NoError(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	var patch map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"app/production/db": {"password": "updated", "user": null},
+		"app/production/queue": null
+	}`), &patch))
+
+	got, err := Merge(testJSONPatchBundle(), patch)
+	assert.NoError(t, err)
+
+	out, err := bundle.AsMap(got)
+	assert.NoError(t, err)
+	if diff := cmp.Diff(out, bundle.KV{
+		"app/production/db": bundle.KV{
+			"password": "updated",
+		},
+	}); diff != "" {
+		t.Errorf("Merge():\n-got/+want\ndiff %s", diff)
+	}
+}
+
+func TestApply_ValueTypes(t *testing.T) {
+	var patch compare.JSONPatch
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op":"add","path":"/app~1production~1db/port","value":5432},
+		{"op":"add","path":"/app~1production~1db/ssl","value":true},
+		{"op":"add","path":"/app~1production~1db/ratio","value":0.5},
+		{"op":"add","path":"/app~1production~1db/options","value":{"pool":{"size":10},"hosts":["a","b"]}}
+	]`), &patch))
+
+	got, err := Apply(testJSONPatchBundle(), patch)
+	assert.NoError(t, err)
+
+	types := map[string]string{}
+	for _, kv := range got.Packages[0].Secrets.Data {
+		types[kv.Key] = kv.Type
+	}
+	assert.Equal(t, map[string]string{
+		"user":     "string",
+		"password": "string",
+		"port":     "int64",
+		"ssl":      "bool",
+		"ratio":    JSONValueType,
+		"options":  JSONValueType,
+	}, types)
+
+	// Values must be restored with their original JSON types
+	doc, err := AsDocument(got)
+	assert.NoError(t, err)
+	if diff := cmp.Diff(doc["app/production/db"], map[string]interface{}{
+		"user":     "admin",
+		"password": "changeme",
+		"port":     int64(5432),
+		"ssl":      true,
+		"ratio":    0.5,
+		"options": map[string]interface{}{
+			"pool":  map[string]interface{}{"size": float64(10)},
+			"hosts": []interface{}{"a", "b"},
+		},
+	}); diff != "" {
+		t.Errorf("AsDocument():\n-got/+want\ndiff %s", diff)
+	}
+
+	// Unchanged values must be kept as-is on the next application
+	again, err := Apply(got, compare.JSONPatch{})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(got, again))
+}
+
+func TestApply_Relocation(t *testing.T) {
+	var patch compare.JSONPatch
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op":"move","from":"/app~1production~1db","path":"/app~1staging~1db"},
+		{"op":"copy","from":"/app~1staging~1db","path":"/app~1qa~1db"},
+		{"op":"replace","path":"/app~1qa~1db/password","value":"qa"},
+		{"op":"move","from":"/app~1production~1queue","path":"/app~1production~1broker"},
+		{"op":"add","path":"/app~1production~1queue","value":{}}
+	]`), &patch))
+
+	src := testJSONPatchBundle()
+	src.Packages[1].Labels = map[string]string{"tier": "backend"}
+
+	got, err := Apply(src, patch)
+	assert.NoError(t, err)
+
+	pkgs := map[string]*bundlev1.Package{}
+	for _, p := range got.Packages {
+		pkgs[p.Name] = p
+	}
+	assert.Len(t, pkgs, 4)
+
+	// Moved and copied packages keep the origin metadata
+	for _, name := range []string{"app/staging/db", "app/qa/db"} {
+		assert.Equal(t, "90d", pkgs[name].Annotations["infosec.elastic.co/v1/SecretPolicy#rotationPeriod"], name)
+	}
+	assert.Equal(t, map[string]string{"tier": "backend"}, pkgs["app/production/broker"].Labels)
+
+	// Re-created package doesn't inherit relocated package metadata
+	assert.Empty(t, pkgs["app/production/queue"].Labels)
+
+	// Source bundle must not be altered
+	assert.Equal(t, "app/production/db", src.Packages[0].Name)
+
+	out, err := bundle.AsMap(got)
+	assert.NoError(t, err)
+	if diff := cmp.Diff(out, bundle.KV{
+		"app/staging/db": bundle.KV{
+			"user":     "admin",
+			"password": "changeme",
+		},
+		"app/qa/db": bundle.KV{
+			"user":     "admin",
+			"password": "qa",
+		},
+		"app/production/broker": bundle.KV{
+			"token": "secret",
+		},
+		"app/production/queue": bundle.KV{},
+	}); diff != "" {
+		t.Errorf("Apply():\n-got/+want\ndiff %s", diff)
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/imdario/mergo"
 	"github.com/jmespath/go-jmespath"
+	"google.golang.org/protobuf/proto"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle/secret"
@@ -40,55 +41,181 @@ const (
 	packageUnchanged ruleAction = iota
 	packageUpdated
 	packagedRemoved
+	packageCopied
+	packageMoved
+	packageMerged
 )
 
 // -----------------------------------------------------------------------------
 
-func executeRule(r *bundlev1.PatchRule, p *bundlev1.Package, values map[string]interface{}) (ruleAction, error) {
+func executeRule(r *bundlev1.PatchRule, b *bundlev1.Bundle, p *bundlev1.Package, values map[string]interface{}) (ruleAction, *relocatedPackage, error) {
 	// Check parameters
 	if r == nil {
-		return packageUnchanged, fmt.Errorf("cannot process nil rule")
+		return packageUnchanged, nil, fmt.Errorf("cannot process nil rule")
 	}
 	if r.Package == nil {
-		return packageUnchanged, fmt.Errorf("cannot process rule with nil package")
+		return packageUnchanged, nil, fmt.Errorf("cannot process rule with nil package")
 	}
 	if p == nil {
-		return packageUnchanged, fmt.Errorf("cannot process nil package")
+		return packageUnchanged, nil, fmt.Errorf("cannot process nil package")
 	}
 
 	// Compile selector
-	s, err := compileSelector(r.Selector, values)
+	s, err := compileSelector(r.Selector, values, b)
 	if err != nil {
-		return packageUnchanged, fmt.Errorf("unable to compile selector: %w", err)
+		return packageUnchanged, nil, fmt.Errorf("unable to compile selector: %w", err)
 	}
 
 	// Package match selector specification
 	if s.IsSatisfiedBy(p) {
 		// Check removal request
 		if r.Package.Remove {
-			return packagedRemoved, nil
+			return packagedRemoved, nil, nil
+		}
+
+		// Check copy, move or merge request
+		action, target, err := packageTarget(r.Package)
+		if err != nil {
+			return packageUnchanged, nil, fmt.Errorf("invalid package target: %w", err)
+		}
+		if target != nil {
+			return relocateRule(action, target, r.Package, p, values)
 		}
 
 		// Apply patch
 		if err := applyPackagePatch(p, r.Package, values); err != nil {
-			return packageUnchanged, fmt.Errorf("unable to apply patch to package `%s`: %w", p.Name, err)
+			return packageUnchanged, nil, fmt.Errorf("unable to apply patch to package `%s`: %w", p.Name, err)
 		}
 
 		// No error
-		return packageUpdated, nil
+		return packageUpdated, nil, nil
 	}
 
 	// No error
-	return packageUnchanged, nil
+	return packageUnchanged, nil, nil
 }
 
-//nolint:gocyclo,funlen // to refactor
-func compileSelector(s *bundlev1.PatchSelector, values map[string]interface{}) (selector.Specification, error) {
+func relocateRule(action ruleAction, target *bundlev1.PatchPackageTarget, op *bundlev1.PatchPackage, p *bundlev1.Package, values map[string]interface{}) (ruleAction, *relocatedPackage, error) {
+	// Resolve target path using the original package
+	targetPath, err := resolveTargetPath(p, target, values)
+	if err != nil {
+		return packageUnchanged, nil, fmt.Errorf("unable to resolve target path: %w", err)
+	}
+
+	// Locked secrets can't be merged or patched
+	if isLocked(p) {
+		if action == packageMerged {
+			return packageUnchanged, nil, fmt.Errorf("unable to merge locked package `%s`", p.Name)
+		}
+		if op.Data != nil {
+			return packageUnchanged, nil, fmt.Errorf("unable to patch secrets of locked package `%s`", p.Name)
+		}
+	}
+
+	// Source package is kept untouched until the relocation is applied
+	out, ok := proto.Clone(p).(*bundlev1.Package)
+	if !ok {
+		return packageUnchanged, nil, fmt.Errorf("the cloned package does not have the expected type: %T", out)
+	}
+
+	// Apply patch to the relocated package
+	if err := applyPackagePatch(out, op, values); err != nil {
+		return packageUnchanged, nil, fmt.Errorf("unable to apply patch to package `%s`: %w", p.Name, err)
+	}
+	out.Name = targetPath
+
+	// No error
+	return action, &relocatedPackage{
+		action:     action,
+		source:     p,
+		pkg:        out,
+		onConflict: target.OnConflict,
+	}, nil
+}
+
+// CompileSelector builds a package matcher specification from the given
+// selector. Leaf selectors and combinators are associated with AND logic.
+func CompileSelector(s *bundlev1.PatchSelector, values map[string]interface{}) (selector.Specification, error) {
+	return compileSelector(s, values, nil)
+}
+
+// -----------------------------------------------------------------------------
+
+// compileSelector builds the package matcher specification, the given bundle
+// is exposed to Rego selectors as `input.bundle`.
+func compileSelector(s *bundlev1.PatchSelector, values map[string]interface{}, b *bundlev1.Bundle) (selector.Specification, error) {
 	// Check parameters
 	if s == nil {
 		return nil, fmt.Errorf("cannot process nil selector")
 	}
 
+	specs := []selector.Specification{}
+
+	// Has allOf combinator
+	if len(s.AllOf) > 0 {
+		subSpecs, err := compileSelectors(s.AllOf, values, b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile allOf selector: %w", err)
+		}
+		specs = append(specs, selector.AllOf(subSpecs...))
+	}
+
+	// Has anyOf combinator
+	if len(s.AnyOf) > 0 {
+		subSpecs, err := compileSelectors(s.AnyOf, values, b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile anyOf selector: %w", err)
+		}
+		specs = append(specs, selector.AnyOf(subSpecs...))
+	}
+
+	// Has not combinator
+	if s.Not != nil {
+		subSpec, err := compileSelector(s.Not, values, b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile not selector: %w", err)
+		}
+		specs = append(specs, selector.Not(subSpec))
+	}
+
+	// Has leaf selector
+	if hasLeafSelector(s) {
+		spec, err := compileLeafSelector(s, values, b)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	switch len(specs) {
+	case 0:
+		return nil, fmt.Errorf("no supported selector specified")
+	case 1:
+		return specs[0], nil
+	default:
+		return selector.AllOf(specs...), nil
+	}
+}
+
+func compileSelectors(selectors []*bundlev1.PatchSelector, values map[string]interface{}, b *bundlev1.Bundle) ([]selector.Specification, error) {
+	res := make([]selector.Specification, 0, len(selectors))
+	for i, s := range selectors {
+		spec, err := compileSelector(s, values, b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile selector %d: %w", i, err)
+		}
+		res = append(res, spec)
+	}
+
+	return res, nil
+}
+
+func hasLeafSelector(s *bundlev1.PatchSelector) bool {
+	return s.MatchPath != nil || s.JmesPath != "" || s.MatchSecret != nil || s.MatchValue != nil || s.RegoFile != "" || s.Rego != "" || len(s.RegoPaths) > 0 || len(s.Cel) > 0
+}
+
+//nolint:gocyclo,funlen // to refactor
+func compileLeafSelector(s *bundlev1.PatchSelector, values map[string]interface{}, b *bundlev1.Bundle) (selector.Specification, error) {
 	// Has matchPath selector
 	if s.MatchPath != nil {
 		switch {
@@ -189,6 +316,23 @@ func compileSelector(s *bundlev1.PatchSelector, values map[string]interface{}) (
 		}
 	}
 
+	// Has matchValue selector
+	if s.MatchValue != nil {
+		return compileValueSelector(s.MatchValue, values)
+	}
+
+	// Rego evaluation options
+	regoOpts := []selector.RegoOptionFunc{
+		selector.WithRegoPaths(s.RegoPaths...),
+		selector.WithRegoData(s.RegoData...),
+	}
+	if b != nil {
+		regoOpts = append(regoOpts, selector.WithRegoBundle(b))
+	}
+	if s.RegoQuery != "" {
+		regoOpts = append(regoOpts, selector.WithRegoQuery(s.RegoQuery))
+	}
+
 	if s.RegoFile != "" {
 		// Read policy file
 		policyFile, err := os.ReadFile(s.RegoFile)
@@ -197,13 +341,13 @@ func compileSelector(s *bundlev1.PatchSelector, values map[string]interface{}) (
 		}
 
 		// Build the specification
-		return selector.MatchRego(context.Background(), string(policyFile))
+		return selector.MatchRego(context.Background(), string(policyFile), regoOpts...)
 	}
 
 	// Has rego policy
-	if s.Rego != "" {
+	if s.Rego != "" || len(s.RegoPaths) > 0 {
 		// Return specification
-		return selector.MatchRego(context.Background(), s.Rego)
+		return selector.MatchRego(context.Background(), s.Rego, regoOpts...)
 	}
 
 	// Has CEL expressions
@@ -216,6 +360,55 @@ func compileSelector(s *bundlev1.PatchSelector, values map[string]interface{}) (
 	return nil, fmt.Errorf("no supported selector specified")
 }
 
+func compileValueSelector(s *bundlev1.PatchSelectorMatchValue, values map[string]interface{}) (selector.Specification, error) {
+	// Evaluation with template engine first
+	render := func(tmpl string) (string, error) {
+		if tmpl == "" {
+			return "", nil
+		}
+		value, err := engine.Render(tmpl, map[string]interface{}{
+			"Values": values,
+		})
+		if err != nil {
+			return "", fmt.Errorf("unable to evaluate template before matchValue build: %w", err)
+		}
+		return value, nil
+	}
+
+	key, err := render(s.Key)
+	if err != nil {
+		return nil, err
+	}
+	regex, err := render(s.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []selector.ValueOptionFunc{
+		selector.WithValueKey(key),
+		selector.WithValueType(s.Type),
+		selector.WithValueSize(int(s.MinSize), int(s.MaxSize)),
+		selector.WithValueRegex(regex),
+		selector.WithValueFormat(s.Format),
+		selector.WithValueKeyType(s.KeyType, int(s.KeyBits)),
+	}
+
+	// Keyed hash equality
+	if s.Hash != nil {
+		hashKey, err := render(s.Hash.Key)
+		if err != nil {
+			return nil, err
+		}
+		if s.Hash.Value == "" {
+			return nil, errors.New("hash value must not be blank for value matcher")
+		}
+		opts = append(opts, selector.WithValueHash(hashKey, s.Hash.Value))
+	}
+
+	// Return specification
+	return selector.MatchValue(opts...)
+}
+
 func applyPackagePatch(pkg *bundlev1.Package, p *bundlev1.PatchPackage, values map[string]interface{}) error {
 	// Check parameters
 	if pkg == nil {
@@ -28,7 +28,7 @@ import (
 func Test_executeRule_Fuzz(t *testing.T) {
 	// Making sure the executeRule never panics
 	for i := 0; i < 50; i++ {
-		f := fuzz.New()
+		f := fuzz.New().Funcs(fuzzPatchSelector)
 
 		// Prepare arguments
 		values := map[string]interface{}{}
@@ -65,14 +65,14 @@ func Test_executeRule_Fuzz(t *testing.T) {
 		f.Fuzz(&spec.Spec.Rules[0])
 
 		// Execute
-		executeRule(spec.Spec.Rules[0], &p, values)
+		executeRule(spec.Spec.Rules[0], nil, &p, values)
 	}
 }
 
 func Test_compileSelector_Fuzz(t *testing.T) {
-	// Making sure the compileSelector never panics
+	// Making sure the CompileSelector never panics
 	for i := 0; i < 50; i++ {
-		f := fuzz.New()
+		f := fuzz.New().Funcs(fuzzPatchSelector)
 
 		// Prepare arguments
 		values := map[string]interface{}{}
@@ -95,7 +95,7 @@ func Test_compileSelector_Fuzz(t *testing.T) {
 		f.Fuzz(&spec.Spec.Rules[0].Selector)
 
 		// Execute
-		compileSelector(spec.Spec.Rules[0].Selector, values)
+		CompileSelector(spec.Spec.Rules[0].Selector, values)
 	}
 }
 
@@ -227,3 +227,35 @@ func Test_applySecretKVPatch_Fuzz(t *testing.T) {
 		applySecretKVPatch(file.Packages[0].Secrets.Data, spec, values)
 	}
 }
+
+// fuzzPatchSelector limits selector combinators recursion to one level.
+func fuzzPatchSelector(s *bundlev1.PatchSelector, c fuzz.Continue) {
+	fuzzLeafPatchSelector(s, c)
+
+	if c.RandBool() {
+		s.Not = &bundlev1.PatchSelector{}
+		fuzzLeafPatchSelector(s.Not, c)
+	}
+	if c.RandBool() {
+		s.AllOf = []*bundlev1.PatchSelector{{}, {}}
+		for _, sub := range s.AllOf {
+			fuzzLeafPatchSelector(sub, c)
+		}
+	}
+	if c.RandBool() {
+		s.AnyOf = []*bundlev1.PatchSelector{{}, {}}
+		for _, sub := range s.AnyOf {
+			fuzzLeafPatchSelector(sub, c)
+		}
+	}
+}
+
+func fuzzLeafPatchSelector(s *bundlev1.PatchSelector, c fuzz.Continue) {
+	c.Fuzz(&s.MatchPath)
+	c.Fuzz(&s.JmesPath)
+	c.Fuzz(&s.Rego)
+	c.Fuzz(&s.RegoFile)
+	c.Fuzz(&s.MatchSecret)
+	c.Fuzz(&s.MatchValue)
+	c.Fuzz(&s.Cel)
+}
@@ -28,6 +28,7 @@ import (
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
 	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/template/values/schema"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -59,6 +60,28 @@ func Validate(spec *bundlev1.Patch) error {
 	return nil
 }
 
+// ValidateValues checks the given values using the patch values schema.
+func ValidateValues(spec *bundlev1.Patch, values map[string]interface{}) error {
+	// Check if a schema is defined
+	if spec == nil || spec.Spec == nil || spec.Spec.ValuesSchema == nil {
+		return nil
+	}
+
+	// Encode schema definition
+	definition, err := schema.FromStruct(spec.Spec.ValuesSchema)
+	if err != nil {
+		return fmt.Errorf("unable to prepare values schema: %w", err)
+	}
+
+	// Validate values
+	if err := schema.Validate(definition, values); err != nil {
+		return fmt.Errorf("invalid patch values: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
 // Checksum calculates the bundle patch checksum.
 func Checksum(spec *bundlev1.Patch) (string, error) {
 	// Validate bundle template
@@ -90,6 +113,12 @@ func Apply(spec *bundlev1.Patch, b *bundlev1.Bundle, values map[string]interface
 	if b == nil {
 		return nil, fmt.Errorf("cannot process nil bundle")
 	}
+	if err := ValidateValues(spec, values); err != nil {
+		return nil, err
+	}
+	if err := CheckPreconditions(spec, b, values); err != nil {
+		return nil, err
+	}
 
 	// Prepare selectors
 	if len(spec.Spec.Rules) == 0 {
@@ -141,7 +170,7 @@ func Apply(spec *bundlev1.Patch, b *bundlev1.Bundle, values map[string]interface
 			Name: r.Selector.MatchPath.Strict,
 		}
 
-		_, err := executeRule(r, p, values)
+		_, _, err := executeRule(r, bCopy, p, values)
 		if err != nil {
 			return nil, fmt.Errorf("unable to execute rule index %d: %w", i, err)
 		}
@@ -163,27 +192,54 @@ func Apply(spec *bundlev1.Patch, b *bundlev1.Bundle, values map[string]interface
 		}
 
 		// Process all packages
-		for i, p := range bCopy.Packages {
-			action, err := executeRule(r, p, values)
+		packages := make([]*bundlev1.Package, 0, len(bCopy.Packages))
+		relocated := []*relocatedPackage{}
+		for _, p := range bCopy.Packages {
+			action, rp, err := executeRule(r, bCopy, p, values)
 			if err != nil {
 				return nil, fmt.Errorf("unable to execute rule index %d: %w", ri, err)
 			}
 
 			switch action {
 			case packagedRemoved:
-				bCopy.Packages = append(bCopy.Packages[:i], bCopy.Packages[i+1:]...)
+				// Drop the package
+				continue
 			case packageUpdated:
 				if WithAnnotations(spec) {
 					// Add annotations to mark package as patched.
 					bundle.Annotate(p, "patched", "true")
 					bundle.Annotate(p, spec.Meta.Name, "true")
 				}
-				bCopy.Packages[i] = p
+			case packageCopied, packageMoved, packageMerged:
+				if WithAnnotations(spec) {
+					// Add annotations to mark package as patched.
+					bundle.Annotate(rp.pkg, "patched", "true")
+					bundle.Annotate(rp.pkg, spec.Meta.Name, "true")
+				}
+				relocated = append(relocated, rp)
+
+				// Source package is replaced by the relocated one
+				if action != packageCopied {
+					continue
+				}
 			case packageUnchanged:
 				// No changes
 			default:
 			}
+
+			packages = append(packages, p)
+		}
+
+		// Apply copy, move and merge operations
+		for _, rp := range relocated {
+			var err error
+			packages, err = relocatePackage(packages, rp)
+			if err != nil {
+				return nil, fmt.Errorf("unable to execute rule index %d: %w", ri, err)
+			}
 		}
+
+		bCopy.Packages = packages
 	}
 
 	// Sort packages
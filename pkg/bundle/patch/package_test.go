@@ -25,6 +25,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	fuzz "github.com/google/gofuzz"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
@@ -47,6 +49,7 @@ var (
 		cmpopts.IgnoreUnexported(bundlev1.Package{}),
 		cmpopts.IgnoreUnexported(bundlev1.SecretChain{}),
 		cmpopts.IgnoreUnexported(bundlev1.KV{}),
+		cmpopts.IgnoreUnexported(wrapperspb.BytesValue{}),
 		opt,
 	}
 )
@@ -182,7 +185,13 @@ func TestChecksum(t *testing.T) {
 func TestApply_Fuzz(t *testing.T) {
 	// Making sure the descrption never panics
 	for i := 0; i < 500; i++ {
-		f := fuzz.New()
+		f := fuzz.New().Funcs(
+			// Protobuf struct values use oneof interfaces not supported by the fuzzer
+			func(s **structpb.Struct, c fuzz.Continue) {
+				*s = nil
+			},
+			fuzzPatchSelector,
+		)
 
 		// Prepare arguments
 		values := map[string]interface{}{}
@@ -394,6 +403,40 @@ func TestApply(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "remove package with rego bundle context",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/rego-bundle-context.yaml"),
+				b: &bundlev1.Bundle{
+					Labels: map[string]string{
+						"environment": "staging",
+					},
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/preview",
+							Labels: map[string]string{
+								"ephemeral": "true",
+							},
+						},
+						{
+							Name: "application/component",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Labels: map[string]string{
+					"environment": "staging",
+				},
+				Packages: []*bundlev1.Package{
+					{
+						Name: "application/component",
+					},
+				},
+			},
+		},
 		{
 			name: "remove secrets with secret matcher",
 			args: args{
@@ -678,6 +721,528 @@ func TestApply(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "copy package",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/copy-package.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/global/legacy/v1.0.0/database",
+						Annotations: map[string]string{
+							"package-copier": "true",
+							"patched":        "true",
+						},
+						Labels: map[string]string{
+							"copied": "true",
+						},
+					},
+					{
+						Name: "application/legacy/database",
+					},
+				},
+			},
+		},
+		{
+			name: "copy package - conflict",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/copy-package.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/global/legacy/v1.0.0/database",
+						},
+						{
+							Name: "application/legacy/database",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "move packages",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/move-packages.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/global/legacy/v1.0.0/database",
+						},
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("v1"),
+									},
+								},
+							},
+						},
+						{
+							Name: "application/legacy/queue",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/global/legacy/v1.0.0/database",
+						Annotations: map[string]string{
+							"package-mover": "true",
+							"patched":       "true",
+						},
+						Secrets: &bundlev1.SecretChain{
+							Data: []*bundlev1.KV{
+								{
+									Key:   "host",
+									Value: []byte("v1"),
+								},
+							},
+						},
+					},
+					{
+						Name: "app/production/global/legacy/v1.0.0/queue",
+						Annotations: map[string]string{
+							"package-mover": "true",
+							"patched":       "true",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "move packages - skip conflict",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/move-packages-skip.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/global/legacy/v1.0.0/database",
+						},
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("v1"),
+									},
+								},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/global/legacy/v1.0.0/database",
+					},
+					{
+						Name: "application/legacy/database",
+						Secrets: &bundlev1.SecretChain{
+							Data: []*bundlev1.KV{
+								{
+									Key:   "host",
+									Value: []byte("v1"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "copy locked package",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/copy-package.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Locked: &wrapperspb.BytesValue{Value: []byte("locked")},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/global/legacy/v1.0.0/database",
+						Annotations: map[string]string{
+							"package-copier": "true",
+							"patched":        "true",
+						},
+						Labels: map[string]string{
+							"copied": "true",
+						},
+						Secrets: &bundlev1.SecretChain{
+							Locked: &wrapperspb.BytesValue{Value: []byte("locked")},
+						},
+					},
+					{
+						Name: "application/legacy/database",
+						Secrets: &bundlev1.SecretChain{
+							Locked: &wrapperspb.BytesValue{Value: []byte("locked")},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "merge locked package",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/merge-packages.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Locked: &wrapperspb.BytesValue{Value: []byte("locked")},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge into locked package",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/merge-packages.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/global/legacy/v1.0.0/config",
+							Secrets: &bundlev1.SecretChain{
+								Locked: &wrapperspb.BytesValue{Value: []byte("locked")},
+							},
+						},
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("db"),
+									},
+								},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge packages",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/merge-packages.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+							Labels: map[string]string{
+								"owner": "database",
+							},
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("db"),
+									},
+									{
+										Key:   "port",
+										Value: []byte("5432"),
+									},
+								},
+							},
+						},
+						{
+							Name: "application/legacy/queue",
+							Labels: map[string]string{
+								"owner": "queue",
+							},
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("queue"),
+									},
+									{
+										Key:   "topic",
+										Value: []byte("events"),
+									},
+								},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/global/legacy/v1.0.0/config",
+						Labels: map[string]string{
+							"owner": "database",
+						},
+						Secrets: &bundlev1.SecretChain{
+							Data: []*bundlev1.KV{
+								{
+									Key:   "host",
+									Value: []byte("db"),
+								},
+								{
+									Key:   "port",
+									Value: []byte("5432"),
+								},
+								{
+									Key:   "topic",
+									Value: []byte("events"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "values schema",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/values-schema.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+						},
+					},
+				},
+				values: map[string]interface{}{
+					"owner": "security",
+				},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "application/legacy/database",
+						Annotations: map[string]string{
+							"package-labeler": "true",
+							"patched":         "true",
+						},
+						Labels: map[string]string{
+							"owner": "security",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "values schema - missing value",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/values-schema.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "values schema - invalid value",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/values-schema.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+						},
+					},
+				},
+				values: map[string]interface{}{
+					"owner": 1,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "preconditions",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/preconditions.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("db"),
+									},
+								},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{
+					"fingerprintKey": "fingerprint-key",
+				},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "application/legacy/database",
+						Annotations: map[string]string{
+							"database-host-updater": "true",
+							"patched":               "true",
+						},
+						Labels: map[string]string{
+							"updated": "true",
+						},
+						Secrets: &bundlev1.SecretChain{
+							Data: []*bundlev1.KV{
+								{
+									Key:   "host",
+									Value: []byte("db"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "preconditions - secret changed",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/preconditions.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "application/legacy/database",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "host",
+										Value: []byte("db2"),
+									},
+								},
+							},
+						},
+					},
+				},
+				values: map[string]interface{}{
+					"fingerprintKey": "fingerprint-key",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "composite selector",
+			args: args{
+				spec: mustLoadPatch("../../../test/fixtures/patch/valid/composite-selector.yaml"),
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/database/primary",
+						},
+						{
+							Name: "app/production/database/legacy",
+							Labels: map[string]string{
+								"legacy": "true",
+							},
+						},
+						{
+							Name: "app/production/billing/api",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{
+										Key:   "DB_PASSWORD",
+										Value: []byte("secret"),
+									},
+								},
+							},
+						},
+						{
+							Name: "app/staging/database/primary",
+						},
+					},
+				},
+				values: map[string]interface{}{},
+			},
+			wantErr: false,
+			want: &bundlev1.Bundle{
+				Packages: []*bundlev1.Package{
+					{
+						Name: "app/production/billing/api",
+						Annotations: map[string]string{
+							"composite-selector": "true",
+							"patched":            "true",
+						},
+						Labels: map[string]string{
+							"rotation": "required",
+						},
+						Secrets: &bundlev1.SecretChain{
+							Data: []*bundlev1.KV{
+								{
+									Key:   "DB_PASSWORD",
+									Value: []byte("secret"),
+								},
+							},
+						},
+					},
+					{
+						Name: "app/production/database/legacy",
+						Labels: map[string]string{
+							"legacy": "true",
+						},
+					},
+					{
+						Name: "app/production/database/primary",
+						Annotations: map[string]string{
+							"composite-selector": "true",
+							"patched":            "true",
+						},
+						Labels: map[string]string{
+							"rotation": "required",
+						},
+					},
+					{
+						Name: "app/staging/database/primary",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
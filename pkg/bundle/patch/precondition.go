@@ -0,0 +1,264 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package patch
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/sdk/security"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+// FingerprintKeyTemplate is the fingerprint key reference set in generated
+// patches, the key itself is provided as a value during patch application.
+const FingerprintKeyTemplate = "{{ .Values.fingerprintKey }}"
+
+// PreconditionError is raised when the input bundle doesn't match the patch
+// preconditions.
+type PreconditionError struct {
+	Violations []string
+}
+
+// Error returns the error message with all violations.
+func (e *PreconditionError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("bundle doesn't match patch preconditions:")
+	for _, v := range e.Violations {
+		fmt.Fprintf(&sb, "\n - %s", v)
+	}
+
+	return sb.String()
+}
+
+// -----------------------------------------------------------------------------
+
+// Fingerprint returns the secret value fingerprint used by patch
+// preconditions. The fingerprint is keyed to prevent offline value guessing
+// from the patch content.
+func Fingerprint(key []byte, kv *bundlev1.KV) string {
+	if kv == nil {
+		return ""
+	}
+
+	h := hmac.New(sha256.New, key)
+	h.Write(kv.Value)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprints returns the secret value fingerprints of the given secret
+// references ('path#key') from the given bundle.
+func Fingerprints(b *bundlev1.Bundle, key []byte, refs ...string) ([]*bundlev1.PatchSecretFingerprint, error) {
+	// Check parameters
+	if b == nil {
+		return nil, fmt.Errorf("cannot process nil bundle")
+	}
+	if len(key) == 0 {
+		return nil, errors.New("fingerprint key must not be blank")
+	}
+
+	// Index packages
+	pkgIndex := packageIndex(b)
+
+	res := []*bundlev1.PatchSecretFingerprint{}
+	for _, ref := range refs {
+		idx := strings.LastIndex(ref, "#")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid secret reference '%s', expected 'path#key'", ref)
+		}
+		path, secretKey := ref[:idx], ref[idx+1:]
+
+		kv := lookupSecret(pkgIndex, path, secretKey)
+		if kv == nil {
+			return nil, fmt.Errorf("secret '%s' doesn't exist", ref)
+		}
+
+		res = append(res, &bundlev1.PatchSecretFingerprint{
+			Path:        path,
+			Key:         secretKey,
+			Fingerprint: Fingerprint(key, kv),
+		})
+	}
+
+	// No error
+	return res, nil
+}
+
+// MerkleTreeRoot returns the encoded bundle merkle tree root used by patch
+// preconditions.
+func MerkleTreeRoot(b *bundlev1.Bundle) (string, error) {
+	// Check parameters
+	if b == nil {
+		return "", fmt.Errorf("cannot process nil bundle")
+	}
+
+	// Tree computation sorts packages, work on a copy
+	bCopy, ok := proto.Clone(b).(*bundlev1.Bundle)
+	if !ok {
+		return "", fmt.Errorf("the cloned bundle does not have the expected type: %T", bCopy)
+	}
+
+	// Compute merkle tree
+	tree, _, err := bundle.Tree(bCopy)
+	if err != nil {
+		return "", fmt.Errorf("unable to compute bundle merkle tree: %w", err)
+	}
+
+	// No error
+	return base64.RawURLEncoding.EncodeToString(tree.Root()), nil
+}
+
+// Preconditions returns patch preconditions bound to the given bundle state.
+func Preconditions(b *bundlev1.Bundle) (*bundlev1.PatchPreconditions, error) {
+	// Compute bundle identifier
+	root, err := MerkleTreeRoot(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// No error
+	return &bundlev1.PatchPreconditions{
+		MerkleTreeRoot: root,
+	}, nil
+}
+
+// CheckPreconditions ensures that the given bundle matches the patch
+// preconditions. All violations are reported.
+//
+//nolint:gocyclo // To refactor
+func CheckPreconditions(spec *bundlev1.Patch, b *bundlev1.Bundle, values map[string]interface{}) error {
+	// Check if preconditions are defined
+	if spec == nil || spec.Spec == nil || spec.Spec.Preconditions == nil {
+		return nil
+	}
+	if b == nil {
+		return fmt.Errorf("cannot process nil bundle")
+	}
+
+	pre := spec.Spec.Preconditions
+	violations := []string{}
+
+	// Check bundle state
+	if pre.MerkleTreeRoot != "" {
+		root, err := MerkleTreeRoot(b)
+		if err != nil {
+			return err
+		}
+		if !security.SecureCompareString(root, pre.MerkleTreeRoot) {
+			violations = append(violations, fmt.Sprintf("merkle tree root is '%s', expected '%s'", root, pre.MerkleTreeRoot))
+		}
+	}
+
+	// Index packages
+	pkgIndex := packageIndex(b)
+
+	// Check package paths
+	for _, path := range pre.RequiredPaths {
+		if _, ok := pkgIndex[path]; !ok {
+			violations = append(violations, fmt.Sprintf("required package `%s` doesn't exist", path))
+		}
+	}
+	for _, path := range pre.ForbiddenPaths {
+		if _, ok := pkgIndex[path]; ok {
+			violations = append(violations, fmt.Sprintf("forbidden package `%s` exists", path))
+		}
+	}
+
+	// Resolve fingerprint key
+	var fingerprintKey []byte
+	if len(pre.Fingerprints) > 0 {
+		key, err := engine.Render(pre.FingerprintKey, map[string]interface{}{
+			"Values": values,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to evaluate fingerprint key template: %w", err)
+		}
+		if strings.TrimSpace(key) == "" {
+			return errors.New("fingerprint key must not be blank when secret fingerprints are defined")
+		}
+		fingerprintKey = []byte(key)
+	}
+
+	// Check secret fingerprints
+	for _, f := range pre.Fingerprints {
+		if f == nil {
+			continue
+		}
+
+		if p, ok := pkgIndex[f.Path]; !ok || p.Secrets == nil {
+			violations = append(violations, fmt.Sprintf("package `%s` of fingerprinted secret `%s` doesn't exist", f.Path, f.Key))
+			continue
+		}
+
+		kv := lookupSecret(pkgIndex, f.Path, f.Key)
+		if kv == nil {
+			violations = append(violations, fmt.Sprintf("fingerprinted secret `%s#%s` doesn't exist", f.Path, f.Key))
+			continue
+		}
+
+		if !security.SecureCompareString(Fingerprint(fingerprintKey, kv), f.Fingerprint) {
+			violations = append(violations, fmt.Sprintf("secret `%s#%s` fingerprint doesn't match", f.Path, f.Key))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PreconditionError{Violations: violations}
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func packageIndex(b *bundlev1.Bundle) map[string]*bundlev1.Package {
+	pkgIndex := map[string]*bundlev1.Package{}
+	for _, p := range b.Packages {
+		if p == nil {
+			continue
+		}
+		pkgIndex[p.Name] = p
+	}
+
+	return pkgIndex
+}
+
+func lookupSecret(pkgIndex map[string]*bundlev1.Package, path, key string) *bundlev1.KV {
+	p, ok := pkgIndex[path]
+	if !ok || p.Secrets == nil {
+		return nil
+	}
+
+	for _, s := range p.Secrets.Data {
+		if s != nil && s.Key == key {
+			return s
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+)
+
+func testPreconditionBundle() *bundlev1.Bundle {
+	return &bundlev1.Bundle{
+		Packages: []*bundlev1.Package{
+			{
+				Name: "application/legacy/queue",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{
+							Key:   "host",
+							Value: []byte("queue"),
+						},
+					},
+				},
+			},
+			{
+				Name: "application/legacy/database",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{
+							Key:   "host",
+							Value: []byte("db"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	root, err := MerkleTreeRoot(testPreconditionBundle())
+	if err != nil {
+		t.Fatalf("unable to compute merkle tree root: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		preconditions  *bundlev1.PatchPreconditions
+		values         map[string]interface{}
+		wantErr        bool
+		wantViolations int
+	}{
+		{
+			name:    "no preconditions",
+			wantErr: false,
+		},
+		{
+			name: "merkle tree root",
+			preconditions: &bundlev1.PatchPreconditions{
+				MerkleTreeRoot: root,
+			},
+			wantErr: false,
+		},
+		{
+			name: "merkle tree root mismatch",
+			preconditions: &bundlev1.PatchPreconditions{
+				MerkleTreeRoot: "invalid",
+			},
+			wantErr:        true,
+			wantViolations: 1,
+		},
+		{
+			name: "paths",
+			preconditions: &bundlev1.PatchPreconditions{
+				RequiredPaths:  []string{"application/legacy/database"},
+				ForbiddenPaths: []string{"application/legacy/database-v2"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "paths mismatch",
+			preconditions: &bundlev1.PatchPreconditions{
+				RequiredPaths:  []string{"application/legacy/database-v2"},
+				ForbiddenPaths: []string{"application/legacy/database"},
+			},
+			wantErr:        true,
+			wantViolations: 2,
+		},
+		{
+			name: "fingerprints",
+			preconditions: &bundlev1.PatchPreconditions{
+				FingerprintKey: "{{ .Values.fingerprintKey }}",
+				Fingerprints: []*bundlev1.PatchSecretFingerprint{
+					{
+						Path:        "application/legacy/database",
+						Key:         "host",
+						Fingerprint: Fingerprint([]byte("fingerprint-key"), &bundlev1.KV{Value: []byte("db")}),
+					},
+				},
+			},
+			values: map[string]interface{}{
+				"fingerprintKey": "fingerprint-key",
+			},
+			wantErr: false,
+		},
+		{
+			name: "fingerprints key mismatch",
+			preconditions: &bundlev1.PatchPreconditions{
+				FingerprintKey: "another-key",
+				Fingerprints: []*bundlev1.PatchSecretFingerprint{
+					{
+						Path:        "application/legacy/database",
+						Key:         "host",
+						Fingerprint: Fingerprint([]byte("fingerprint-key"), &bundlev1.KV{Value: []byte("db")}),
+					},
+				},
+			},
+			wantErr:        true,
+			wantViolations: 1,
+		},
+		{
+			name: "fingerprints blank key",
+			preconditions: &bundlev1.PatchPreconditions{
+				FingerprintKey: "{{ .Values.fingerprintKey }}",
+				Fingerprints: []*bundlev1.PatchSecretFingerprint{
+					{
+						Path:        "application/legacy/database",
+						Key:         "host",
+						Fingerprint: Fingerprint([]byte(""), &bundlev1.KV{Value: []byte("db")}),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fingerprints mismatch",
+			preconditions: &bundlev1.PatchPreconditions{
+				FingerprintKey: "fingerprint-key",
+				Fingerprints: []*bundlev1.PatchSecretFingerprint{
+					{
+						Path:        "application/legacy/database",
+						Key:         "host",
+						Fingerprint: Fingerprint([]byte("fingerprint-key"), &bundlev1.KV{Value: []byte("queue")}),
+					},
+					{
+						Path:        "application/legacy/database",
+						Key:         "port",
+						Fingerprint: Fingerprint([]byte("fingerprint-key"), &bundlev1.KV{Value: []byte("5432")}),
+					},
+					{
+						Path:        "application/legacy/cache",
+						Key:         "host",
+						Fingerprint: Fingerprint([]byte("fingerprint-key"), &bundlev1.KV{Value: []byte("cache")}),
+					},
+				},
+			},
+			wantErr:        true,
+			wantViolations: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &bundlev1.Patch{
+				Spec: &bundlev1.PatchSpec{
+					Preconditions: tt.preconditions,
+				},
+			}
+
+			b := testPreconditionBundle()
+			err := CheckPreconditions(spec, b, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if b.Packages[0].Name != "application/legacy/queue" {
+				t.Errorf("CheckPreconditions() must not alter the input bundle")
+			}
+			if err == nil || tt.wantViolations == 0 {
+				return
+			}
+
+			var perr *PreconditionError
+			if !errors.As(err, &perr) {
+				t.Errorf("CheckPreconditions() error type = %T, want PreconditionError", err)
+				return
+			}
+			if len(perr.Violations) != tt.wantViolations {
+				t.Errorf("CheckPreconditions() violations = %v, want %d", perr.Violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	key := []byte("fingerprint-key")
+
+	tests := []struct {
+		name    string
+		key     []byte
+		refs    []string
+		want    []*bundlev1.PatchSecretFingerprint
+		wantErr bool
+	}{
+		{
+			name:    "blank key",
+			refs:    []string{"application/legacy/database#host"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid reference",
+			key:     key,
+			refs:    []string{"application/legacy/database"},
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			key:     key,
+			refs:    []string{"application/legacy/database#port"},
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			key:  key,
+			refs: []string{"application/legacy/database#host"},
+			want: []*bundlev1.PatchSecretFingerprint{
+				{
+					Path:        "application/legacy/database",
+					Key:         "host",
+					Fingerprint: "c053331d7ffb6722d26919e08b1851687e1dcf7bc891edca552d2845f27c33c0",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fingerprints(testPreconditionBundle(), tt.key, tt.refs...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fingerprints() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Fingerprints() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Path != tt.want[i].Path || got[i].Key != tt.want[i].Key || got[i].Fingerprint != tt.want[i].Fingerprint {
+					t.Errorf("Fingerprints()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -64,3 +64,36 @@ func YAML(r io.Reader) (*bundlev1.Patch, error) {
 	// No error
 	return &def, nil
 }
+
+// SelectorYAML a given reader in order to extract a PatchSelector
+// specification.
+func SelectorYAML(r io.Reader) (*bundlev1.PatchSelector, error) {
+	// Check arguments
+	if types.IsNil(r) {
+		return nil, fmt.Errorf("reader is nil")
+	}
+
+	// Drain the reader
+	jsonReader, err := convert.YAMLtoJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse input as PatchSelector: %w", err)
+	}
+
+	// Drain reader
+	jsonData, err := io.ReadAll(jsonReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to drain all json reader content: %w", err)
+	}
+
+	// Initialize empty definition object
+	def := bundlev1.PatchSelector{}
+	def.Reset()
+
+	// Deserialize JSON with JSONPB wrapper
+	if err := protojson.Unmarshal(jsonData, &def); err != nil {
+		return nil, fmt.Errorf("unable to decode selector as json: %w", err)
+	}
+
+	// No error
+	return &def, nil
+}
@@ -0,0 +1,314 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	celext "github.com/google/cel-go/ext"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/ruleset/engine/cel/ext"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+const (
+	conflictError     = "error"
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+)
+
+// relocatedPackage describes a package produced by a copy, move or merge
+// operation.
+type relocatedPackage struct {
+	action     ruleAction
+	source     *bundlev1.Package
+	pkg        *bundlev1.Package
+	onConflict string
+}
+
+// -----------------------------------------------------------------------------
+
+func packageTarget(p *bundlev1.PatchPackage) (ruleAction, *bundlev1.PatchPackageTarget, error) {
+	// Check parameters
+	if p == nil {
+		return packageUnchanged, nil, fmt.Errorf("cannot process nil patch")
+	}
+
+	var (
+		action ruleAction
+		target *bundlev1.PatchPackageTarget
+		count  int
+	)
+	if p.Copy != nil {
+		action, target = packageCopied, p.Copy
+		count++
+	}
+	if p.Move != nil {
+		action, target = packageMoved, p.Move
+		count++
+	}
+	if p.MergeInto != nil {
+		action, target = packageMerged, p.MergeInto
+		count++
+	}
+
+	switch {
+	case count == 0:
+		return packageUnchanged, nil, nil
+	case count > 1:
+		return packageUnchanged, nil, errors.New("copy, move and mergeInto operations are mutually exclusive")
+	}
+
+	// Validate conflict policy
+	switch strings.ToLower(target.OnConflict) {
+	case "", conflictError, conflictSkip, conflictOverwrite:
+	default:
+		return packageUnchanged, nil, fmt.Errorf("unsupported conflict policy '%s'", target.OnConflict)
+	}
+
+	// No error
+	return action, target, nil
+}
+
+func resolveTargetPath(p *bundlev1.Package, t *bundlev1.PatchPackageTarget, values map[string]interface{}) (string, error) {
+	// Check parameters
+	if p == nil {
+		return "", fmt.Errorf("cannot process nil package")
+	}
+	if t == nil {
+		return "", fmt.Errorf("cannot process nil target")
+	}
+
+	var (
+		out string
+		err error
+	)
+	switch {
+	case t.Template != "" && t.Cel != "":
+		return "", errors.New("template and cel target are mutually exclusive")
+	case t.Template != "":
+		out, err = engine.Render(t.Template, map[string]interface{}{
+			"Values": values,
+			"Path":   p.Name,
+		})
+		if err != nil {
+			return "", fmt.Errorf("unable to execute target path template of `%s`: %w", p.Name, err)
+		}
+	case t.Cel != "":
+		out, err = evaluateTargetExpression(t.Cel, p, values)
+		if err != nil {
+			return "", fmt.Errorf("unable to evaluate target path expression of `%s`: %w", p.Name, err)
+		}
+	default:
+		return "", errors.New("no template or cel defined for target path")
+	}
+
+	// Check result
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", fmt.Errorf("target path of `%s` is blank", p.Name)
+	}
+
+	// No error
+	return out, nil
+}
+
+func evaluateTargetExpression(exp string, p *bundlev1.Package, values map[string]interface{}) (string, error) {
+	// Prepare CEL Environment
+	env, err := cel.NewEnv(
+		cel.Types(&bundlev1.Bundle{}, &bundlev1.Package{}, &bundlev1.SecretChain{}, &bundlev1.KV{}),
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Packages(),
+		ext.Secrets(),
+		celext.Strings(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("unable to prepare CEL engine environment: %w", err)
+	}
+
+	// Compile expression
+	ast, issues := env.Compile(exp)
+	if issues != nil && issues.Err() != nil {
+		return "", fmt.Errorf("invalid CEL expression '%s': %w", exp, issues.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.StringType) {
+		return "", fmt.Errorf("CEL target expression expects return type of string, not %s", ast.OutputType())
+	}
+
+	// Compile the program
+	prg, err := env.Program(ast)
+	if err != nil {
+		return "", fmt.Errorf("error while creating CEL program: %w", err)
+	}
+
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	// Evaluate using the package context
+	out, _, err := prg.Eval(map[string]interface{}{
+		"p":      p,
+		"values": values,
+	})
+	if err != nil {
+		return "", fmt.Errorf("an error occurred during the expression evaluation: %w", err)
+	}
+
+	path, ok := out.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected expression result type %T", out.Value())
+	}
+
+	// No error
+	return path, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func relocatePackage(packages []*bundlev1.Package, rp *relocatedPackage) ([]*bundlev1.Package, error) {
+	// Check parameters
+	if rp == nil || rp.pkg == nil {
+		return nil, fmt.Errorf("cannot process nil package")
+	}
+
+	// Lookup target package
+	idx := -1
+	for i, p := range packages {
+		if p != nil && p.Name == rp.pkg.Name {
+			idx = i
+			break
+		}
+	}
+
+	// Target doesn't exist
+	if idx < 0 {
+		return append(packages, rp.pkg), nil
+	}
+
+	// Merge into existing package
+	if rp.action == packageMerged {
+		if err := mergePackage(packages[idx], rp.pkg, rp.onConflict); err != nil {
+			return nil, fmt.Errorf("unable to merge into package `%s`: %w", rp.pkg.Name, err)
+		}
+		return packages, nil
+	}
+
+	switch strings.ToLower(rp.onConflict) {
+	case conflictSkip:
+		// Keep target package, and the source package if it was moved
+		if rp.action == packageMoved && rp.source != nil {
+			packages = append(packages, rp.source)
+		}
+	case conflictOverwrite:
+		packages[idx] = rp.pkg
+	default:
+		return nil, fmt.Errorf("target package `%s` already exists", rp.pkg.Name)
+	}
+
+	// No error
+	return packages, nil
+}
+
+// mergePackage merges the source package into the destination package.
+// Labels and annotations are merged with the destination taking precedence
+// unless the policy is overwrite. Secret key conflicts are resolved using the
+// given policy.
+func mergePackage(dst, src *bundlev1.Package, policy string) error {
+	// Check parameters
+	if dst == nil {
+		return fmt.Errorf("cannot process nil target package")
+	}
+	if src == nil {
+		return fmt.Errorf("cannot process nil source package")
+	}
+	if isLocked(dst) || isLocked(src) {
+		return fmt.Errorf("unable to merge locked packages")
+	}
+
+	overwrite := strings.EqualFold(policy, conflictOverwrite)
+
+	// Merge metadata
+	dst.Labels = mergeMap(dst.Labels, src.Labels, overwrite)
+	dst.Annotations = mergeMap(dst.Annotations, src.Annotations, overwrite)
+
+	// Nothing to merge
+	if src.Secrets == nil {
+		return nil
+	}
+	if dst.Secrets == nil {
+		dst.Secrets = &bundlev1.SecretChain{}
+	}
+	dst.Secrets.Labels = mergeMap(dst.Secrets.Labels, src.Secrets.Labels, overwrite)
+	dst.Secrets.Annotations = mergeMap(dst.Secrets.Annotations, src.Secrets.Annotations, overwrite)
+
+	// Merge secret data
+	for _, kv := range src.Secrets.Data {
+		// Ignore nil
+		if kv == nil {
+			continue
+		}
+
+		found := false
+		for i, existing := range dst.Secrets.Data {
+			if existing == nil || existing.Key != kv.Key {
+				continue
+			}
+
+			found = true
+			switch strings.ToLower(policy) {
+			case conflictSkip:
+				// Keep target value
+			case conflictOverwrite:
+				dst.Secrets.Data[i] = kv
+			default:
+				return fmt.Errorf("secret key `%s` already exists", kv.Key)
+			}
+		}
+		if !found {
+			dst.Secrets.Data = append(dst.Secrets.Data, kv)
+		}
+	}
+
+	// No error
+	return nil
+}
+
+func mergeMap(dst, src map[string]string, overwrite bool) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]string{}
+	}
+	for k, v := range src {
+		if _, ok := dst[k]; ok && !overwrite {
+			continue
+		}
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func isLocked(p *bundlev1.Package) bool {
+	return p != nil && p.Secrets != nil && p.Secrets.Locked != nil
+}
@@ -21,13 +21,21 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
 )
 
 // FromBundle crawls secret structure to generate a linter ruleset.
-func FromBundle(b *bundlev1.Bundle) (*bundlev1.RuleSet, error) {
+//
+// By default, one rule is generated for each package. Value inference adds
+// type, length and entropy constraints from the secret values, and package
+// collapse merges packages with the same structure as one glob rule.
+func FromBundle(b *bundlev1.Bundle, opts ...OptionFunc) (*bundlev1.RuleSet, error) {
 	// Check arguments
 	if b == nil {
 		return nil, errors.New("unable to process nil bundle")
@@ -36,6 +44,12 @@ func FromBundle(b *bundlev1.Bundle) (*bundlev1.RuleSet, error) {
 		return nil, errors.New("unable to generate rule from an empty bundle")
 	}
 
+	// Default options
+	dopts := &options{}
+	for _, o := range opts {
+		o(dopts)
+	}
+
 	// Retrieve MTR
 	root, _, err := bundle.Tree(b)
 	if err != nil {
@@ -58,41 +72,204 @@ func FromBundle(b *bundlev1.Bundle) (*bundlev1.RuleSet, error) {
 		},
 	}
 
-	// Iterate over bundle package
-	ruleIdx := 1
+	// Build package shapes
+	shapes := []*packageShape{}
 	for _, p := range b.Packages {
 		if p == nil || p.Secrets == nil || len(p.Secrets.Data) == 0 {
 			// Skip invalid package
 			continue
 		}
 
-		// Prepare a rule
-		r := &bundlev1.Rule{
-			Name:        fmt.Sprintf("LINT-%s-%d", b64Root[:6], ruleIdx),
-			Path:        p.Name,
-			Constraints: []string{},
-		}
+		shapes = append(shapes, newPackageShape(p, dopts.inferValues))
+	}
 
-		// Process the labels for each secret
-		for label := range p.Labels {
-			r.Constraints = append(r.Constraints, fmt.Sprintf(`p.match_label(%q)`, label))
-		}
+	// Group similar packages
+	if dopts.collapsePackages {
+		shapes = collapsePackageShapes(b, shapes)
+	}
 
-		// Process the annotations for each secret
-		for annotation := range p.Annotations {
-			r.Constraints = append(r.Constraints, fmt.Sprintf(`p.match_annotation(%q)`, annotation))
+	// Generate rules
+	for idx, ps := range shapes {
+		r := &bundlev1.Rule{
+			Name:        fmt.Sprintf("LINT-%s-%d", b64Root[:6], idx+1),
+			Path:        ps.path,
+			Constraints: ps.constraints(),
 		}
-
-		// Process each secret
-		for _, s := range p.Secrets.Data {
-			r.Constraints = append(r.Constraints, fmt.Sprintf(`p.has_secret(%q)`, s.Key))
+		if len(ps.packages) > 1 {
+			r.Description = fmt.Sprintf("Inferred from %d packages", len(ps.packages))
 		}
 
 		// Add the rules
 		rs.Spec.Rules = append(rs.Spec.Rules, r)
-		ruleIdx++
 	}
 
 	// No error
 	return rs, nil
 }
+
+// -----------------------------------------------------------------------------
+
+// packageShape describes the package structure used to generate a rule.
+type packageShape struct {
+	path        string
+	packages    []string
+	labels      []string
+	annotations []string
+	secrets     []string
+	values      []*valueShape
+}
+
+func newPackageShape(p *bundlev1.Package, inferValues bool) *packageShape {
+	ps := &packageShape{
+		path:        p.Name,
+		packages:    []string{p.Name},
+		labels:      sortedMapKeys(p.Labels),
+		annotations: sortedMapKeys(p.Annotations),
+		secrets:     []string{},
+		values:      []*valueShape{},
+	}
+
+	for _, s := range p.Secrets.Data {
+		ps.secrets = append(ps.secrets, s.Key)
+	}
+	sort.Strings(ps.secrets)
+
+	if inferValues {
+		ps.values = inferValueShapes(p)
+	}
+
+	return ps
+}
+
+// signature returns the structural package identifier. Packages sharing the
+// same signature can be collapsed.
+func (ps *packageShape) signature() string {
+	parts := []string{
+		fmt.Sprintf("segments=%d", strings.Count(ps.path, "/")),
+		fmt.Sprintf("labels=%s", strings.Join(ps.labels, ",")),
+		fmt.Sprintf("annotations=%s", strings.Join(ps.annotations, ",")),
+		fmt.Sprintf("secrets=%s", strings.Join(ps.secrets, ",")),
+	}
+	for _, v := range ps.values {
+		parts = append(parts, v.signature())
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (ps *packageShape) constraints() []string {
+	res := []string{}
+
+	// Process the labels for each secret
+	for _, label := range ps.labels {
+		res = append(res, fmt.Sprintf(`p.match_label(%q)`, label))
+	}
+
+	// Process the annotations for each secret
+	for _, annotation := range ps.annotations {
+		res = append(res, fmt.Sprintf(`p.match_annotation(%q)`, annotation))
+	}
+
+	// Process each secret
+	for _, key := range ps.secrets {
+		res = append(res, fmt.Sprintf(`p.has_secret(%q)`, key))
+	}
+
+	// Inferred value constraints
+	for _, v := range ps.values {
+		res = append(res, v.constraints()...)
+	}
+
+	return res
+}
+
+// collapsePackageShapes merges packages with the same signature. The merged
+// path replaces differing segments by a wildcard, the group is kept as
+// individual packages if the glob matches other packages of the bundle.
+func collapsePackageShapes(b *bundlev1.Bundle, shapes []*packageShape) []*packageShape {
+	// Group by signature, keep first occurrence order
+	groups := map[string][]*packageShape{}
+	order := []string{}
+	for _, ps := range shapes {
+		sig := ps.signature()
+		if _, ok := groups[sig]; !ok {
+			order = append(order, sig)
+		}
+		groups[sig] = append(groups[sig], ps)
+	}
+
+	res := []*packageShape{}
+	for _, sig := range order {
+		group := groups[sig]
+		if len(group) == 1 {
+			res = append(res, group[0])
+			continue
+		}
+
+		merged, ok := mergePackageShapes(b, group)
+		if !ok {
+			res = append(res, group...)
+			continue
+		}
+
+		res = append(res, merged)
+	}
+
+	return res
+}
+
+func mergePackageShapes(b *bundlev1.Bundle, group []*packageShape) (*packageShape, bool) {
+	// Build the path glob
+	segments := strings.Split(group[0].path, "/")
+	for i := range segments {
+		segments[i] = glob.QuoteMeta(segments[i])
+	}
+	for _, ps := range group[1:] {
+		for i, segment := range strings.Split(ps.path, "/") {
+			if glob.QuoteMeta(segment) != segments[i] {
+				segments[i] = "*"
+			}
+		}
+	}
+	path := strings.Join(segments, "/")
+
+	// Ensure the glob doesn't match packages outside the group
+	matcher, err := glob.Compile(path)
+	if err != nil {
+		return nil, false
+	}
+	members := map[string]struct{}{}
+	for _, ps := range group {
+		members[ps.path] = struct{}{}
+	}
+	for _, p := range b.Packages {
+		if p == nil {
+			continue
+		}
+		if _, ok := members[p.Name]; !ok && matcher.Match(p.Name) {
+			return nil, false
+		}
+	}
+
+	// Merge value bounds
+	merged := &packageShape{
+		path:        path,
+		packages:    []string{},
+		labels:      group[0].labels,
+		annotations: group[0].annotations,
+		secrets:     group[0].secrets,
+		values:      make([]*valueShape, len(group[0].values)),
+	}
+	for i, v := range group[0].values {
+		value := *v
+		merged.values[i] = &value
+	}
+	for _, ps := range group {
+		merged.packages = append(merged.packages, ps.path)
+		for i, v := range ps.values {
+			merged.values[i].merge(v)
+		}
+	}
+
+	return merged, true
+}
@@ -18,11 +18,17 @@
 package ruleset
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"testing"
 
-	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
-
 	"github.com/stretchr/testify/assert"
+	"go.step.sm/crypto/pemutil"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/secret"
 )
 
 func TestFromBundle(t *testing.T) {
@@ -222,3 +228,120 @@ func TestFromBundle(t *testing.T) {
 		})
 	}
 }
+
+func mustSecrets(t *testing.T, values map[string]string) *bundlev1.SecretChain {
+	t.Helper()
+
+	chain := &bundlev1.SecretChain{}
+	for _, k := range sortedMapKeys(values) {
+		packed, err := secret.Pack(values[k])
+		assert.NoError(t, err)
+		chain.Data = append(chain.Data, &bundlev1.KV{Key: k, Type: "string", Value: packed})
+	}
+
+	return chain
+}
+
+func TestFromBundle_Inference(t *testing.T) {
+	_, pk, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	block, err := pemutil.Serialize(pk)
+	assert.NoError(t, err)
+
+	b := &bundlev1.Bundle{
+		Packages: []*bundlev1.Package{
+			{
+				Name:   "app/production/customer1/ece/v1.0.0/adminconsole/database",
+				Labels: map[string]string{"database": "postgresql"},
+				Secrets: mustSecrets(t, map[string]string{
+					"DB_URL":      "https://db1.internal/harp",
+					"DB_PASSWORD": "Zq8!vW3#kL9@xP2$",
+					"DB_SETTINGS": `{"sslmode":"require"}`,
+				}),
+			},
+			{
+				Name:   "app/production/customer2/ece/v1.0.0/adminconsole/database",
+				Labels: map[string]string{"database": "postgresql"},
+				Secrets: mustSecrets(t, map[string]string{
+					"DB_URL":      "https://db2.internal/harp",
+					"DB_PASSWORD": "Rt5%mN7^bV1&cX4*yU6(",
+					"DB_SETTINGS": `{"sslmode":"verify-full"}`,
+				}),
+			},
+			{
+				Name: "app/production/customer1/ece/v1.0.0/adminconsole/http/session",
+				Secrets: mustSecrets(t, map[string]string{
+					"id":          "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+					"key":         "dGhpcyBpcyBhIDMyIGJ5dGVzIGtleSBmb3IgdGVzdHM=",
+					"signing.pem": string(pem.EncodeToMemory(block)),
+					"contact":     "security@elastic.co",
+				}),
+			},
+			{
+				Name: "app/staging/customer1/ece/v1.0.0/adminconsole/database",
+				Secrets: mustSecrets(t, map[string]string{
+					"DB_URL": "https://db3.internal/harp",
+				}),
+			},
+		},
+	}
+
+	t.Run("inference", func(t *testing.T) {
+		rs, err := FromBundle(b, WithValueInference(true))
+		assert.NoError(t, err)
+		assert.Len(t, rs.Spec.Rules, 4)
+		assert.Equal(t, []string{
+			`p.has_secret("contact")`,
+			`p.has_secret("id")`,
+			`p.has_secret("key")`,
+			`p.has_secret("signing.pem")`,
+			`p.secret("contact").is_email()`,
+			`p.secret("id").is_uuid()`,
+			`p.secret("key").is_base64()`,
+			`p.secret("key").length() >= 44`,
+			`p.secret("key").password_entropy() >= 289`,
+			`p.secret("signing.pem").is_private_key("ed25519", 256)`,
+		}, rs.Spec.Rules[1].Constraints)
+
+		// The generated ruleset must validate the source bundle
+		assert.NoError(t, Evaluate(context.Background(), b, rs))
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		rs, err := FromBundle(b, WithValueInference(true), WithPackageCollapse(true))
+		assert.NoError(t, err)
+		assert.Len(t, rs.Spec.Rules, 3)
+		assert.Equal(t, "app/production/*/ece/v1.0.0/adminconsole/database", rs.Spec.Rules[0].Path)
+		assert.Equal(t, "Inferred from 2 packages", rs.Spec.Rules[0].Description)
+		assert.Equal(t, []string{
+			`p.match_label("database")`,
+			`p.has_secret("DB_PASSWORD")`,
+			`p.has_secret("DB_SETTINGS")`,
+			`p.has_secret("DB_URL")`,
+			`p.secret("DB_PASSWORD").length() >= 16`,
+			`p.secret("DB_PASSWORD").password_entropy() >= 105`,
+			`p.secret("DB_SETTINGS").is_json()`,
+			`p.secret("DB_URL").is_url()`,
+		}, rs.Spec.Rules[0].Constraints)
+		assert.Equal(t, "app/staging/customer1/ece/v1.0.0/adminconsole/database", rs.Spec.Rules[2].Path)
+
+		// The generated ruleset must validate the source bundle
+		assert.NoError(t, Evaluate(context.Background(), b, rs))
+	})
+
+	t.Run("collapse conflict", func(t *testing.T) {
+		// Same structure, but the glob would match the staging package
+		conflict := &bundlev1.Bundle{
+			Packages: []*bundlev1.Package{
+				{Name: "app/production/db", Secrets: mustSecrets(t, map[string]string{"host": "db1"})},
+				{Name: "app/qa/db", Secrets: mustSecrets(t, map[string]string{"host": "db2"})},
+				{Name: "app/staging/db", Secrets: mustSecrets(t, map[string]string{"user": "db3"})},
+			},
+		}
+
+		rs, err := FromBundle(conflict, WithPackageCollapse(true))
+		assert.NoError(t, err)
+		assert.Len(t, rs.Spec.Rules, 3)
+		assert.NoError(t, Evaluate(context.Background(), conflict, rs))
+	})
+}
@@ -20,6 +20,8 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
@@ -27,7 +29,33 @@ import (
 // ErrRuleNotValid is raised when a rule from a ruleset is false.
 var ErrRuleNotValid = errors.New("rule is not valid")
 
+// ConstraintError is raised when a package doesn't validate a rule constraint.
+// It wraps ErrRuleNotValid. Messages holds the violation messages reported by
+// the policy, if any.
+type ConstraintError struct {
+	Constraint string
+	Messages   []string
+}
+
+// Error returns the error message.
+func (e *ConstraintError) Error() string {
+	if len(e.Messages) > 0 {
+		return fmt.Sprintf("%s: constraint '%s' is not satisfied: %s", ErrRuleNotValid.Error(), e.Constraint, strings.Join(e.Messages, ", "))
+	}
+	return fmt.Sprintf("%s: constraint '%s' is not satisfied", ErrRuleNotValid.Error(), e.Constraint)
+}
+
+// Unwrap returns the wrapped error.
+func (e *ConstraintError) Unwrap() error {
+	return ErrRuleNotValid
+}
+
 // PackageLinter describes linter engine contract.
 type PackageLinter interface {
 	EvaluatePackage(ctx context.Context, p *bundlev1.Package) error
 }
+
+// BundleLinter describes bundle scoped linter engine contract.
+type BundleLinter interface {
+	EvaluateBundle(ctx context.Context, b *bundlev1.Bundle) error
+}
@@ -47,6 +47,52 @@ func New(expressions []string) (engine.PackageLinter, error) {
 		return nil, fmt.Errorf("unable to prepare CEL engine environment: %w", err)
 	}
 
+	// Compile expressions
+	ruleset, err := compile(env, expressions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return rule engine
+	return &ruleEngine{
+		cel:         env,
+		ruleset:     ruleset,
+		constraints: expressions,
+	}, nil
+}
+
+// NewBundle returns a Google CEL based bundle linter engine. Expressions are
+// evaluated with the complete bundle exposed as `bundle` variable.
+func NewBundle(expressions []string) (engine.BundleLinter, error) {
+	// Prepare CEL Environment
+	env, err := cel.NewEnv(
+		cel.Types(&bundlev1.Bundle{}, &bundlev1.Package{}, &bundlev1.SecretChain{}, &bundlev1.KV{}),
+		ext.Bundles(),
+		ext.PackageFunctions(),
+		ext.Secrets(),
+		celext.Strings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare CEL engine environment: %w", err)
+	}
+
+	// Compile expressions
+	ruleset, err := compile(env, expressions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return rule engine
+	return &ruleEngine{
+		cel:         env,
+		ruleset:     ruleset,
+		constraints: expressions,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func compile(env *cel.Env, expressions []string) ([]cel.Program, error) {
 	// Assemble the complete ruleset
 	ruleset := make([]cel.Program, 0, len(expressions))
 	for _, exp := range expressions {
@@ -79,18 +125,16 @@ func New(expressions []string) (engine.PackageLinter, error) {
 		ruleset = append(ruleset, p)
 	}
 
-	// Return rule engine
-	return &ruleEngine{
-		cel:     env,
-		ruleset: ruleset,
-	}, nil
+	// No error
+	return ruleset, nil
 }
 
 // -----------------------------------------------------------------------------
 
 type ruleEngine struct {
-	cel     *cel.Env
-	ruleset []cel.Program
+	cel         *cel.Env
+	ruleset     []cel.Program
+	constraints []string
 }
 
 //nolint:revive // refactor use of ctx
@@ -100,19 +144,38 @@ func (re *ruleEngine) EvaluatePackage(ctx context.Context, p *bundlev1.Package)
 		return errors.New("unable to evaluate nil package")
 	}
 
+	// Evaluate using the package context
+	return re.evaluate(map[string]interface{}{
+		"p": p,
+	})
+}
+
+//nolint:revive // refactor use of ctx
+func (re *ruleEngine) EvaluateBundle(ctx context.Context, b *bundlev1.Bundle) error {
+	// Check arguments
+	if b == nil {
+		return errors.New("unable to evaluate nil bundle")
+	}
+
+	// Evaluate using the bundle context
+	return re.evaluate(map[string]interface{}{
+		"bundle": b,
+	})
+}
+
+func (re *ruleEngine) evaluate(input map[string]interface{}) error {
 	// Apply evaluation (implicit AND between rules)
-	for _, exp := range re.ruleset {
-		// Evaluate using the bundle context
-		out, _, err := exp.Eval(map[string]interface{}{
-			"p": p,
-		})
+	for i, exp := range re.ruleset {
+		out, _, err := exp.Eval(input)
 		if err != nil {
 			return fmt.Errorf("an error occurred during the rule evaluation: %w", err)
 		}
 
 		// Boolean rule returned false
 		if out.Value() == false {
-			return engine.ErrRuleNotValid
+			return &engine.ConstraintError{
+				Constraint: re.constraints[i],
+			}
 		}
 	}
 
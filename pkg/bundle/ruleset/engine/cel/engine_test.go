@@ -19,11 +19,21 @@ package cel
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle/ruleset/engine"
 	"github.com/elastic/harp/pkg/bundle/secret"
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
 )
 
 func TestNew(t *testing.T) {
@@ -327,6 +337,94 @@ func Test_ruleEngine_EvaluatePackage(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid: is_json",
+			fields: fields{
+				expressions: []string{
+					`p.secret("test").is_json()`,
+				},
+			},
+			args: args{
+				p: &bundlev1.Package{
+					Name: "app/qa/security/harp/v1.0.0/server/database/credentials",
+					Secrets: &bundlev1.SecretChain{
+						Data: []*bundlev1.KV{
+							{
+								Key:   "test",
+								Value: mustPack("{not json"),
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid: is_json",
+			fields: fields{
+				expressions: []string{
+					`p.secret("test").is_json()`,
+				},
+			},
+			args: args{
+				p: &bundlev1.Package{
+					Name: "app/qa/security/harp/v1.0.0/server/database/credentials",
+					Secrets: &bundlev1.SecretChain{
+						Data: []*bundlev1.KV{
+							{
+								Key:   "test",
+								Value: mustPack(`{"host":"db.internal"}`),
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid: length",
+			fields: fields{
+				expressions: []string{
+					`p.secret("test").length() >= 8`,
+				},
+			},
+			args: args{
+				p: &bundlev1.Package{
+					Name: "app/qa/security/harp/v1.0.0/server/database/credentials",
+					Secrets: &bundlev1.SecretChain{
+						Data: []*bundlev1.KV{
+							{
+								Key:   "test",
+								Value: mustPack("short"),
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid: length",
+			fields: fields{
+				expressions: []string{
+					`p.secret("test").length() >= 8 && p.secret("test").length() <= 10`,
+				},
+			},
+			args: args{
+				p: &bundlev1.Package{
+					Name: "app/qa/security/harp/v1.0.0/server/database/credentials",
+					Secrets: &bundlev1.SecretChain{
+						Data: []*bundlev1.KV{
+							{
+								Key:   "test",
+								Value: mustPack("lëngthy-pw"),
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -337,3 +435,373 @@ func Test_ruleEngine_EvaluatePackage(t *testing.T) {
 		})
 	}
 }
+
+func mustCertificate(notAfter time.Time, dnsNames ...string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "harp-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     dnsNames,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func mustPrivateKey(key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func mustJWT(claims map[string]interface{}) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	token, err := crypto.ToJWS(claims, key)
+	if err != nil {
+		panic(err)
+	}
+
+	return token
+}
+
+func Test_ruleEngine_EvaluatePackage_Crypto(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate EC key: %v", err)
+	}
+	jwk, err := crypto.ToJWK(ecKey)
+	if err != nil {
+		t.Fatalf("unable to encode JWK: %v", err)
+	}
+
+	cert := mustCertificate(time.Now().Add(90*24*time.Hour), "harp.elastic.co")
+	expiringCert := mustCertificate(time.Now().Add(10*24*time.Hour), "harp.elastic.co")
+
+	tests := []struct {
+		name       string
+		expression string
+		value      interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "valid: is_pem_certificate",
+			expression: `p.secret("test").is_pem_certificate()`,
+			value:      cert,
+		},
+		{
+			name:       "invalid: is_pem_certificate",
+			expression: `p.secret("test").is_pem_certificate()`,
+			value:      "not-a-certificate",
+			wantErr:    true,
+		},
+		{
+			name:       "valid: cert_expires_within",
+			expression: `!p.secret("test").cert_expires_within(duration("720h"))`,
+			value:      cert,
+		},
+		{
+			name:       "invalid: cert_expires_within",
+			expression: `!p.secret("test").cert_expires_within(duration("720h"))`,
+			value:      expiringCert,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid: cert_expires_within - not a certificate",
+			expression: `!p.secret("test").cert_expires_within(duration("720h"))`,
+			value:      "not-a-certificate",
+			wantErr:    true,
+		},
+		{
+			name:       "valid: cert_has_san",
+			expression: `p.secret("test").cert_has_san("harp.elastic.co")`,
+			value:      cert,
+		},
+		{
+			name:       "invalid: cert_has_san",
+			expression: `p.secret("test").cert_has_san("www.elastic.co")`,
+			value:      cert,
+			wantErr:    true,
+		},
+		{
+			name:       "valid: is_private_key rsa",
+			expression: `p.secret("test").is_private_key("rsa", 2048)`,
+			value:      mustPrivateKey(rsaKey),
+		},
+		{
+			name:       "invalid: is_private_key rsa too small",
+			expression: `p.secret("test").is_private_key("rsa", 3072)`,
+			value:      mustPrivateKey(rsaKey),
+			wantErr:    true,
+		},
+		{
+			name:       "invalid: is_private_key type mismatch",
+			expression: `p.secret("test").is_private_key("rsa", 256)`,
+			value:      mustPrivateKey(ecKey),
+			wantErr:    true,
+		},
+		{
+			name:       "valid: is_private_key from JWK",
+			expression: `p.secret("test").is_private_key("ec", 384)`,
+			value:      jwk,
+		},
+		{
+			name:       "valid: is_private_key any",
+			expression: `p.secret("test").is_private_key("any", 0)`,
+			value:      mustPrivateKey(ecKey),
+		},
+		{
+			name:       "valid: is_jwk",
+			expression: `p.secret("test").is_jwk()`,
+			value:      jwk,
+		},
+		{
+			name:       "invalid: is_jwk",
+			expression: `p.secret("test").is_jwk()`,
+			value:      "{}",
+			wantErr:    true,
+		},
+		{
+			name:       "valid: is_jwt",
+			expression: `p.secret("test").is_jwt()`,
+			value:      mustJWT(map[string]interface{}{"sub": "harp"}),
+		},
+		{
+			name:       "invalid: is_jwt",
+			expression: `p.secret("test").is_jwt()`,
+			value:      "not-a-token",
+			wantErr:    true,
+		},
+		{
+			name:       "valid: jwt_expired without expiration",
+			expression: `!p.secret("test").jwt_expired()`,
+			value:      mustJWT(map[string]interface{}{"sub": "harp"}),
+		},
+		{
+			name:       "valid: jwt_expired",
+			expression: `!p.secret("test").jwt_expired()`,
+			value:      mustJWT(map[string]interface{}{"exp": time.Now().Add(time.Hour).Unix()}),
+		},
+		{
+			name:       "invalid: jwt_expired",
+			expression: `!p.secret("test").jwt_expired()`,
+			value:      mustJWT(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}),
+			wantErr:    true,
+		},
+		{
+			name:       "valid: password_entropy",
+			expression: `p.secret("test").password_entropy() >= 60.0`,
+			value:      "Ch4ng3-Th1s-P4ssw0rd!",
+		},
+		{
+			name:       "invalid: password_entropy",
+			expression: `p.secret("test").password_entropy() >= 60`,
+			value:      "password",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := New([]string{tt.expression})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			p := &bundlev1.Package{
+				Name: "app/production/security/harp/v1.0.0/server/tls",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{
+							Key:   "test",
+							Value: mustPack(tt.value),
+						},
+					},
+				},
+			}
+			if err := re.EvaluatePackage(context.Background(), p); (err != nil) != tt.wantErr {
+				t.Errorf("ruleEngine.EvaluatePackage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func testBundle() *bundlev1.Bundle {
+	return &bundlev1.Bundle{
+		Labels: map[string]string{
+			"environment": "production",
+		},
+		Packages: []*bundlev1.Package{
+			{
+				Name: "app/production/billing/database",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "host", Value: mustPack("db1.internal")},
+					},
+				},
+			},
+			{
+				Name: "app/production/checkout/database",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "host", Value: mustPack("db2.internal")},
+					},
+				},
+			},
+			{
+				Name: "infra/production/billing/database",
+			},
+		},
+	}
+}
+
+func TestNewBundle(t *testing.T) {
+	tests := []struct {
+		name        string
+		expressions []string
+		wantErr     bool
+	}{
+		{
+			name: "funcs",
+			expressions: []string{
+				`bundle.packages_matching("app/*").all(p, p.match_path("app/*"))`,
+				`bundle.count("app/*") == 2`,
+				`unique(bundle.secrets("app/*", "host"))`,
+				`"environment" in bundle.labels`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "package variable is not declared",
+			expressions: []string{
+				`p.is_cso_compliant()`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "not a boolean result",
+			expressions: []string{
+				`bundle.count("*")`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBundle(tt.expressions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewBundle() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_ruleEngine_EvaluateBundle(t *testing.T) {
+	tests := []struct {
+		name        string
+		expressions []string
+		b           *bundlev1.Bundle
+		wantErr     bool
+	}{
+		{
+			name:        "nil",
+			expressions: []string{`true`},
+			wantErr:     true,
+		},
+		{
+			name: "valid: count",
+			expressions: []string{
+				`bundle.count("app/*") == 2`,
+				`bundle.count("*/database") == 3`,
+			},
+			b:       testBundle(),
+			wantErr: false,
+		},
+		{
+			name: "invalid: count",
+			expressions: []string{
+				`bundle.count("app/*") == 3`,
+			},
+			b:       testBundle(),
+			wantErr: true,
+		},
+		{
+			name: "valid: matching infra package",
+			expressions: []string{
+				`bundle.packages_matching("app/production/billing/*").all(p, bundle.count(p.name.replace("app/", "infra/")) > 0)`,
+			},
+			b:       testBundle(),
+			wantErr: false,
+		},
+		{
+			name: "invalid: matching infra package",
+			expressions: []string{
+				`bundle.packages_matching("app/*").all(p, bundle.count(p.name.replace("app/", "infra/")) > 0)`,
+			},
+			b:       testBundle(),
+			wantErr: true,
+		},
+		{
+			name: "valid: unique hosts",
+			expressions: []string{
+				`unique(bundle.secrets("app/*", "host"))`,
+			},
+			b:       testBundle(),
+			wantErr: false,
+		},
+		{
+			name: "invalid: unique hosts",
+			expressions: []string{
+				`unique(bundle.secrets("app/*", "host") + ["db1.internal"])`,
+			},
+			b:       testBundle(),
+			wantErr: true,
+		},
+		{
+			name: "valid: bundle label",
+			expressions: []string{
+				`bundle.labels["environment"] == "production"`,
+			},
+			b:       testBundle(),
+			wantErr: false,
+		},
+		{
+			name: "invalid: bundle label",
+			expressions: []string{
+				`"owner" in bundle.labels`,
+			},
+			b:       testBundle(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := NewBundle(tt.expressions)
+			if err != nil {
+				t.Fatalf("NewBundle() error = %v", err)
+			}
+			if err := re.EvaluateBundle(context.Background(), tt.b); (err != nil) != tt.wantErr {
+				t.Errorf("ruleEngine.EvaluateBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ package ext
 import "github.com/google/cel-go/checker/decls"
 
 var (
+	harpBundleObjectType  = decls.NewObjectType("harp.bundle.v1.Bundle")
 	harpPackageObjectType = decls.NewObjectType("harp.bundle.v1.Package")
 	harpKVObjectType      = decls.NewObjectType("harp.bundle.v1.KV")
 )
@@ -0,0 +1,226 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ext
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gobwas/glob"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/interpreter/functions"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+// Bundles exported bundle operations.
+func Bundles() cel.EnvOption {
+	return cel.Lib(bundleLib{})
+}
+
+type bundleLib struct{}
+
+func (bundleLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		//nolint:staticcheck // TODO: deprecated usage. Requires an update.
+		cel.Declarations(
+			decls.NewVar("bundle", harpBundleObjectType),
+			decls.NewFunction("packages_matching",
+				decls.NewInstanceOverload("bundle_packages_matching_string",
+					[]*exprpb.Type{harpBundleObjectType, decls.String},
+					decls.NewListType(harpPackageObjectType),
+				),
+			),
+			decls.NewFunction("count",
+				decls.NewInstanceOverload("bundle_count_string",
+					[]*exprpb.Type{harpBundleObjectType, decls.String},
+					decls.Int,
+				),
+			),
+			decls.NewFunction("secrets",
+				decls.NewInstanceOverload("bundle_secrets_string_string",
+					[]*exprpb.Type{harpBundleObjectType, decls.String, decls.String},
+					decls.NewListType(decls.String),
+				),
+			),
+			decls.NewFunction("unique",
+				decls.NewOverload("unique_list",
+					[]*exprpb.Type{decls.NewListType(decls.Dyn)},
+					decls.Bool,
+				),
+			),
+		),
+	}
+}
+
+func (bundleLib) ProgramOptions() []cel.ProgramOption {
+	// Register types
+	reg, err := types.NewRegistry(
+		&bundlev1.Package{},
+	)
+	if err != nil {
+		panic(fmt.Errorf("unable to register types: %w", err))
+	}
+
+	return []cel.ProgramOption{
+		//nolint:staticcheck // TODO: refactor for deprecations
+		cel.Functions(
+			&functions.Overload{
+				Operator: "bundle_packages_matching_string",
+				Binary:   celBundlePackagesMatching(reg),
+			},
+			&functions.Overload{
+				Operator: "bundle_count_string",
+				Binary:   celBundleCount,
+			},
+			&functions.Overload{
+				Operator: "bundle_secrets_string_string",
+				Function: celBundleSecrets,
+			},
+			&functions.Overload{
+				Operator: "unique_list",
+				Unary:    celUnique,
+			},
+		),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func packagesMatching(lhs, rhs ref.Val) ([]*bundlev1.Package, bool) {
+	x, _ := lhs.ConvertToNative(reflect.TypeOf(&bundlev1.Bundle{}))
+	b, ok := x.(*bundlev1.Bundle)
+	if !ok {
+		return nil, false
+	}
+
+	patternTyped, ok := rhs.(types.String)
+	if !ok {
+		return nil, false
+	}
+
+	pattern, ok := patternTyped.Value().(string)
+	if !ok {
+		return nil, false
+	}
+
+	m, err := glob.Compile(pattern)
+	if err != nil {
+		return nil, false
+	}
+
+	res := []*bundlev1.Package{}
+	for _, p := range b.Packages {
+		if p != nil && m.Match(p.Name) {
+			res = append(res, p)
+		}
+	}
+
+	return res, true
+}
+
+func celBundlePackagesMatching(reg types.Adapter) func(lhs, rhs ref.Val) ref.Val {
+	return func(lhs, rhs ref.Val) ref.Val {
+		pkgs, ok := packagesMatching(lhs, rhs)
+		if !ok {
+			return types.NewErr("unable to match bundle packages")
+		}
+
+		return types.NewDynamicList(reg, pkgs)
+	}
+}
+
+func celBundleCount(lhs, rhs ref.Val) ref.Val {
+	pkgs, ok := packagesMatching(lhs, rhs)
+	if !ok {
+		return types.NewErr("unable to match bundle packages")
+	}
+
+	return types.Int(len(pkgs))
+}
+
+func celBundleSecrets(values ...ref.Val) ref.Val {
+	if len(values) != 3 {
+		return types.NewErr("invalid argument count")
+	}
+
+	pkgs, ok := packagesMatching(values[0], values[1])
+	if !ok {
+		return types.NewErr("unable to match bundle packages")
+	}
+
+	keyTyped, ok := values[2].(types.String)
+	if !ok {
+		return types.NewErr("secret key must be a string")
+	}
+
+	key, ok := keyTyped.Value().(string)
+	if !ok {
+		return types.NewErr("secret key must be a string")
+	}
+
+	res := []string{}
+	for _, p := range pkgs {
+		if p.Secrets == nil {
+			continue
+		}
+
+		for _, kv := range p.Secrets.Data {
+			if kv == nil || kv.Key != key {
+				continue
+			}
+
+			var out interface{}
+			if err := secret.Unpack(kv.Value, &out); err != nil {
+				return types.NewErr("unable to unpack secret value for `%s#%s`", p.Name, key)
+			}
+
+			res = append(res, fmt.Sprintf("%v", out))
+		}
+	}
+
+	return types.NewStringList(types.DefaultTypeAdapter, res)
+}
+
+func celUnique(val ref.Val) ref.Val {
+	l, ok := val.(traits.Lister)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	size, ok := l.Size().(types.Int)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	for i := types.Int(0); i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			if l.Get(i).Equal(l.Get(j)) == types.True {
+				return types.Bool(false)
+			}
+		}
+	}
+
+	return types.Bool(true)
+}
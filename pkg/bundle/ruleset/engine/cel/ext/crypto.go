@@ -0,0 +1,360 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ext
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"go.step.sm/crypto/pemutil"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/secret"
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+)
+
+// secretString returns the unpacked string value of the given KV.
+func secretString(val ref.Val) (string, bool) {
+	x, _ := val.ConvertToNative(reflect.TypeOf(&bundlev1.KV{}))
+	kv, ok := x.(*bundlev1.KV)
+	if !ok || kv == nil {
+		return "", false
+	}
+
+	var out string
+	if err := secret.Unpack(kv.Value, &out); err != nil {
+		return "", false
+	}
+
+	return out, true
+}
+
+func stringArg(val ref.Val) (string, bool) {
+	typed, ok := val.(types.String)
+	if !ok {
+		return "", false
+	}
+
+	out, ok := typed.Value().(string)
+	return out, ok
+}
+
+// -----------------------------------------------------------------------------
+
+func parseCertificates(value string) ([]*x509.Certificate, bool) {
+	certs, err := pemutil.ParseCertificateBundle([]byte(value))
+	if err != nil || len(certs) == 0 {
+		return nil, false
+	}
+
+	return certs, true
+}
+
+func celKVIsPEMCertificate(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	_, ok = parseCertificates(value)
+	return types.Bool(ok)
+}
+
+// celKVCertExpiresWithin returns true if one of the certificates expires
+// within the given duration. Invalid certificates are considered as expired.
+func celKVCertExpiresWithin(lhs, rhs ref.Val) ref.Val {
+	d, ok := rhs.(types.Duration)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	certs, ok := parseCertificates(value)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	deadline := time.Now().Add(d.Duration)
+	for _, c := range certs {
+		if c.NotAfter.Before(deadline) {
+			return types.Bool(true)
+		}
+	}
+
+	return types.Bool(false)
+}
+
+func celKVCertHasSAN(lhs, rhs ref.Val) ref.Val {
+	name, ok := stringArg(rhs)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	certs, ok := parseCertificates(value)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	// Only the leaf certificate is checked
+	leaf := certs[0]
+	for _, dns := range leaf.DNSNames {
+		if strings.EqualFold(dns, name) {
+			return types.Bool(true)
+		}
+	}
+	for _, email := range leaf.EmailAddresses {
+		if strings.EqualFold(email, name) {
+			return types.Bool(true)
+		}
+	}
+	for _, ip := range leaf.IPAddresses {
+		if ip.String() == name {
+			return types.Bool(true)
+		}
+	}
+	for _, uri := range leaf.URIs {
+		if uri.String() == name {
+			return types.Bool(true)
+		}
+	}
+
+	return types.Bool(false)
+}
+
+// -----------------------------------------------------------------------------
+
+func parsePrivateKey(value string) (interface{}, bool) {
+	// PEM encoded key
+	if key, err := pemutil.Parse([]byte(value)); err == nil {
+		return key, true
+	}
+
+	// JWK encoded key
+	key, err := crypto.FromJWK(value)
+	if err != nil {
+		return nil, false
+	}
+	if pair, ok := key.(struct {
+		Private interface{}
+		Public  interface{}
+	}); ok {
+		return pair.Private, true
+	}
+
+	return nil, false
+}
+
+// celKVIsPrivateKey returns true if the secret is a private key of the given
+// type (rsa, ec, ed25519 or any) with at least the given size in bits.
+func celKVIsPrivateKey(values ...ref.Val) ref.Val {
+	if len(values) != 3 {
+		return types.Bool(false)
+	}
+
+	keyType, ok := stringArg(values[1])
+	if !ok {
+		return types.Bool(false)
+	}
+
+	minBits, ok := values[2].(types.Int)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	value, ok := secretString(values[0])
+	if !ok {
+		return types.Bool(false)
+	}
+
+	key, ok := parsePrivateKey(value)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	var (
+		kt   string
+		bits int
+	)
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		kt, bits = "rsa", k.N.BitLen()
+	case *ecdsa.PrivateKey:
+		kt, bits = "ec", k.Curve.Params().BitSize
+	case ed25519.PrivateKey, *ed25519.PrivateKey:
+		kt, bits = "ed25519", 256
+	default:
+		return types.Bool(false)
+	}
+
+	switch strings.ToLower(keyType) {
+	case "", "any", kt:
+	case "ecdsa":
+		if kt != "ec" {
+			return types.Bool(false)
+		}
+	default:
+		return types.Bool(false)
+	}
+
+	return types.Bool(bits >= int(minBits))
+}
+
+// -----------------------------------------------------------------------------
+
+func celKVIsJWK(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	_, err := crypto.FromJWK(value)
+	return types.Bool(err == nil)
+}
+
+func parseJWTClaims(value string) (map[string]interface{}, bool) {
+	t, err := crypto.ParseJWT(strings.TrimSpace(value))
+	if err != nil {
+		return nil, false
+	}
+
+	token, ok := t.(struct {
+		Headers []jose.Header
+		Claims  map[string]interface{}
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return token.Claims, true
+}
+
+func celKVIsJWT(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(false)
+	}
+
+	_, ok = parseJWTClaims(value)
+	return types.Bool(ok)
+}
+
+// celKVJWTExpired returns true if the token expiration is reached. Invalid
+// tokens are considered as expired.
+func celKVJWTExpired(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	claims, ok := parseJWTClaims(value)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	// Token without expiration
+	raw, ok := claims["exp"]
+	if !ok {
+		return types.Bool(false)
+	}
+	exp, ok := raw.(float64)
+	if !ok {
+		return types.Bool(true)
+	}
+
+	return types.Bool(!time.Now().Before(time.Unix(int64(exp), 0)))
+}
+
+// -----------------------------------------------------------------------------
+
+// celKVPasswordEntropy returns the estimated entropy bits of the secret value
+// based on its length and used character classes.
+func celKVPasswordEntropy(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Double(0)
+	}
+
+	return types.Double(PasswordEntropy(value))
+}
+
+// PasswordEntropy returns the estimated entropy bits of the given value based
+// on its length and used character classes.
+func PasswordEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	var (
+		hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+		length                                            int
+	)
+	for _, r := range value {
+		length++
+		switch {
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+	}
+
+	// Compute character pool size
+	pool := 0
+	if hasLower {
+		pool += 26
+	}
+	if hasUpper {
+		pool += 26
+	}
+	if hasDigit {
+		pool += 10
+	}
+	if hasSymbol {
+		pool += 33
+	}
+	if hasOther {
+		pool += 100
+	}
+
+	return float64(length) * math.Log2(float64(pool))
+}
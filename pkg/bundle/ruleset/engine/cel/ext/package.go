@@ -35,18 +35,33 @@ import (
 	htypes "github.com/elastic/harp/pkg/sdk/types"
 )
 
-// Packages exported package operations.
+// Packages exported package operations with the package exposed as `p`
+// variable.
 func Packages() cel.EnvOption {
+	return cel.Lib(packageLib{declareVariable: true})
+}
+
+// PackageFunctions exported package operations without the `p` variable.
+func PackageFunctions() cel.EnvOption {
 	return cel.Lib(packageLib{})
 }
 
-type packageLib struct{}
+type packageLib struct {
+	declareVariable bool
+}
 
-func (packageLib) CompileOptions() []cel.EnvOption {
-	return []cel.EnvOption{
+func (l packageLib) CompileOptions() []cel.EnvOption {
+	opts := []cel.EnvOption{}
+	if l.declareVariable {
 		//nolint:staticcheck // TODO: deprecated usage. Requires an update.
-		cel.Declarations(
+		opts = append(opts, cel.Declarations(
 			decls.NewVar("p", harpPackageObjectType),
+		))
+	}
+
+	return append(opts,
+		//nolint:staticcheck // TODO: deprecated usage. Requires an update.
+		cel.Declarations(
 			decls.NewFunction("match_label",
 				decls.NewInstanceOverload("package_match_label_string",
 					[]*exprpb.Type{harpPackageObjectType, decls.String},
@@ -104,7 +119,7 @@ func (packageLib) CompileOptions() []cel.EnvOption {
 				),
 			),
 		),
-	}
+	)
 }
 
 func (packageLib) ProgramOptions() []cel.ProgramOption {
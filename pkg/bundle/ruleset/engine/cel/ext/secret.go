@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"unicode/utf8"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -44,6 +45,8 @@ type secretLib struct{}
 
 func (secretLib) CompileOptions() []cel.EnvOption {
 	return []cel.EnvOption{
+		// Allow entropy comparison with integer literals
+		cel.CrossTypeNumericComparisons(true),
 		//nolint:staticcheck // TODO: deprecated usage. Requires an update.
 		cel.Declarations(
 			decls.NewFunction("is_base64",
@@ -82,6 +85,60 @@ func (secretLib) CompileOptions() []cel.EnvOption {
 					decls.Bool,
 				),
 			),
+			decls.NewFunction("is_pem_certificate",
+				decls.NewInstanceOverload("kv_is_pem_certificate",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("cert_expires_within",
+				decls.NewInstanceOverload("kv_cert_expires_within_duration",
+					[]*exprpb.Type{harpKVObjectType, decls.Duration},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("cert_has_san",
+				decls.NewInstanceOverload("kv_cert_has_san_string",
+					[]*exprpb.Type{harpKVObjectType, decls.String},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("is_private_key",
+				decls.NewInstanceOverload("kv_is_private_key_string_int",
+					[]*exprpb.Type{harpKVObjectType, decls.String, decls.Int},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("is_jwk",
+				decls.NewInstanceOverload("kv_is_jwk",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("is_jwt",
+				decls.NewInstanceOverload("kv_is_jwt",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("jwt_expired",
+				decls.NewInstanceOverload("kv_jwt_expired",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Bool,
+				),
+			),
+			decls.NewFunction("password_entropy",
+				decls.NewInstanceOverload("kv_password_entropy",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Double,
+				),
+			),
+			decls.NewFunction("length",
+				decls.NewInstanceOverload("kv_length",
+					[]*exprpb.Type{harpKVObjectType},
+					decls.Int,
+				),
+			),
 		),
 	}
 }
@@ -114,6 +171,42 @@ func (secretLib) ProgramOptions() []cel.ProgramOption {
 				Operator: "kv_is_json",
 				Unary:    celValidatorBuilder(&jsonValidator{}),
 			},
+			&functions.Overload{
+				Operator: "kv_is_pem_certificate",
+				Unary:    celKVIsPEMCertificate,
+			},
+			&functions.Overload{
+				Operator: "kv_cert_expires_within_duration",
+				Binary:   celKVCertExpiresWithin,
+			},
+			&functions.Overload{
+				Operator: "kv_cert_has_san_string",
+				Binary:   celKVCertHasSAN,
+			},
+			&functions.Overload{
+				Operator: "kv_is_private_key_string_int",
+				Function: celKVIsPrivateKey,
+			},
+			&functions.Overload{
+				Operator: "kv_is_jwk",
+				Unary:    celKVIsJWK,
+			},
+			&functions.Overload{
+				Operator: "kv_is_jwt",
+				Unary:    celKVIsJWT,
+			},
+			&functions.Overload{
+				Operator: "kv_jwt_expired",
+				Unary:    celKVJWTExpired,
+			},
+			&functions.Overload{
+				Operator: "kv_password_entropy",
+				Unary:    celKVPasswordEntropy,
+			},
+			&functions.Overload{
+				Operator: "kv_length",
+				Unary:    celKVLength,
+			},
 		),
 	}
 }
@@ -149,11 +242,32 @@ type jsonValidator struct{}
 
 func (v *jsonValidator) Validate(in interface{}) error {
 	// Process input
-	if data, ok := in.([]byte); ok {
-		if !json.Valid(data) {
-			return fmt.Errorf("invalid JSON payload")
-		}
+	var data []byte
+	switch value := in.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return fmt.Errorf("unable to validate JSON for %T type", in)
+	}
+
+	if !json.Valid(data) {
+		return fmt.Errorf("invalid JSON payload")
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// celKVLength returns the character count of the secret value.
+func celKVLength(lhs ref.Val) ref.Val {
+	value, ok := secretString(lhs)
+	if !ok {
+		return types.Int(0)
 	}
 
-	return fmt.Errorf("unable to validate JSON for %T type", in)
+	return types.Int(utf8.RuneCountInString(value))
 }
@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ext
+
+import (
+	"testing"
+)
+
+func Test_jsonValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      interface{}
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			in:      nil,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			in:      42,
+			wantErr: true,
+		},
+		{
+			name:    "invalid string",
+			in:      "{not json",
+			wantErr: true,
+		},
+		{
+			name:    "invalid bytes",
+			in:      []byte("{not json"),
+			wantErr: true,
+		},
+		{
+			name: "valid string",
+			in:   `{"host":"db.internal"}`,
+		},
+		{
+			name: "valid bytes",
+			in:   []byte(`["a","b"]`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &jsonValidator{}
+			if err := v.Validate(tt.in); (err != nil) != tt.wantErr {
+				t.Errorf("jsonValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"context"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+)
+
+type contextKey string
+
+func (c contextKey) String() string {
+	return "github.com/elastic/harp/pkg/bundle/ruleset/engine#" + string(c)
+}
+
+var contextKeyBundle = contextKey("bundle")
+
+// WithBundle attaches the evaluated bundle to the context so that package
+// linters can expose it as evaluation context.
+func WithBundle(ctx context.Context, b *bundlev1.Bundle) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, contextKeyBundle, b)
+}
+
+// Bundle gets the evaluated bundle from the context.
+func Bundle(ctx context.Context) (*bundlev1.Bundle, bool) {
+	b, ok := ctx.Value(contextKeyBundle).(*bundlev1.Bundle)
+	return b, ok && b != nil
+}
@@ -23,43 +23,101 @@ import (
 	"fmt"
 	"io"
 
+	//nolint:staticcheck // deprecated package requires refactor
+	"github.com/open-policy-agent/opa/ast"
 	//nolint:staticcheck // deprecated package requires refactor
 	"github.com/open-policy-agent/opa/rego"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/internal/policy"
 	"github.com/elastic/harp/pkg/bundle/ruleset/engine"
 )
 
 const (
 	maxPolicySize = 5 * 1024 * 1025 // 5MB
+	policyQuery   = "data.harp.compliant"
 )
 
-func New(ctx context.Context, r io.Reader) (engine.PackageLinter, error) {
-	// Read all policy content
-	policy, err := io.ReadAll(io.LimitReader(r, maxPolicySize))
+// New returns a Rego based package linter engine. The package is exposed as
+// policy input, and the evaluated bundle as `input.bundle` when attached to
+// the evaluation context.
+//
+// The policy reader can be nil when policy paths are provided.
+func New(ctx context.Context, r io.Reader, opts ...OptionFunc) (engine.PackageLinter, error) {
+	// Prepare the policy
+	query, constraint, err := prepare(ctx, r, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read the policy content: %w", err)
+		return nil, err
 	}
 
-	// Parse and prepare the policy
-	query, err := rego.New(
-		rego.Query("data.harp.compliant"),
-		rego.Module("harp.rego", string(policy)),
-	).PrepareForEval(ctx)
+	// Return engine
+	return &ruleEngine{
+		query:      query,
+		constraint: constraint,
+	}, nil
+}
+
+// NewBundle returns a Rego based bundle linter engine. The bundle is exposed
+// as `input.bundle`.
+func NewBundle(ctx context.Context, r io.Reader, opts ...OptionFunc) (engine.BundleLinter, error) {
+	// Prepare the policy
+	query, constraint, err := prepare(ctx, r, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to prepare for eval: %w", err)
+		return nil, err
 	}
 
 	// Return engine
 	return &ruleEngine{
-		query: query,
+		query:      query,
+		constraint: constraint,
 	}, nil
 }
 
 // -----------------------------------------------------------------------------
 
+func prepare(ctx context.Context, r io.Reader, opts ...OptionFunc) (rego.PreparedEvalQuery, string, error) {
+	// Default options
+	dopts := &options{
+		query: policyQuery,
+	}
+	for _, o := range opts {
+		o(dopts)
+	}
+
+	src := &policy.Source{
+		Query: dopts.query,
+		Paths: dopts.paths,
+		Data:  dopts.data,
+	}
+
+	if r != nil {
+		// Read all policy content
+		module, err := io.ReadAll(io.LimitReader(r, maxPolicySize))
+		if err != nil {
+			return rego.PreparedEvalQuery{}, "", fmt.Errorf("unable to read the policy content: %w", err)
+		}
+		src.Module = string(module)
+	}
+
+	// Parse and prepare the policy
+	query, err := policy.Prepare(ctx, src)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, "", err
+	}
+
+	// No error
+	return query, dopts.query, nil
+}
+
+// -----------------------------------------------------------------------------
+
 type ruleEngine struct {
-	query rego.PreparedEvalQuery
+	query      rego.PreparedEvalQuery
+	constraint string
+
+	// Bundle input cache
+	bundle      *bundlev1.Bundle
+	bundleValue ast.Value
 }
 
 func (re *ruleEngine) EvaluatePackage(ctx context.Context, p *bundlev1.Package) error {
@@ -68,31 +126,83 @@ func (re *ruleEngine) EvaluatePackage(ctx context.Context, p *bundlev1.Package)
 		return errors.New("unable to evaluate nil package")
 	}
 
+	// Convert the package as input
+	input, err := ast.InterfaceToValue(p)
+	if err != nil {
+		return fmt.Errorf("unable to prepare package input: %w", err)
+	}
+
+	// Expose bundle context
+	if b, ok := engine.Bundle(ctx); ok {
+		obj, ok := input.(ast.Object)
+		if !ok {
+			return fmt.Errorf("unexpected package input type %T", input)
+		}
+
+		bundleValue, err := re.bundleInput(b)
+		if err != nil {
+			return err
+		}
+		obj.Insert(ast.StringTerm("bundle"), ast.NewTerm(bundleValue))
+	}
+
 	// Evaluation with the given package
-	results, err := re.query.Eval(ctx, rego.EvalInput(p))
+	return re.evaluate(ctx, input)
+}
+
+func (re *ruleEngine) EvaluateBundle(ctx context.Context, b *bundlev1.Bundle) error {
+	// Check arguments
+	if b == nil {
+		return errors.New("unable to evaluate nil bundle")
+	}
+
+	bundleValue, err := re.bundleInput(b)
 	if err != nil {
-		return fmt.Errorf("unable to evaluate the policy: %w", err)
-	} else if len(results) == 0 {
-		// Handle undefined result.
-		return nil
+		return err
 	}
 
-	for _, result := range results {
-		for _, expression := range result.Expressions {
-			// Extract result
-			compliant, ok := expression.Value.(bool)
-			if !ok {
-				// Handle unexpected result type.
-				return errors.New("the policy must return boolean")
-			}
+	// Evaluation with the given bundle
+	return re.evaluate(ctx, ast.NewObject(
+		ast.Item(ast.StringTerm("bundle"), ast.NewTerm(bundleValue)),
+	))
+}
+
+// bundleInput converts the bundle once for all package evaluations.
+func (re *ruleEngine) bundleInput(b *bundlev1.Bundle) (ast.Value, error) {
+	if re.bundle == b && re.bundleValue != nil {
+		return re.bundleValue, nil
+	}
+
+	value, err := ast.InterfaceToValue(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare bundle input: %w", err)
+	}
+
+	// Update cache
+	re.bundle, re.bundleValue = b, value
+
+	return value, nil
+}
+
+func (re *ruleEngine) evaluate(ctx context.Context, input ast.Value) error {
+	decision, err := policy.Evaluate(ctx, re.query, rego.EvalParsedInput(input))
+	if err != nil {
+		return err
+	}
+
+	// Handle undefined result.
+	if !decision.Defined {
+		return nil
+	}
 
-			// Check package compliance
-			if !compliant {
-				return engine.ErrRuleNotValid
-			}
+	// Check compliance
+	if !decision.Allowed {
+		return &engine.ConstraintError{
+			Constraint: re.constraint,
+			Messages:   decision.Messages,
 		}
 	}
 
-	// Package validated
+	// Input validated
 	return nil
 }
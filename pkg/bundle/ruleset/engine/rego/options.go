@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rego
+
+type options struct {
+	query string
+	paths []string
+	data  []string
+}
+
+// OptionFunc is used to customize the policy evaluation.
+type OptionFunc func(o *options)
+
+// -----------------------------------------------------------------------------
+
+// WithQuery sets the query evaluated by the policy. The query result must be
+// a boolean or a collection of violation messages (`deny[msg]`).
+func WithQuery(value string) OptionFunc {
+	return func(o *options) {
+		o.query = value
+	}
+}
+
+// WithPaths adds policy files, directories or OPA bundle archives.
+func WithPaths(values ...string) OptionFunc {
+	return func(o *options) {
+		o.paths = append(o.paths, values...)
+	}
+}
+
+// WithData adds JSON or YAML data document files exposed as `data`.
+func WithData(values ...string) OptionFunc {
+	return func(o *options) {
+		o.data = append(o.data, values...)
+	}
+}
@@ -0,0 +1,210 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ExemptionAnnotation is the package or bundle annotation used to declare
+	// rule exemptions.
+	ExemptionAnnotation = "harp.elastic.co/v1/ruleset#exempt"
+
+	exemptionDateFormat = "2006-01-02"
+)
+
+// Exemption describes a time-boxed rule exemption for a package.
+type Exemption struct {
+	Rule   string
+	Until  time.Time
+	Reason string
+	// DateOnly is set when the expiration is declared as a date, the
+	// exemption is then valid until the end of this day.
+	DateOnly bool
+}
+
+// Active returns true if the exemption is still valid at the given time.
+// Date-only expiration includes the complete day.
+func (e *Exemption) Active(now time.Time) bool {
+	until := e.Until
+	if e.DateOnly {
+		until = until.AddDate(0, 0, 1)
+	}
+
+	return now.Before(until)
+}
+
+// Expiration returns the expiration as declared in the annotation format.
+func (e *Exemption) Expiration() string {
+	if e.DateOnly {
+		return e.Until.Format(exemptionDateFormat)
+	}
+
+	return e.Until.Format(time.RFC3339)
+}
+
+// ParseExemptions decodes exemption annotation value. Multiple exemptions are
+// separated by a new line. Attribute values can be double-quoted to contain
+// separators, quoted values use Go string escaping.
+//
+// Format: RULE-ID;until=2026-12-31;reason="Migration in progress; see #123"
+func ParseExemptions(value string) ([]*Exemption, error) {
+	res := []*Exemption{}
+
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts, err := splitExemption(line)
+		if err != nil {
+			return nil, fmt.Errorf("exemption '%s' is malformed: %w", line, err)
+		}
+
+		e := &Exemption{
+			Rule: strings.TrimSpace(parts[0]),
+		}
+		if e.Rule == "" {
+			return nil, fmt.Errorf("exemption '%s' must start with a rule name", line)
+		}
+
+		for _, part := range parts[1:] {
+			k, v, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, fmt.Errorf("exemption '%s' has an invalid attribute '%s'", line, part)
+			}
+
+			v, err = unquoteExemptionValue(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("exemption '%s' has an invalid attribute value '%s': %w", line, part, err)
+			}
+
+			switch strings.TrimSpace(k) {
+			case "until":
+				until, dateOnly, err := parseExemptionDate(v)
+				if err != nil {
+					return nil, fmt.Errorf("exemption '%s' has an invalid expiration date: %w", line, err)
+				}
+				e.Until, e.DateOnly = until, dateOnly
+			case "reason":
+				e.Reason = v
+			default:
+				return nil, fmt.Errorf("exemption '%s' has an unsupported attribute '%s'", line, k)
+			}
+		}
+
+		// Exemptions must be time-boxed
+		if e.Until.IsZero() {
+			return nil, fmt.Errorf("exemption '%s' must declare an expiration date", line)
+		}
+
+		res = append(res, e)
+	}
+
+	// No error
+	return res, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// splitExemption splits the exemption line on attribute separators located
+// outside of double-quoted values.
+func splitExemption(line string) ([]string, error) {
+	var (
+		parts   = []string{}
+		start   = 0
+		quoted  = false
+		escaped = false
+	)
+
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == ';':
+			parts = append(parts, line[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quoted value")
+	}
+
+	// No error
+	return append(parts, line[start:]), nil
+}
+
+func unquoteExemptionValue(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		return value, nil
+	}
+
+	return strconv.Unquote(value)
+}
+
+func parseExemptionDate(value string) (time.Time, bool, error) {
+	// Date only, valid until the end of the day
+	if t, err := time.Parse(exemptionDateFormat, value); err == nil {
+		return t, true, nil
+	}
+
+	// Complete timestamp
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.New("date must be formatted as YYYY-MM-DD or RFC3339")
+	}
+
+	return t, false, nil
+}
+
+func findExemption(annotations map[string]string, rule string) (*Exemption, error) {
+	// Check arguments
+	if annotations == nil {
+		return nil, nil
+	}
+
+	value, ok := annotations[ExemptionAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	// Decode exemptions
+	exemptions, err := ParseExemptions(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range exemptions {
+		if e.Rule == rule {
+			return e, nil
+		}
+	}
+
+	// No matching exemption
+	return nil, nil
+}
@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExemptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []*Exemption
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  []*Exemption{},
+		},
+		{
+			name:  "date",
+			value: "HARP-SRV-0001;until=2026-12-31;reason=Migration in progress",
+			want: []*Exemption{
+				{
+					Rule:     "HARP-SRV-0001",
+					Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+					Reason:   "Migration in progress",
+					DateOnly: true,
+				},
+			},
+		},
+		{
+			name:  "multiple",
+			value: "HARP-SRV-0001;until=2026-12-31\nHARP-SRV-0002;until=2026-06-01T12:00:00Z;reason=Legacy",
+			want: []*Exemption{
+				{
+					Rule:     "HARP-SRV-0001",
+					Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+					DateOnly: true,
+				},
+				{
+					Rule:   "HARP-SRV-0002",
+					Until:  time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC),
+					Reason: "Legacy",
+				},
+			},
+		},
+		{
+			name:  "quoted reason",
+			value: `HARP-SRV-0001;until=2026-12-31;reason="Blocked by #123; see \"legacy=true\", ask ops!"`,
+			want: []*Exemption{
+				{
+					Rule:     "HARP-SRV-0001",
+					Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+					Reason:   `Blocked by #123; see "legacy=true", ask ops!`,
+					DateOnly: true,
+				},
+			},
+		},
+		{
+			name:  "unquoted reason with punctuation",
+			value: "HARP-SRV-0001;reason=Owner: ops, ticket #42 (a=b)!;until=2026-12-31",
+			want: []*Exemption{
+				{
+					Rule:     "HARP-SRV-0001",
+					Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+					Reason:   "Owner: ops, ticket #42 (a=b)!",
+					DateOnly: true,
+				},
+			},
+		},
+		{
+			name:    "unterminated quote",
+			value:   `HARP-SRV-0001;until=2026-12-31;reason="Blocked; see #123`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid quoted value",
+			value:   `HARP-SRV-0001;until=2026-12-31;reason="Blocked" later`,
+			wantErr: true,
+		},
+		{
+			name:    "missing rule",
+			value:   ";until=2026-12-31",
+			wantErr: true,
+		},
+		{
+			name:    "missing expiration",
+			value:   "HARP-SRV-0001;reason=Forever",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			value:   "HARP-SRV-0001;until=31/12/2026",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported attribute",
+			value:   "HARP-SRV-0001;until=2026-12-31;owner=security",
+			wantErr: true,
+		},
+		{
+			name:    "invalid attribute",
+			value:   "HARP-SRV-0001;until=2026-12-31;reason",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExemptions(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseExemptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.Equal(t, tt.want[i].Rule, got[i].Rule)
+				assert.Equal(t, tt.want[i].Reason, got[i].Reason)
+				assert.True(t, tt.want[i].Until.Equal(got[i].Until))
+				assert.Equal(t, tt.want[i].DateOnly, got[i].DateOnly)
+			}
+		})
+	}
+}
+
+func TestExemption_Active(t *testing.T) {
+	e := &Exemption{
+		Rule:  "HARP-SRV-0001",
+		Until: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.True(t, e.Active(time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)))
+	assert.False(t, e.Active(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Date only expiration includes the complete day
+	e = &Exemption{
+		Rule:     "HARP-SRV-0001",
+		Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		DateOnly: true,
+	}
+
+	assert.True(t, e.Active(time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)))
+	assert.False(t, e.Active(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestExemption_Expiration(t *testing.T) {
+	exemptions, err := ParseExemptions("HARP-SRV-0001;until=2026-12-31\nHARP-SRV-0002;until=2026-06-01T12:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-12-31", exemptions[0].Expiration())
+	assert.Equal(t, "2026-06-01T12:00:00Z", exemptions[1].Expiration())
+}
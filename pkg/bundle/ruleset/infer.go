@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/internal/inspect"
+	"github.com/elastic/harp/pkg/bundle/ruleset/engine/cel/ext"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+// valueShape describes the inferred shape of a secret value.
+type valueShape struct {
+	key     string
+	kind    string
+	keyType string
+	keyBits int
+
+	// Bounds
+	minLength  int
+	minEntropy float64
+}
+
+// signature returns the structural shape identifier, bounds are excluded.
+func (s *valueShape) signature() string {
+	return fmt.Sprintf("%s|%s|%s", s.key, s.kind, s.keyType)
+}
+
+// merge widens bounds to accept both values.
+func (s *valueShape) merge(other *valueShape) {
+	if other.keyBits < s.keyBits {
+		s.keyBits = other.keyBits
+	}
+	if other.minLength < s.minLength {
+		s.minLength = other.minLength
+	}
+	if other.minEntropy < s.minEntropy {
+		s.minEntropy = other.minEntropy
+	}
+}
+
+// constraints returns CEL constraints matching the value shape.
+func (s *valueShape) constraints() []string {
+	res := []string{}
+	switch s.kind {
+	case inspect.FormatCertificate:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_pem_certificate()`, s.key))
+	case inspect.FormatPrivateKey:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_private_key(%q, %d)`, s.key, s.keyType, s.keyBits))
+	case inspect.FormatJWT:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_jwt()`, s.key))
+	case inspect.FormatJWK:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_jwk()`, s.key))
+	case inspect.FormatJSON:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_json()`, s.key))
+	case inspect.FormatUUID:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_uuid()`, s.key))
+	case inspect.FormatEmail:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_email()`, s.key))
+	case inspect.FormatURL:
+		res = append(res, fmt.Sprintf(`p.secret(%q).is_url()`, s.key))
+	case inspect.FormatBase64, inspect.FormatString:
+		if s.kind == inspect.FormatBase64 {
+			res = append(res, fmt.Sprintf(`p.secret(%q).is_base64()`, s.key))
+		}
+
+		// Lower bounds only, observed values don't define an exact length
+		res = append(res, fmt.Sprintf(`p.secret(%q).length() >= %d`, s.key, s.minLength))
+		if entropy := int(math.Floor(s.minEntropy)); entropy > 0 {
+			res = append(res, fmt.Sprintf(`p.secret(%q).password_entropy() >= %d`, s.key, entropy))
+		}
+	}
+
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+// inferValueShapes inspects package secret values. Secrets with non string or
+// empty values are ignored.
+func inferValueShapes(p *bundlev1.Package) []*valueShape {
+	res := []*valueShape{}
+	if p.Secrets == nil {
+		return res
+	}
+
+	for _, kv := range p.Secrets.Data {
+		if kv == nil {
+			continue
+		}
+
+		var value string
+		if err := secret.Unpack(kv.Value, &value); err != nil || value == "" {
+			continue
+		}
+
+		res = append(res, inferValueShape(kv.Key, value))
+	}
+
+	// Sort by key
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].key < res[j].key
+	})
+
+	return res
+}
+
+func inferValueShape(key, value string) *valueShape {
+	s := &valueShape{
+		key:  key,
+		kind: inspect.FormatString,
+	}
+
+	// Detect the value format
+	info := inspect.Inspect(value)
+	switch info.Format {
+	case inspect.FormatPEM, inspect.FormatPublicKey:
+		// No dedicated constraint
+	case inspect.FormatPrivateKey:
+		s.kind, s.keyType, s.keyBits = info.Format, info.KeyType, info.KeyBits
+	default:
+		s.kind = info.Format
+	}
+
+	// Bounds
+	s.minLength = utf8.RuneCountInString(value)
+	s.minEntropy = ext.PasswordEntropy(value)
+
+	return s
+}
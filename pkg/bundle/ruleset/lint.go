@@ -18,7 +18,6 @@
 package ruleset
 
 import (
-	"errors"
 	"fmt"
 	"io"
 
@@ -34,8 +33,24 @@ func JSONSchema() []byte {
 	return jsonschema.BundleV1RuleSetSchema()
 }
 
-// Lint to input reader content with Bundle jsonschema.
+// SuiteJSONSchema returns the used json schema for ruleset test validation.
+func SuiteJSONSchema() []byte {
+	return jsonschema.BundleV1RuleSetTestSchema()
+}
+
+// Lint to input reader content with RuleSet jsonschema.
 func Lint(r io.Reader) ([]gojsonschema.ResultError, error) {
+	return lint(r, jsonschema.BundleV1RuleSetSchema(), "ruleset")
+}
+
+// LintSuite to input reader content with RuleSetTest jsonschema.
+func LintSuite(r io.Reader) ([]gojsonschema.ResultError, error) {
+	return lint(r, jsonschema.BundleV1RuleSetTestSchema(), "ruleset test")
+}
+
+// -----------------------------------------------------------------------------
+
+func lint(r io.Reader, schema []byte, kind string) ([]gojsonschema.ResultError, error) {
 	// Check arguments
 	if types.IsNil(r) {
 		return nil, fmt.Errorf("reader is nil")
@@ -54,16 +69,16 @@ func Lint(r io.Reader) ([]gojsonschema.ResultError, error) {
 	}
 
 	// Prepare loaders
-	schemaLoader := gojsonschema.NewBytesLoader(jsonschema.BundleV1RuleSetSchema())
+	schemaLoader := gojsonschema.NewBytesLoader(schema)
 	documentLoader := gojsonschema.NewBytesLoader(jsonData)
 
 	// Validate
 	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
 	if err != nil {
-		return nil, fmt.Errorf("ruleset validation failed %w", err)
+		return nil, fmt.Errorf("%s validation failed %w", kind, err)
 	}
 	if !result.Valid() {
-		return result.Errors(), errors.New("ruleset not valid")
+		return result.Errors(), fmt.Errorf("%s not valid", kind)
 	}
 
 	// No error
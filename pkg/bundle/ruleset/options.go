@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+type options struct {
+	inferValues      bool
+	collapsePackages bool
+}
+
+// OptionFunc is the function used to configure the ruleset generation.
+type OptionFunc func(o *options)
+
+// -----------------------------------------------------------------------------
+
+// WithValueInference enables secret value inspection to generate type,
+// length and entropy constraints.
+func WithValueInference(value bool) OptionFunc {
+	return func(o *options) {
+		o.inferValues = value
+	}
+}
+
+// WithPackageCollapse enables similar packages grouping as one glob rule.
+func WithPackageCollapse(value bool) OptionFunc {
+	return func(o *options) {
+		o.collapsePackages = value
+	}
+}
@@ -21,72 +21,103 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gobwas/glob"
+	"go.uber.org/zap"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle/ruleset/engine"
 	"github.com/elastic/harp/pkg/bundle/ruleset/engine/cel"
 	"github.com/elastic/harp/pkg/bundle/ruleset/engine/rego"
+	"github.com/elastic/harp/pkg/sdk/log"
 )
 
-// Evaluate given bundl using the loaded ruleset.
+// Evaluate given bundle using the loaded ruleset. It returns the first
+// blocking violation as an error, non-blocking violations are logged.
+func Evaluate(ctx context.Context, b *bundlev1.Bundle, spec *bundlev1.RuleSet) error {
+	// Evaluate all rules
+	report, err := EvaluateAll(ctx, b, spec)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range report.Rules {
+		for _, v := range r.Violations {
+			if v.Blocking() {
+				return errors.New(v.Message)
+			}
+			log.For(ctx).Warn("Non-blocking rule violation", zap.String("rule", v.Rule), zap.String("package", v.Package), zap.String("severity", v.Severity), zap.Bool("exempted", v.Exempted))
+		}
+	}
+
+	// No error
+	return nil
+}
+
+// EvaluateAll evaluates the given bundle using the loaded ruleset and collects
+// all violations instead of stopping at the first one.
 //
 //nolint:gocyclo // to refactor
-func Evaluate(ctx context.Context, b *bundlev1.Bundle, spec *bundlev1.RuleSet) error {
+func EvaluateAll(ctx context.Context, b *bundlev1.Bundle, spec *bundlev1.RuleSet) (*Report, error) {
 	// Validate spec
 	if err := Validate(spec); err != nil {
-		return fmt.Errorf("unable to validate spec: %w", err)
+		return nil, fmt.Errorf("unable to validate spec: %w", err)
 	}
 	if b == nil {
-		return fmt.Errorf("cannot process nil bundle")
+		return nil, fmt.Errorf("cannot process nil bundle")
 	}
 
 	// Prepare selectors
 	if len(spec.Spec.Rules) == 0 {
-		return fmt.Errorf("empty ruleset")
+		return nil, fmt.Errorf("empty ruleset")
+	}
+
+	// Exemptions are evaluated with the same reference time
+	now := time.Now().UTC()
+
+	// Expose the bundle to package rules
+	ctx = engine.WithBundle(ctx, b)
+
+	report := &Report{
+		RuleSet: spec.Meta.Name,
+		Rules:   make([]*RuleResult, 0, len(spec.Spec.Rules)),
 	}
 
 	// Process each rule
 	for _, r := range spec.Spec.Rules {
-		// Compile path matcher
-		pathMatcher, err := glob.Compile(r.Path)
-		if err != nil {
-			return fmt.Errorf("unable to compile path matcher: %w", err)
+		if r == nil {
+			return nil, errors.New("unable to compile nil rule")
+		}
+
+		res := &RuleResult{
+			Name:        r.Name,
+			Description: r.Description,
+			Path:        r.Path,
+			Scope:       ruleScope(r),
+			Severity:    ruleSeverity(r),
+			Violations:  []*Violation{},
 		}
 
-		var (
-			vm    engine.PackageLinter
-			vmErr error
-		)
-
-		switch {
-		case len(r.Constraints) > 0:
-			// Compile constraints
-			vm, vmErr = cel.New(r.Constraints)
-		case r.RegoFile != "":
-			// Open policy file
-			f, err := os.Open(r.RegoFile)
+		// Bundle scoped rule
+		if res.Scope == ScopeBundle {
+			violations, err := evaluateBundleRule(ctx, r, b, now)
 			if err != nil {
-				return fmt.Errorf("unable to open rego policy file: %w", err)
+				return nil, err
 			}
-
-			// Create a evaluation context
-			vm, vmErr = rego.New(ctx, f)
-		case r.Rego != "":
-			// Create a evaluation context
-			vm, vmErr = rego.New(ctx, strings.NewReader(r.Rego))
-		default:
-			return errors.New("one of 'constraints', 'rego' or 'rego_file' property must be defined")
-		}
-		if vmErr != nil {
-			return fmt.Errorf("unable to prepare evaluation context: %w", vmErr)
+			res.Violations = append(res.Violations, violations...)
+			report.Rules = append(report.Rules, res)
+			continue
 		}
 
-		// A rule must match at least one time.
-		matchOnce := false
+		// Compile the rule
+		pathMatcher, vm, err := compileRule(ctx, r)
+		if err != nil {
+			return nil, err
+		}
 
 		// For each package
 		for _, p := range b.Packages {
@@ -96,25 +127,220 @@ func Evaluate(ctx context.Context, b *bundlev1.Bundle, spec *bundlev1.RuleSet) e
 			}
 
 			// If package match the path filter.
-			if pathMatcher.Match(p.Name) {
-				matchOnce = true
-
-				errEval := vm.EvaluatePackage(ctx, p)
-				if errEval != nil {
-					if errors.Is(errEval, engine.ErrRuleNotValid) {
-						return fmt.Errorf("package '%s' doesn't validate rule '%s'", p.Name, r.Name)
-					}
-					return fmt.Errorf("unexpected error occurred during constraints evaluation: %w", errEval)
-				}
+			if !pathMatcher.Match(p.Name) {
+				continue
 			}
+			res.Packages++
+
+			errEval := vm.EvaluatePackage(ctx, p)
+			if errEval == nil {
+				continue
+			}
+			if !errors.Is(errEval, engine.ErrRuleNotValid) {
+				return nil, fmt.Errorf("unexpected error occurred during constraints evaluation: %w", errEval)
+			}
+
+			res.Violations = append(res.Violations, newPackageViolations(r, p, errEval, now)...)
 		}
 
 		// Check matching constraint
-		if !matchOnce {
-			return fmt.Errorf("rule '%s' didn't match any packages", r.Name)
+		if res.Packages == 0 {
+			res.Violations = append(res.Violations, newViolation(r, fmt.Sprintf("rule '%s' didn't match any packages", r.Name), nil, now))
 		}
+
+		report.Rules = append(report.Rules, res)
+	}
+
+	// No error
+	return report, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func checkRule(r *bundlev1.Rule) error {
+	// Check arguments
+	if r == nil {
+		return errors.New("unable to compile nil rule")
+	}
+
+	// Check severity
+	switch r.Severity {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("rule '%s' has an invalid severity '%s'", r.Name, r.Severity)
+	}
+
+	// Check scope
+	switch r.Scope {
+	case "", ScopePackage, ScopeBundle:
+	default:
+		return fmt.Errorf("rule '%s' has an invalid scope '%s'", r.Name, r.Scope)
 	}
 
 	// No error
 	return nil
 }
+
+func compileRule(ctx context.Context, r *bundlev1.Rule) (glob.Glob, engine.PackageLinter, error) {
+	// Check arguments
+	if err := checkRule(r); err != nil {
+		return nil, nil, err
+	}
+
+	// Compile path matcher
+	pathMatcher, err := glob.Compile(r.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to compile path matcher: %w", err)
+	}
+
+	// Prepare evaluation engine
+	vm, err := newLinter(ctx, r, cel.New, rego.New)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// No error
+	return pathMatcher, vm, nil
+}
+
+func evaluateBundleRule(ctx context.Context, r *bundlev1.Rule, b *bundlev1.Bundle, now time.Time) ([]*Violation, error) {
+	// Check arguments
+	if err := checkRule(r); err != nil {
+		return nil, err
+	}
+
+	// Prepare evaluation engine
+	vm, err := newLinter(ctx, r, cel.NewBundle, rego.NewBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluate the complete bundle
+	errEval := vm.EvaluateBundle(ctx, b)
+	if errEval == nil {
+		return nil, nil
+	}
+	if !errors.Is(errEval, engine.ErrRuleNotValid) {
+		return nil, fmt.Errorf("unexpected error occurred during constraints evaluation: %w", errEval)
+	}
+
+	// No error
+	return newViolations(r, fmt.Sprintf("bundle doesn't validate rule '%s'", r.Name), errEval, b.Annotations, now), nil
+}
+
+func newLinter[T any](ctx context.Context, r *bundlev1.Rule, celFactory func([]string) (T, error), regoFactory func(context.Context, io.Reader, ...rego.OptionFunc) (T, error)) (T, error) {
+	var (
+		vm    T
+		vmErr error
+	)
+
+	// Rego evaluation options
+	opts := []rego.OptionFunc{
+		rego.WithPaths(r.RegoPaths...),
+		rego.WithData(r.RegoData...),
+	}
+	if r.RegoQuery != "" {
+		opts = append(opts, rego.WithQuery(r.RegoQuery))
+	}
+
+	switch {
+	case len(r.Constraints) > 0:
+		// Compile constraints
+		vm, vmErr = celFactory(r.Constraints)
+	case r.RegoFile != "":
+		// Open policy file
+		f, err := os.Open(r.RegoFile)
+		if err != nil {
+			return vm, fmt.Errorf("unable to open rego policy file: %w", err)
+		}
+		defer f.Close()
+
+		// Create a evaluation context
+		vm, vmErr = regoFactory(ctx, f, opts...)
+	case r.Rego != "":
+		// Create a evaluation context
+		vm, vmErr = regoFactory(ctx, strings.NewReader(r.Rego), opts...)
+	case len(r.RegoPaths) > 0:
+		// Create a evaluation context from policy paths
+		vm, vmErr = regoFactory(ctx, nil, opts...)
+	default:
+		return vm, errors.New("one of 'constraints', 'rego', 'rego_file' or 'rego_paths' property must be defined")
+	}
+	if vmErr != nil {
+		return vm, fmt.Errorf("unable to prepare evaluation context: %w", vmErr)
+	}
+
+	// No error
+	return vm, nil
+}
+
+func ruleSeverity(r *bundlev1.Rule) string {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+func ruleScope(r *bundlev1.Rule) string {
+	if r == nil || r.Scope == "" {
+		return ScopePackage
+	}
+	return r.Scope
+}
+
+func newPackageViolations(r *bundlev1.Rule, p *bundlev1.Package, errEval error, now time.Time) []*Violation {
+	res := newViolations(r, fmt.Sprintf("package '%s' doesn't validate rule '%s'", p.Name, r.Name), errEval, p.Annotations, now)
+	for _, v := range res {
+		v.Package = p.Name
+	}
+
+	return res
+}
+
+// newViolations creates a violation for each message reported by the failing
+// constraint.
+func newViolations(r *bundlev1.Rule, message string, errEval error, annotations map[string]string, now time.Time) []*Violation {
+	// Extract the failing constraint
+	var constraintErr *engine.ConstraintError
+	if !errors.As(errEval, &constraintErr) {
+		return []*Violation{newViolation(r, message, annotations, now)}
+	}
+	if len(constraintErr.Messages) == 0 {
+		v := newViolation(r, message, annotations, now)
+		v.Constraint = constraintErr.Constraint
+		return []*Violation{v}
+	}
+
+	res := make([]*Violation, 0, len(constraintErr.Messages))
+	for _, msg := range constraintErr.Messages {
+		v := newViolation(r, fmt.Sprintf("%s: %s", message, msg), annotations, now)
+		v.Constraint = constraintErr.Constraint
+		res = append(res, v)
+	}
+
+	return res
+}
+
+func newViolation(r *bundlev1.Rule, message string, annotations map[string]string, now time.Time) *Violation {
+	v := &Violation{
+		Rule:     r.Name,
+		Severity: ruleSeverity(r),
+		Message:  message,
+	}
+
+	// Check exemptions
+	e, err := findExemption(annotations, r.Name)
+	switch {
+	case err != nil:
+		v.Message = fmt.Sprintf("%s (invalid exemption annotation: %v)", v.Message, err)
+	case e == nil:
+	case e.Active(now):
+		v.Exempted = true
+		v.ExemptionReason = e.Reason
+		v.ExemptionUntil = e.Expiration()
+	default:
+		v.Message = fmt.Sprintf("%s (exemption expired on %s)", v.Message, e.Expiration())
+	}
+
+	return v
+}
@@ -24,8 +24,10 @@ import (
 	"testing"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/secret"
 	fuzz "github.com/google/gofuzz"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func mustLoadRuleSet(filePath string) *bundlev1.RuleSet {
@@ -42,6 +44,46 @@ func mustLoadRuleSet(filePath string) *bundlev1.RuleSet {
 	return p
 }
 
+func bundleRulesBundle(checkoutHost string) *bundlev1.Bundle {
+	return &bundlev1.Bundle{
+		Labels: map[string]string{
+			"environment": "production",
+		},
+		Packages: []*bundlev1.Package{
+			{
+				Name: "app/billing/database",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "host", Value: mustPack("db1.internal")},
+					},
+				},
+			},
+			{
+				Name: "app/checkout/database",
+				Secrets: &bundlev1.SecretChain{
+					Data: []*bundlev1.KV{
+						{Key: "host", Value: mustPack(checkoutHost)},
+					},
+				},
+			},
+			{
+				Name: "infra/billing/database",
+			},
+			{
+				Name: "infra/checkout/database",
+			},
+		},
+	}
+}
+
+func mustPack(in interface{}) []byte {
+	out, err := secret.Pack(in)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
 func TestEvaluate(t *testing.T) {
 	type args struct {
 		specFilePath string
@@ -78,6 +120,78 @@ func TestEvaluate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "cso - exempted package",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/cso.yaml",
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/qa/security",
+							Annotations: map[string]string{
+								ExemptionAnnotation: "HARP-SRV-0001;until=2999-12-31;reason=Migration in progress",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cso - expired exemption",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/cso.yaml",
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/qa/security",
+							Annotations: map[string]string{
+								ExemptionAnnotation: "HARP-SRV-0001;until=2000-01-01;reason=Migration in progress",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "warning - rule didn't match",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/multiple-rules.yaml",
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/security/harp/v1.0.0/server/database/credentials",
+							Secrets: &bundlev1.SecretChain{
+								Data: []*bundlev1.KV{
+									{Key: "DB_HOST"},
+									{Key: "DB_NAME"},
+									{Key: "DB_USER"},
+									{Key: "DB_PASSWORD"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bundle rules - valid bundle",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/bundle-rules.yaml",
+				b:            bundleRulesBundle("db2.internal"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "bundle rules - shared host",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/bundle-rules.yaml",
+				b:            bundleRulesBundle("db1.internal"),
+			},
+			wantErr: true,
+		},
 		{
 			name: "cso - valid bundle",
 			args: args{
@@ -275,7 +389,12 @@ func TestEvaluate(t *testing.T) {
 func TestEvaluate_Fuzz(t *testing.T) {
 	// Making sure the descrption never panics
 	for i := 0; i < 500; i++ {
-		f := fuzz.New()
+		f := fuzz.New().Funcs(
+			// Protobuf struct values use oneof interfaces not supported by the fuzzer
+			func(s **structpb.Struct, c fuzz.Continue) {
+				*s = nil
+			},
+		)
 
 		rs := bundlev1.RuleSet{
 			ApiVersion: "harp.elastic.co/v1",
@@ -20,6 +20,8 @@ package ruleset
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -64,3 +66,76 @@ func YAML(r io.Reader) (*bundlev1.RuleSet, error) {
 	// No error
 	return &def, nil
 }
+
+// ResolvePaths resolves relative rule policy paths and data documents against
+// the directory of the given ruleset file. For compatibility, a relative
+// policy file is kept as is if it exists from the working directory. Nothing
+// is resolved for rulesets read from stdin.
+func ResolvePaths(spec *bundlev1.RuleSet, filename string) {
+	// Check arguments
+	if spec == nil || spec.Spec == nil || filename == "" || filename == "-" {
+		return
+	}
+
+	baseDir := filepath.Dir(filename)
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(baseDir, path)
+	}
+
+	for _, r := range spec.Spec.Rules {
+		if r == nil {
+			continue
+		}
+
+		if _, err := os.Stat(r.RegoFile); err != nil {
+			r.RegoFile = resolve(r.RegoFile)
+		}
+		for i := range r.RegoPaths {
+			r.RegoPaths[i] = resolve(r.RegoPaths[i])
+		}
+		for i := range r.RegoData {
+			r.RegoData[i] = resolve(r.RegoData[i])
+		}
+	}
+}
+
+// SuiteYAML parses the given reader in order to extract a RuleSetTest
+// specification.
+func SuiteYAML(r io.Reader) (*bundlev1.RuleSetTest, error) {
+	// Check arguments
+	if types.IsNil(r) {
+		return nil, fmt.Errorf("reader is nil")
+	}
+
+	// Drain the reader
+	jsonReader, err := convert.YAMLtoJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse input as RuleSetTest: %w", err)
+	}
+
+	// Drain reader
+	jsonData, err := io.ReadAll(jsonReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to drain all json reader content: %w", err)
+	}
+
+	// Initialize empty definition object
+	def := bundlev1.RuleSetTest{}
+	def.Reset()
+
+	// Deserialize JSON with JSONPB wrapper
+	if err := protojson.Unmarshal(jsonData, &def); err != nil {
+		return nil, fmt.Errorf("unable to decode spec as json: %w", err)
+	}
+
+	// Validate spec
+	if err := ValidateSuite(&def); err != nil {
+		return nil, fmt.Errorf("unable to validate descriptor: %w", err)
+	}
+
+	// No error
+	return &def, nil
+}
@@ -23,6 +23,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
 
 func mustLoad(filePath string) io.Reader {
@@ -91,3 +96,42 @@ func TestYAML(t *testing.T) {
 		})
 	}
 }
+
+func TestResolvePaths(t *testing.T) {
+	newSpec := func() *bundlev1.RuleSet {
+		return &bundlev1.RuleSet{
+			Spec: &bundlev1.RuleSetSpec{
+				Rules: []*bundlev1.Rule{
+					{
+						Name:      "HARP-OWN-0001",
+						RegoFile:  "policy.rego",
+						RegoPaths: []string{"policies", "/etc/harp/policies", "bundle.tar.gz"},
+						RegoData:  []string{"owners.json"},
+					},
+				},
+			},
+		}
+	}
+
+	spec := newSpec()
+	ResolvePaths(spec, filepath.Join("rulesets", "ownership.yaml"))
+	assert.Equal(t, filepath.Join("rulesets", "policy.rego"), spec.Spec.Rules[0].RegoFile)
+	assert.Equal(t, []string{filepath.Join("rulesets", "policies"), "/etc/harp/policies", filepath.Join("rulesets", "bundle.tar.gz")}, spec.Spec.Rules[0].RegoPaths)
+	assert.Equal(t, []string{filepath.Join("rulesets", "owners.json")}, spec.Spec.Rules[0].RegoData)
+
+	// Rulesets read from stdin are not resolved
+	spec = newSpec()
+	ResolvePaths(spec, "-")
+	assert.Equal(t, newSpec(), spec)
+
+	// Policy files relative to the working directory are kept
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	require.NoError(t, os.WriteFile("policy.rego", []byte("package harp"), 0o600))
+	spec = newSpec()
+	ResolvePaths(spec, filepath.Join("rulesets", "ownership.yaml"))
+	assert.Equal(t, "policy.rego", spec.Spec.Rules[0].RegoFile)
+	assert.Equal(t, []string{filepath.Join("rulesets", "owners.json")}, spec.Spec.Rules[0].RegoData)
+}
@@ -0,0 +1,448 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/harp/build/version"
+	"github.com/elastic/harp/pkg/sdk/types"
+)
+
+const (
+	// ReportFormatText renders the report as human readable text.
+	ReportFormatText = "text"
+	// ReportFormatJSON renders the report as JSON.
+	ReportFormatJSON = "json"
+	// ReportFormatSARIF renders the report as a SARIF 2.1.0 log.
+	ReportFormatSARIF = "sarif"
+	// ReportFormatJUnit renders the report as JUnit XML.
+	ReportFormatJUnit = "junit"
+)
+
+const (
+	// SeverityError marks violations failing the evaluation.
+	SeverityError = "error"
+	// SeverityWarning marks violations reported without failing the evaluation.
+	SeverityWarning = "warning"
+	// SeverityInfo marks informative violations.
+	SeverityInfo = "info"
+)
+
+const (
+	// ScopePackage marks rules evaluated for each matching package.
+	ScopePackage = "package"
+	// ScopeBundle marks rules evaluated once with the complete bundle.
+	ScopeBundle = "bundle"
+)
+
+// ReportFormats lists supported report formats.
+var ReportFormats = []string{ReportFormatText, ReportFormatJSON, ReportFormatSARIF, ReportFormatJUnit}
+
+// Report holds the complete ruleset evaluation result.
+type Report struct {
+	RuleSet  string        `json:"ruleset"`
+	Artifact string        `json:"artifact,omitempty"`
+	Rules    []*RuleResult `json:"rules"`
+}
+
+// RuleResult holds the evaluation result of one rule.
+type RuleResult struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Path        string       `json:"path,omitempty"`
+	Scope       string       `json:"scope"`
+	Severity    string       `json:"severity"`
+	Packages    int          `json:"packages"`
+	Violations  []*Violation `json:"violations"`
+}
+
+// Violation describes a rule violation.
+type Violation struct {
+	Rule            string `json:"rule"`
+	Package         string `json:"package,omitempty"`
+	Constraint      string `json:"constraint,omitempty"`
+	Message         string `json:"message"`
+	Severity        string `json:"severity"`
+	Exempted        bool   `json:"exempted,omitempty"`
+	ExemptionReason string `json:"exemptionReason,omitempty"`
+	ExemptionUntil  string `json:"exemptionUntil,omitempty"`
+}
+
+// Blocking returns true if the violation must fail the evaluation.
+func (v *Violation) Blocking() bool {
+	return v.Severity == SeverityError && !v.Exempted
+}
+
+// String returns the violation description.
+func (v *Violation) String() string {
+	res := v.Message
+	if v.Constraint != "" {
+		res = fmt.Sprintf("%s (%s)", res, v.Constraint)
+	}
+	if v.Exempted {
+		res = fmt.Sprintf("%s [exempted until %s: %s]", res, v.ExemptionUntil, v.ExemptionReason)
+	}
+
+	return res
+}
+
+// Violations returns all report violations.
+func (r *Report) Violations() []*Violation {
+	res := []*Violation{}
+	if r == nil {
+		return res
+	}
+
+	for _, rule := range r.Rules {
+		res = append(res, rule.Violations...)
+	}
+
+	return res
+}
+
+// Failures returns blocking violations only.
+func (r *Report) Failures() []*Violation {
+	res := []*Violation{}
+	for _, v := range r.Violations() {
+		if v.Blocking() {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Valid returns true when the report doesn't contain any blocking violation.
+func (r *Report) Valid() bool {
+	return len(r.Failures()) == 0
+}
+
+// -----------------------------------------------------------------------------
+
+// WriteReport renders the report using the given format.
+func WriteReport(w io.Writer, r *Report, format string) error {
+	// Check arguments
+	if types.IsNil(w) {
+		return fmt.Errorf("unable to write report to nil writer")
+	}
+	if r == nil {
+		return fmt.Errorf("unable to write nil report")
+	}
+
+	switch format {
+	case ReportFormatText, "":
+		return writeTextReport(w, r)
+	case ReportFormatJSON:
+		return writeJSONReport(w, r)
+	case ReportFormatSARIF:
+		return writeSARIFReport(w, r)
+	case ReportFormatJUnit:
+		return writeJUnitReport(w, r)
+	default:
+		return fmt.Errorf("unsupported report format '%s', supported formats are %s", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+func writeTextReport(w io.Writer, r *Report) error {
+	violations := r.Violations()
+
+	exempted := 0
+	for _, v := range violations {
+		status := v.Severity
+		if v.Exempted {
+			status = "exempted"
+			exempted++
+		}
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", v.Rule, status, v.String()); err != nil {
+			return fmt.Errorf("unable to write report: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%d violation(s) found (%d failure(s), %d exempted), %d rule(s) evaluated\n", len(violations), len(r.Failures()), exempted, len(r.Rules)); err != nil {
+		return fmt.Errorf("unable to write report: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
+func writeJSONReport(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("unable to encode report as JSON: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func writeSARIFReport(w io.Writer, r *Report) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "harp",
+				Version:        version.Version,
+				InformationURI: "https://github.com/elastic/harp",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	// Rule descriptors are indexed by their unique identifier
+	ruleIndex := map[string]int{}
+	for _, rule := range r.Rules {
+		idx, ok := ruleIndex[rule.Name]
+		if !ok {
+			description := rule.Description
+			if description == "" {
+				description = rule.Name
+			}
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[rule.Name] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   rule.Name,
+				ShortDescription:     sarifMessage{Text: description},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+			})
+		}
+
+		for _, v := range rule.Violations {
+			res := sarifResult{
+				RuleID:    rule.Name,
+				RuleIndex: idx,
+				Level:     sarifLevel(v.Severity),
+				Message:   sarifMessage{Text: v.Message},
+			}
+			if v.Constraint != "" {
+				res.Message.Text = fmt.Sprintf("%s (%s)", v.Message, v.Constraint)
+			}
+			if v.Exempted {
+				res.Suppressions = []sarifSuppression{
+					{Kind: "external", Justification: v.ExemptionReason},
+				}
+			}
+			if loc := sarifViolationLocation(r.Artifact, v); loc != nil {
+				res.Locations = []sarifLocation{*loc}
+			}
+			run.Results = append(run.Results, res)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}); err != nil {
+		return fmt.Errorf("unable to encode report as SARIF: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
+func sarifViolationLocation(artifact string, v *Violation) *sarifLocation {
+	if artifact == "" && v.Package == "" {
+		return nil
+	}
+
+	loc := &sarifLocation{}
+	if artifact != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(artifact)},
+		}
+	}
+	if v.Package != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{
+			{FullyQualifiedName: v.Package, Kind: "package"},
+		}
+	}
+
+	return loc
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+func writeJUnitReport(w io.Writer, r *Report) error {
+	suite := junitTestSuite{
+		Name:      r.RuleSet,
+		Tests:     len(r.Rules),
+		TestCases: []junitTestCase{},
+	}
+
+	for _, rule := range r.Rules {
+		tc := junitTestCase{
+			Name:      rule.Name,
+			ClassName: r.RuleSet,
+		}
+
+		failures, notices := []string{}, []string{}
+		for _, v := range rule.Violations {
+			if v.Blocking() {
+				failures = append(failures, v.String())
+			} else {
+				notices = append(notices, fmt.Sprintf("%s: %s", v.Severity, v.String()))
+			}
+		}
+
+		if len(failures) > 0 {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d violation(s)", len(failures)),
+				Type:    "RuleViolation",
+				Content: strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		if len(notices) > 0 {
+			tc.SystemOut = strings.Join(notices, "\n")
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("unable to write report: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&junitTestSuites{TestSuites: []junitTestSuite{suite}}); err != nil {
+		return fmt.Errorf("unable to encode report as JUnit: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("unable to write report: %w", err)
+	}
+
+	// No error
+	return nil
+}
@@ -0,0 +1,394 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+)
+
+var multipleRulesBundle = &bundlev1.Bundle{
+	Packages: []*bundlev1.Package{
+		{
+			Name: "app/production/database/credentials",
+			Secrets: &bundlev1.SecretChain{
+				Data: []*bundlev1.KV{
+					{Key: "DB_HOST"},
+				},
+			},
+		},
+		{
+			Name: "app/staging/database/credentials",
+		},
+	},
+}
+
+func TestEvaluateAll(t *testing.T) {
+	type args struct {
+		specFilePath string
+		b            *bundlev1.Bundle
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []*Violation
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name: "nil bundle",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/cso.yaml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/cso.yaml",
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/security/harp/v1.0.0/server/database/credentials",
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want:    []*Violation{},
+		},
+		{
+			name: "exemptions",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/cso.yaml",
+				b: &bundlev1.Bundle{
+					Packages: []*bundlev1.Package{
+						{
+							Name: "app/production/exempted",
+							Annotations: map[string]string{
+								ExemptionAnnotation: "HARP-SRV-0001;until=2999-12-31;reason=Migration in progress",
+							},
+						},
+						{
+							Name: "app/production/expired",
+							Annotations: map[string]string{
+								ExemptionAnnotation: "HARP-SRV-0001;until=2000-01-01T00:00:00Z",
+							},
+						},
+						{
+							Name: "app/production/invalid",
+							Annotations: map[string]string{
+								ExemptionAnnotation: "HARP-SRV-0001",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: []*Violation{
+				{
+					Rule:            "HARP-SRV-0001",
+					Package:         "app/production/exempted",
+					Constraint:      "p.is_cso_compliant()",
+					Message:         "package 'app/production/exempted' doesn't validate rule 'HARP-SRV-0001'",
+					Severity:        "error",
+					Exempted:        true,
+					ExemptionReason: "Migration in progress",
+					ExemptionUntil:  "2999-12-31",
+				},
+				{
+					Rule:       "HARP-SRV-0001",
+					Package:    "app/production/expired",
+					Constraint: "p.is_cso_compliant()",
+					Message:    "package 'app/production/expired' doesn't validate rule 'HARP-SRV-0001' (exemption expired on 2000-01-01T00:00:00Z)",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-SRV-0001",
+					Package:    "app/production/invalid",
+					Constraint: "p.is_cso_compliant()",
+					Message:    "package 'app/production/invalid' doesn't validate rule 'HARP-SRV-0001' (invalid exemption annotation: exemption 'HARP-SRV-0001' must declare an expiration date)",
+					Severity:   "error",
+				},
+			},
+		},
+		{
+			name: "bundle rules",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/bundle-rules.yaml",
+				b: func() *bundlev1.Bundle {
+					b := bundleRulesBundle("db1.internal")
+					b.Labels = nil
+					b.Packages = b.Packages[:3]
+					return b
+				}(),
+			},
+			wantErr: false,
+			want: []*Violation{
+				{
+					Rule:       "HARP-BDL-0001",
+					Constraint: `bundle.packages_matching("app/*/database").all(p, bundle.count(p.name.replace("app/", "infra/")) > 0)`,
+					Message:    "bundle doesn't validate rule 'HARP-BDL-0001'",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-BDL-0002",
+					Constraint: `unique(bundle.secrets("app/*/database", "host"))`,
+					Message:    "bundle doesn't validate rule 'HARP-BDL-0002'",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-BDL-0003",
+					Constraint: "data.harp.compliant",
+					Message:    "bundle doesn't validate rule 'HARP-BDL-0003'",
+					Severity:   "error",
+				},
+			},
+		},
+		{
+			name: "multiple violations",
+			args: args{
+				specFilePath: "../../../test/fixtures/ruleset/valid/multiple-rules.yaml",
+				b:            multipleRulesBundle,
+			},
+			wantErr: false,
+			want: []*Violation{
+				{
+					Rule:       "HARP-SRV-0001",
+					Package:    "app/production/database/credentials",
+					Constraint: "p.is_cso_compliant()",
+					Message:    "package 'app/production/database/credentials' doesn't validate rule 'HARP-SRV-0001'",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-SRV-0001",
+					Package:    "app/staging/database/credentials",
+					Constraint: "p.is_cso_compliant()",
+					Message:    "package 'app/staging/database/credentials' doesn't validate rule 'HARP-SRV-0001'",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-SRV-0002",
+					Package:    "app/production/database/credentials",
+					Constraint: "p.has_all_secrets(['DB_HOST','DB_NAME','DB_USER','DB_PASSWORD'])",
+					Message:    "package 'app/production/database/credentials' doesn't validate rule 'HARP-SRV-0002'",
+					Severity:   "error",
+				},
+				{
+					Rule:       "HARP-SRV-0002",
+					Package:    "app/staging/database/credentials",
+					Constraint: "p.has_secret('DB_HOST')",
+					Message:    "package 'app/staging/database/credentials' doesn't validate rule 'HARP-SRV-0002'",
+					Severity:   "error",
+				},
+				{
+					Rule:     "HARP-SRV-0003",
+					Message:  "rule 'HARP-SRV-0003' didn't match any packages",
+					Severity: "warning",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec *bundlev1.RuleSet
+			if tt.args.specFilePath != "" {
+				spec = mustLoadRuleSet(tt.args.specFilePath)
+			}
+
+			got, err := EvaluateAll(context.Background(), tt.args.b, spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, got.Violations())
+			assert.Equal(t, len(got.Failures()) == 0, got.Valid())
+		})
+	}
+}
+
+func TestEvaluateAll_RegoPolicies(t *testing.T) {
+	// Policy paths are relative to the ruleset
+	specPath := "../../../test/fixtures/ruleset/valid/rego-policies.yaml"
+	spec := mustLoadRuleSet(specPath)
+	ResolvePaths(spec, specPath)
+
+	b := &bundlev1.Bundle{
+		Labels: map[string]string{
+			"environment": "production",
+		},
+		Packages: []*bundlev1.Package{
+			{
+				Name: "app/production/database/credentials",
+				Annotations: map[string]string{
+					"harp.elastic.co/v1/package#owner": "security",
+				},
+			},
+			{
+				Name: "app/staging/database/credentials",
+				Annotations: map[string]string{
+					"harp.elastic.co/v1/package#owner": "unknown",
+				},
+			},
+			{
+				Name: "app/qa/database/credentials",
+			},
+		},
+	}
+
+	report, err := EvaluateAll(context.Background(), b, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Violation{
+		{
+			Rule:       "HARP-OWN-0001",
+			Package:    "app/staging/database/credentials",
+			Constraint: "data.harp.deny",
+			Message:    "package 'app/staging/database/credentials' doesn't validate rule 'HARP-OWN-0001': owner 'unknown' is not registered",
+			Severity:   "error",
+		},
+		{
+			Rule:       "HARP-OWN-0001",
+			Package:    "app/qa/database/credentials",
+			Constraint: "data.harp.deny",
+			Message:    "package 'app/qa/database/credentials' doesn't validate rule 'HARP-OWN-0001': package must declare an owner",
+			Severity:   "error",
+		},
+	}, report.Violations())
+
+	// Bundle context is exposed to package policies
+	b.Labels = nil
+	report, err = EvaluateAll(context.Background(), b, spec)
+	assert.NoError(t, err)
+	assert.Len(t, report.Violations(), 5)
+}
+
+func TestWriteReport(t *testing.T) {
+	report, err := EvaluateAll(context.Background(), multipleRulesBundle, mustLoadRuleSet("../../../test/fixtures/ruleset/valid/multiple-rules.yaml"))
+	assert.NoError(t, err)
+
+	t.Run("invalid format", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.Error(t, WriteReport(&out, report, "html"))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, report, ReportFormatText))
+		assert.Contains(t, out.String(), "[HARP-SRV-0002] error: package 'app/staging/database/credentials' doesn't validate rule 'HARP-SRV-0002' (p.has_secret('DB_HOST'))\n")
+		assert.Contains(t, out.String(), "5 violation(s) found (4 failure(s), 0 exempted), 3 rule(s) evaluated\n")
+		assert.Contains(t, out.String(), "[HARP-SRV-0003] warning: rule 'HARP-SRV-0003' didn't match any packages\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, report, ReportFormatJSON))
+
+		var got Report
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, report, &got)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, report, ReportFormatSARIF))
+
+		var got sarifLog
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, "2.1.0", got.Version)
+		assert.Len(t, got.Runs, 1)
+		assert.Len(t, got.Runs[0].Tool.Driver.Rules, 3)
+		assert.Len(t, got.Runs[0].Results, 5)
+		assert.Equal(t, "HARP-SRV-0003", got.Runs[0].Results[4].RuleID)
+		assert.Equal(t, 2, got.Runs[0].Results[4].RuleIndex)
+		assert.Equal(t, "warning", got.Runs[0].Results[4].Level)
+		assert.Empty(t, got.Runs[0].Results[4].Locations)
+		assert.Equal(t, "app/production/database/credentials", got.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+		assert.Nil(t, got.Runs[0].Results[0].Locations[0].PhysicalLocation)
+	})
+
+	t.Run("sarif with artifact", func(t *testing.T) {
+		withArtifact := *report
+		withArtifact.Artifact = "bundles/customer.bundle"
+
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, &withArtifact, ReportFormatSARIF))
+
+		var got sarifLog
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Len(t, got.Runs[0].Results, 5)
+		for _, res := range got.Runs[0].Results {
+			assert.Len(t, res.Locations, 1)
+			assert.Equal(t, "bundles/customer.bundle", res.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+
+		// Bundle scope results only have a physical location
+		assert.Empty(t, got.Runs[0].Results[4].Locations[0].LogicalLocations)
+		assert.Equal(t, "app/production/database/credentials", got.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	})
+
+	t.Run("sarif with duplicate rule names", func(t *testing.T) {
+		duplicated := &Report{
+			RuleSet: "duplicated",
+			Rules: []*RuleResult{
+				{Name: "HARP-SRV-0001", Severity: SeverityError, Violations: []*Violation{{Rule: "HARP-SRV-0001", Message: "first", Severity: SeverityError}}},
+				{Name: "HARP-SRV-0002", Severity: SeverityError, Violations: []*Violation{}},
+				{Name: "HARP-SRV-0001", Severity: SeverityError, Violations: []*Violation{{Rule: "HARP-SRV-0001", Message: "second", Severity: SeverityError}}},
+			},
+		}
+
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, duplicated, ReportFormatSARIF))
+
+		var got sarifLog
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Len(t, got.Runs[0].Tool.Driver.Rules, 2)
+		assert.Len(t, got.Runs[0].Results, 2)
+		for _, res := range got.Runs[0].Results {
+			assert.Equal(t, 0, res.RuleIndex)
+			assert.Equal(t, res.RuleID, got.Runs[0].Tool.Driver.Rules[res.RuleIndex].ID)
+		}
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, WriteReport(&out, report, ReportFormatJUnit))
+
+		var got junitTestSuites
+		assert.NoError(t, xml.Unmarshal(out.Bytes(), &got))
+		assert.Len(t, got.TestSuites, 1)
+		assert.Equal(t, "harp-server", got.TestSuites[0].Name)
+		assert.Equal(t, 3, got.TestSuites[0].Tests)
+		assert.Equal(t, 2, got.TestSuites[0].Failures)
+		assert.Nil(t, got.TestSuites[0].TestCases[2].Failure)
+		assert.Equal(t, "warning: rule 'HARP-SRV-0003' didn't match any packages", got.TestSuites[0].TestCases[2].SystemOut)
+		assert.Equal(t, "2 violation(s)", got.TestSuites[0].TestCases[0].Failure.Message)
+	})
+}
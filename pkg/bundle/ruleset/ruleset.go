@@ -24,6 +24,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/internal/policy"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -51,6 +52,21 @@ func Validate(spec *bundlev1.RuleSet) error {
 		return fmt.Errorf("spec should be 'nil'")
 	}
 
+	// Validate rules
+	names := map[string]struct{}{}
+	for _, r := range spec.Spec.Rules {
+		if err := checkRule(r); err != nil {
+			return err
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("rule name '%s' is not unique", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if ruleScope(r) == ScopePackage && r.Path == "" {
+			return fmt.Errorf("package rule '%s' must declare a path", r.Name)
+		}
+	}
+
 	// No error
 	return nil
 }
@@ -74,3 +90,44 @@ func Checksum(spec *bundlev1.RuleSet) (string, error) {
 	// No error
 	return base64.RawURLEncoding.EncodeToString(checksum[:]), nil
 }
+
+// PolicyChecksum calculates the specification checksum including the content
+// of the policy files, policy paths and data documents referenced by the
+// rules, so that it identifies the evaluated policies.
+func PolicyChecksum(spec *bundlev1.RuleSet) (string, error) {
+	// Validate bundle template
+	if err := Validate(spec); err != nil {
+		return "", fmt.Errorf("unable to validate spec: %w", err)
+	}
+
+	// Encode spec as protobuf
+	payload, err := proto.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode ruleset: %w", err)
+	}
+
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to initialize hash function: %w", err)
+	}
+	h.Write(payload)
+
+	// Add referenced policies and data documents
+	for _, r := range spec.Spec.Rules {
+		if r == nil {
+			continue
+		}
+
+		paths := append([]string{}, r.RegoPaths...)
+		if r.RegoFile != "" {
+			paths = append(paths, r.RegoFile)
+		}
+		paths = append(paths, r.RegoData...)
+		if err := policy.Digest(h, paths...); err != nil {
+			return "", fmt.Errorf("unable to compute rule '%s' policy checksum: %w", r.Name, err)
+		}
+	}
+
+	// No error
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+}
@@ -18,9 +18,14 @@
 package ruleset
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 )
 
@@ -100,6 +105,23 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "duplicate rule names",
+			args: args{
+				spec: &bundlev1.RuleSet{
+					ApiVersion: "harp.elastic.co/v1",
+					Kind:       "RuleSet",
+					Meta:       &bundlev1.RuleSetMeta{},
+					Spec: &bundlev1.RuleSetSpec{
+						Rules: []*bundlev1.Rule{
+							{Name: "HARP-SRV-0001", Path: "app/production/*"},
+							{Name: "HARP-SRV-0001", Path: "app/staging/*"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -151,3 +173,55 @@ func TestChecksum(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyChecksum(t *testing.T) {
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "policy.rego")
+	dataFile := filepath.Join(dir, "data", "owners.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dataFile), 0o755))
+	require.NoError(t, os.WriteFile(policyFile, []byte("package harp\n\ndefault compliant = true\n"), 0o600))
+	require.NoError(t, os.WriteFile(dataFile, []byte(`{"owners":{"security":true}}`), 0o600))
+
+	spec := &bundlev1.RuleSet{
+		ApiVersion: "harp.elastic.co/v1",
+		Kind:       "RuleSet",
+		Meta:       &bundlev1.RuleSetMeta{},
+		Spec: &bundlev1.RuleSetSpec{
+			Rules: []*bundlev1.Rule{
+				{
+					Name:     "HARP-OWN-0001",
+					Path:     "app/**",
+					RegoFile: policyFile,
+					RegoData: []string{filepath.Dir(dataFile)},
+				},
+			},
+		},
+	}
+
+	specChecksum, err := Checksum(spec)
+	require.NoError(t, err)
+	first, err := PolicyChecksum(spec)
+	require.NoError(t, err)
+	assert.NotEqual(t, specChecksum, first)
+
+	// Stable
+	second, err := PolicyChecksum(spec)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	// Data document update
+	require.NoError(t, os.WriteFile(dataFile, []byte(`{"owners":{}}`), 0o600))
+	updated, err := PolicyChecksum(spec)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, updated)
+
+	// Specification checksum doesn't cover policy content
+	unchanged, err := Checksum(spec)
+	require.NoError(t, err)
+	assert.Equal(t, specChecksum, unchanged)
+
+	// Missing policy file
+	require.NoError(t, os.Remove(policyFile))
+	_, err = PolicyChecksum(spec)
+	assert.Error(t, err)
+}
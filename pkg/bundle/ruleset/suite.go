@@ -0,0 +1,498 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+const (
+	// OutcomePass expects the rule to be validated.
+	OutcomePass = "pass"
+	// OutcomeFail expects the rule to raise a violation.
+	OutcomeFail = "fail"
+)
+
+// SuiteReport holds a ruleset test suite execution result.
+type SuiteReport struct {
+	Name    string
+	RuleSet string
+	Cases   []*SuiteCaseResult
+	// Rules lists all rule names declared by the ruleset.
+	Rules []string
+	// Covered lists rule names asserted by at least one expectation.
+	Covered []string
+	Elapsed time.Duration
+}
+
+// SuiteCaseResult holds a test case execution result.
+type SuiteCaseResult struct {
+	Name     string
+	Failures []string
+	Elapsed  time.Duration
+}
+
+// Passed returns true if the test case has no failure.
+func (r *SuiteCaseResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Passed returns true if all test cases passed.
+func (r *SuiteReport) Passed() bool {
+	for _, c := range r.Cases {
+		if !c.Passed() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Coverage returns the ratio of rules asserted by test expectations.
+func (r *SuiteReport) Coverage() float64 {
+	if len(r.Rules) == 0 {
+		return 0
+	}
+
+	return float64(len(r.Covered)) / float64(len(r.Rules))
+}
+
+// Uncovered returns rule names not asserted by any test expectation.
+func (r *SuiteReport) Uncovered() []string {
+	covered := map[string]struct{}{}
+	for _, name := range r.Covered {
+		covered[name] = struct{}{}
+	}
+
+	res := []string{}
+	for _, name := range r.Rules {
+		if _, ok := covered[name]; !ok {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+// ValidateSuite checks the ruleset test specification.
+func ValidateSuite(spec *bundlev1.RuleSetTest) error {
+	// Check if spec is nil
+	if spec == nil {
+		return errors.New("unable to validate ruleset test: test is nil")
+	}
+
+	if spec.ApiVersion != "harp.elastic.co/v1" {
+		return errors.New("apiVersion should be 'harp.elastic.co/v1'")
+	}
+
+	if spec.Kind != "RuleSetTest" {
+		return errors.New("kind should be 'RuleSetTest'")
+	}
+
+	if spec.Meta == nil || spec.Meta.Name == "" {
+		return errors.New("meta.name must not be blank")
+	}
+
+	if spec.Spec == nil {
+		return errors.New("spec should not be 'nil'")
+	}
+
+	if spec.Spec.RuleSet == "" {
+		return errors.New("spec.ruleSet must not be blank")
+	}
+
+	// Validate test cases
+	for i, c := range spec.Spec.Cases {
+		if c == nil {
+			return fmt.Errorf("test case #%d is nil", i)
+		}
+		if c.Name == "" {
+			return fmt.Errorf("test case #%d must declare a name", i)
+		}
+		if c.Input == nil {
+			return fmt.Errorf("test case '%s' must declare an input", c.Name)
+		}
+		if len(c.Expect) == 0 {
+			return fmt.Errorf("test case '%s' must declare at least one expectation", c.Name)
+		}
+		for _, e := range c.Expect {
+			if e == nil || e.Rule == "" {
+				return fmt.Errorf("test case '%s' has an expectation without rule", c.Name)
+			}
+			switch e.Outcome {
+			case OutcomePass:
+				if len(e.Messages) > 0 {
+					return fmt.Errorf("test case '%s' expects messages for a passing rule '%s'", c.Name, e.Rule)
+				}
+			case OutcomeFail:
+			default:
+				return fmt.Errorf("test case '%s' has an invalid outcome '%s' for rule '%s'", c.Name, e.Outcome, e.Rule)
+			}
+		}
+	}
+
+	// No error
+	return nil
+}
+
+// RunSuite executes all test cases of the given specification against the
+// ruleset. The base directory is used to resolve input bundle files.
+func RunSuite(ctx context.Context, spec *bundlev1.RuleSetTest, rs *bundlev1.RuleSet, baseDir string) (*SuiteReport, error) {
+	// Validate specifications
+	if err := ValidateSuite(spec); err != nil {
+		return nil, fmt.Errorf("unable to validate test spec: %w", err)
+	}
+	if err := Validate(rs); err != nil {
+		return nil, fmt.Errorf("unable to validate ruleset: %w", err)
+	}
+
+	start := time.Now()
+	report := &SuiteReport{
+		Name:    spec.Meta.Name,
+		RuleSet: rs.Meta.Name,
+		Cases:   make([]*SuiteCaseResult, 0, len(spec.Spec.Cases)),
+		Rules:   make([]string, 0, len(rs.Spec.Rules)),
+		Covered: []string{},
+	}
+
+	// Index rules
+	rules := map[string]*bundlev1.Rule{}
+	for _, r := range rs.Spec.Rules {
+		rules[r.Name] = r
+		report.Rules = append(report.Rules, r.Name)
+	}
+
+	covered := map[string]struct{}{}
+	for _, c := range spec.Spec.Cases {
+		res, err := runSuiteCase(ctx, c, rs, rules, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run test case '%s': %w", c.Name, err)
+		}
+		report.Cases = append(report.Cases, res)
+
+		// Update coverage
+		for _, e := range c.Expect {
+			covered[e.Rule] = struct{}{}
+		}
+	}
+
+	// Keep ruleset order
+	for _, name := range report.Rules {
+		if _, ok := covered[name]; ok {
+			report.Covered = append(report.Covered, name)
+		}
+	}
+	report.Elapsed = time.Since(start)
+
+	// No error
+	return report, nil
+}
+
+// WriteSuiteReport renders the test report using `go test` output format.
+// Passing test cases are only displayed in verbose mode.
+func WriteSuiteReport(w io.Writer, r *SuiteReport, source string, verbose bool) error {
+	// Check arguments
+	if w == nil {
+		return errors.New("unable to write to nil writer")
+	}
+	if r == nil {
+		return errors.New("unable to write nil report")
+	}
+
+	var sb strings.Builder
+	for _, c := range r.Cases {
+		name := fmt.Sprintf("%s/%s", r.Name, c.Name)
+		if verbose {
+			fmt.Fprintf(&sb, "=== RUN   %s\n", name)
+		}
+		switch {
+		case !c.Passed():
+			fmt.Fprintf(&sb, "--- FAIL: %s (%.2fs)\n", name, c.Elapsed.Seconds())
+			for _, f := range c.Failures {
+				fmt.Fprintf(&sb, "    %s\n", f)
+			}
+		case verbose:
+			fmt.Fprintf(&sb, "--- PASS: %s (%.2fs)\n", name, c.Elapsed.Seconds())
+		}
+	}
+
+	// Summary
+	status := "ok  "
+	if !r.Passed() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(&sb, "%s\t%s\t%.3fs\tcoverage: %.1f%% of rules (%d/%d)\n", status, source, r.Elapsed.Seconds(), r.Coverage()*100, len(r.Covered), len(r.Rules))
+	if uncovered := r.Uncovered(); verbose && len(uncovered) > 0 {
+		fmt.Fprintf(&sb, "    uncovered rules: %s\n", strings.Join(uncovered, ", "))
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("unable to write test report: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func runSuiteCase(ctx context.Context, c *bundlev1.RuleSetTestCase, rs *bundlev1.RuleSet, rules map[string]*bundlev1.Rule, baseDir string) (*SuiteCaseResult, error) {
+	start := time.Now()
+
+	// Build the input bundle
+	b, err := suiteInput(c.Input, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Select rules under test
+	subset := &bundlev1.RuleSet{
+		ApiVersion: rs.ApiVersion,
+		Kind:       rs.Kind,
+		Meta:       rs.Meta,
+		Spec:       &bundlev1.RuleSetSpec{},
+	}
+	if len(c.Rules) == 0 {
+		subset.Spec.Rules = rs.Spec.Rules
+	}
+	for _, name := range c.Rules {
+		r, ok := rules[name]
+		if !ok {
+			return nil, fmt.Errorf("rule '%s' is not declared by the ruleset", name)
+		}
+		subset.Spec.Rules = append(subset.Spec.Rules, r)
+	}
+
+	// Evaluate all rules
+	report, err := EvaluateAll(ctx, b, subset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SuiteCaseResult{
+		Name:     c.Name,
+		Failures: []string{},
+	}
+
+	// Check expectations
+	for _, e := range c.Expect {
+		failure, err := checkExpectation(e, subset, report, b)
+		if err != nil {
+			return nil, err
+		}
+		if failure != "" {
+			res.Failures = append(res.Failures, failure)
+		}
+	}
+	res.Elapsed = time.Since(start)
+
+	// No error
+	return res, nil
+}
+
+func checkExpectation(e *bundlev1.RuleSetTestExpectation, rs *bundlev1.RuleSet, report *Report, b *bundlev1.Bundle) (string, error) {
+	// Retrieve the rule evaluation result
+	var (
+		rule   *bundlev1.Rule
+		result *RuleResult
+	)
+	for i, r := range rs.Spec.Rules {
+		if r.Name == e.Rule {
+			rule, result = r, report.Rules[i]
+			break
+		}
+	}
+
+	subject := fmt.Sprintf("rule '%s'", e.Rule)
+	if e.Package != "" {
+		subject = fmt.Sprintf("rule '%s' on package '%s'", e.Rule, e.Package)
+	}
+	if rule == nil {
+		return fmt.Sprintf("%s: rule is not evaluated by the test case", subject), nil
+	}
+
+	// Check that the package is evaluated by the rule
+	if e.Package != "" {
+		if ruleScope(rule) == ScopeBundle {
+			return fmt.Sprintf("%s: bundle scoped rules don't evaluate packages", subject), nil
+		}
+
+		pathMatcher, err := glob.Compile(rule.Path)
+		if err != nil {
+			return "", fmt.Errorf("unable to compile path matcher: %w", err)
+		}
+
+		found := false
+		for _, p := range b.Packages {
+			if p != nil && p.Name == e.Package && pathMatcher.Match(p.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s: package is not evaluated by the rule", subject), nil
+		}
+	}
+
+	// Collect non exempted violations
+	violations := []string{}
+	for _, v := range result.Violations {
+		if v.Exempted {
+			continue
+		}
+		if e.Package != "" && v.Package != e.Package {
+			continue
+		}
+		violations = append(violations, v.String())
+	}
+
+	switch e.Outcome {
+	case OutcomePass:
+		if len(violations) > 0 {
+			return fmt.Sprintf("%s: expected pass, got fail: %s", subject, strings.Join(violations, "; ")), nil
+		}
+	case OutcomeFail:
+		if len(violations) == 0 {
+			return fmt.Sprintf("%s: expected fail, got pass", subject), nil
+		}
+
+		// Check expected messages
+		missing := []string{}
+		for _, msg := range e.Messages {
+			matched := false
+			for _, v := range violations {
+				if strings.Contains(v, msg) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				missing = append(missing, fmt.Sprintf("%q", msg))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("%s: expected messages %s not found in: %s", subject, strings.Join(missing, ", "), strings.Join(violations, "; ")), nil
+		}
+	}
+
+	// No failure
+	return "", nil
+}
+
+func suiteInput(in *bundlev1.RuleSetTestInput, baseDir string) (*bundlev1.Bundle, error) {
+	b := &bundlev1.Bundle{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+		Packages:    []*bundlev1.Package{},
+	}
+
+	// Load the bundle file
+	if in.BundleFile != "" {
+		var err error
+		if b, err = loadSuiteBundle(filepath.Join(baseDir, in.BundleFile)); err != nil {
+			return nil, err
+		}
+		if b.Labels == nil {
+			b.Labels = map[string]string{}
+		}
+		if b.Annotations == nil {
+			b.Annotations = map[string]string{}
+		}
+	}
+
+	// Merge bundle metadata
+	for k, v := range in.Labels {
+		b.Labels[k] = v
+	}
+	for k, v := range in.Annotations {
+		b.Annotations[k] = v
+	}
+
+	// Append inline packages
+	for _, ip := range in.Packages {
+		if ip == nil || ip.Name == "" {
+			return nil, errors.New("inline package must declare a name")
+		}
+
+		p := &bundlev1.Package{
+			Name:        ip.Name,
+			Labels:      ip.Labels,
+			Annotations: ip.Annotations,
+			Secrets: &bundlev1.SecretChain{
+				Data: []*bundlev1.KV{},
+			},
+		}
+		for _, key := range sortedMapKeys(ip.Secrets) {
+			packed, err := secret.Pack(ip.Secrets[key])
+			if err != nil {
+				return nil, fmt.Errorf("unable to pack secret value for `%s.%s`: %w", ip.Name, key, err)
+			}
+			p.Secrets.Data = append(p.Secrets.Data, &bundlev1.KV{
+				Key:   key,
+				Type:  "string",
+				Value: packed,
+			})
+		}
+
+		b.Packages = append(b.Packages, p)
+	}
+
+	// No error
+	return b, nil
+}
+
+func loadSuiteBundle(path string) (*bundlev1.Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bundle file: %w", err)
+	}
+	defer f.Close()
+
+	// JSON dump
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		b, err := bundle.FromDump(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load bundle dump '%s': %w", path, err)
+		}
+		return b, nil
+	}
+
+	// Bundle container
+	b, err := bundle.FromContainerReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load bundle container '%s': %w", path, err)
+	}
+
+	// No error
+	return b, nil
+}
@@ -0,0 +1,198 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+)
+
+const testRuleSetTest = `apiVersion: harp.elastic.co/v1
+kind: RuleSetTest
+meta:
+  name: harp-server
+spec:
+  ruleSet: multiple-rules.yaml
+  cases:
+    - name: wrong-expectations
+      rules:
+        - HARP-SRV-0001
+      input:
+        packages:
+          - name: invalid/path
+          - name: app/production/security/harp/v1.0.0/server/database/credentials
+      expect:
+        - rule: HARP-SRV-0001
+          package: invalid/path
+          outcome: pass
+        - rule: HARP-SRV-0001
+          package: app/production/security/harp/v1.0.0/server/database/credentials
+          outcome: fail
+        - rule: HARP-SRV-0001
+          package: unknown/package
+          outcome: pass
+        - rule: HARP-SRV-0002
+          outcome: pass
+`
+
+func mustLoadTestSuite(t *testing.T, path string) (*bundlev1.RuleSetTest, *bundlev1.RuleSet) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	spec, err := SuiteYAML(f)
+	assert.NoError(t, err)
+
+	rsf, err := os.Open(filepath.Join(filepath.Dir(path), spec.Spec.RuleSet))
+	assert.NoError(t, err)
+	defer rsf.Close()
+
+	rs, err := YAML(rsf)
+	assert.NoError(t, err)
+
+	return spec, rs
+}
+
+func TestValidateSuite(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid kind",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSet\nmeta:\n  name: test\nspec:\n  ruleSet: rs.yaml\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing ruleset",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSetTest\nmeta:\n  name: test\nspec:\n  cases: []\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing input",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSetTest\nmeta:\n  name: test\nspec:\n  ruleSet: rs.yaml\n  cases:\n  - name: c1\n    expect:\n    - rule: R1\n      outcome: pass\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid outcome",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSetTest\nmeta:\n  name: test\nspec:\n  ruleSet: rs.yaml\n  cases:\n  - name: c1\n    input: {}\n    expect:\n    - rule: R1\n      outcome: error\n",
+			wantErr: true,
+		},
+		{
+			name:    "messages on passing rule",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSetTest\nmeta:\n  name: test\nspec:\n  ruleSet: rs.yaml\n  cases:\n  - name: c1\n    input: {}\n    expect:\n    - rule: R1\n      outcome: pass\n      messages: [foo]\n",
+			wantErr: true,
+		},
+		{
+			name:    "valid",
+			spec:    "apiVersion: harp.elastic.co/v1\nkind: RuleSetTest\nmeta:\n  name: test\nspec:\n  ruleSet: rs.yaml\n  cases:\n  - name: c1\n    input: {}\n    expect:\n    - rule: R1\n      outcome: fail\n      messages: [foo]\n",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SuiteYAML(strings.NewReader(tt.spec))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SuiteYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunSuite(t *testing.T) {
+	t.Run("fixtures", func(t *testing.T) {
+		for _, path := range []string{
+			"../../../test/fixtures/ruleset/tests/harp-server_test.yaml",
+			"../../../test/fixtures/ruleset/tests/harp-bundle_test.yaml",
+		} {
+			f, err := os.Open(path)
+			assert.NoError(t, err)
+			validationErrors, err := LintSuite(f)
+			assert.NoError(t, err)
+			assert.Empty(t, validationErrors)
+			f.Close()
+
+			spec, rs := mustLoadTestSuite(t, path)
+
+			report, err := RunSuite(context.Background(), spec, rs, filepath.Dir(path))
+			assert.NoError(t, err)
+			for _, c := range report.Cases {
+				assert.Empty(t, c.Failures, c.Name)
+			}
+			assert.True(t, report.Passed())
+		}
+	})
+
+	t.Run("coverage", func(t *testing.T) {
+		spec, rs := mustLoadTestSuite(t, "../../../test/fixtures/ruleset/tests/harp-server_test.yaml")
+
+		report, err := RunSuite(context.Background(), spec, rs, "../../../test/fixtures/ruleset/tests")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"HARP-SRV-0001", "HARP-SRV-0002"}, report.Covered)
+		assert.Equal(t, []string{"HARP-SRV-0003"}, report.Uncovered())
+		assert.InDelta(t, 2.0/3.0, report.Coverage(), 0.001)
+
+		out := &bytes.Buffer{}
+		assert.NoError(t, WriteSuiteReport(out, report, "harp-server_test.yaml", true))
+		assert.Contains(t, out.String(), "=== RUN   harp-server/non-cso-package\n--- PASS: harp-server/non-cso-package")
+		assert.Contains(t, out.String(), "coverage: 66.7% of rules (2/3)")
+		assert.Contains(t, out.String(), "uncovered rules: HARP-SRV-0003")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		spec, err := SuiteYAML(strings.NewReader(testRuleSetTest))
+		assert.NoError(t, err)
+		_, rs := mustLoadTestSuite(t, "../../../test/fixtures/ruleset/tests/harp-server_test.yaml")
+
+		report, err := RunSuite(context.Background(), spec, rs, ".")
+		assert.NoError(t, err)
+		assert.False(t, report.Passed())
+		assert.Len(t, report.Cases, 1)
+		assert.Equal(t, []string{
+			"rule 'HARP-SRV-0001' on package 'invalid/path': expected pass, got fail: package 'invalid/path' doesn't validate rule 'HARP-SRV-0001' (p.is_cso_compliant())",
+			"rule 'HARP-SRV-0001' on package 'app/production/security/harp/v1.0.0/server/database/credentials': expected fail, got pass",
+			"rule 'HARP-SRV-0001' on package 'unknown/package': package is not evaluated by the rule",
+			"rule 'HARP-SRV-0002': rule is not evaluated by the test case",
+		}, report.Cases[0].Failures)
+
+		out := &bytes.Buffer{}
+		assert.NoError(t, WriteSuiteReport(out, report, "inline_test.yaml", false))
+		assert.True(t, strings.HasPrefix(out.String(), "--- FAIL: harp-server/wrong-expectations"))
+		assert.Contains(t, out.String(), "FAIL\tinline_test.yaml\t")
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		spec, err := SuiteYAML(strings.NewReader(strings.Replace(testRuleSetTest, "- HARP-SRV-0001\n      input", "- HARP-SRV-9999\n      input", 1)))
+		assert.NoError(t, err)
+		_, rs := mustLoadTestSuite(t, "../../../test/fixtures/ruleset/tests/harp-server_test.yaml")
+
+		_, err = RunSuite(context.Background(), spec, rs, ".")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,152 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/template"
+	"github.com/elastic/harp/pkg/bundle/template/visitor/secretbuilder"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+var ruleNameSanitizer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// FromTemplate generates a linter ruleset from a BundleTemplate. One rule is
+// generated for each secret suffix, it requires the CSO path built by the
+// secret builder, the secret keys declared by the suffix template and the
+// declared labels and annotations. Rules are named from the secret path, and
+// secret values are neither generated nor looked up.
+func FromTemplate(spec *bundlev1.Template, templateContext engine.Context) (*bundlev1.RuleSet, error) {
+	// Check arguments
+	if spec == nil {
+		return nil, errors.New("unable to process nil template")
+	}
+	if spec.Meta == nil {
+		return nil, errors.New("unable to process template without metadata")
+	}
+
+	// Default rendering context
+	if types.IsNil(templateContext) {
+		templateContext = engine.NewContext()
+	}
+
+	// Validate values
+	if err := template.ValidateValues(spec, templateContext.Values()); err != nil {
+		return nil, fmt.Errorf("unable to validate template values: %w", err)
+	}
+
+	// Resolve package paths and secret keys without generating secret values
+	b := &bundlev1.Bundle{}
+	if err := template.Execute(spec, secretbuilder.Declare(b, templateContext)); err != nil {
+		return nil, fmt.Errorf("unable to execute bundle template: %w", err)
+	}
+	if len(b.Packages) == 0 {
+		return nil, errors.New("unable to generate rule from a template without secrets")
+	}
+
+	// Create ruleset
+	rs := &bundlev1.RuleSet{
+		ApiVersion: "harp.elastic.co/v1",
+		Kind:       "RuleSet",
+		Meta: &bundlev1.RuleSetMeta{
+			Name:        spec.Meta.Name,
+			Owner:       spec.Meta.Owner,
+			Description: fmt.Sprintf("Generated from '%s' bundle template", spec.Meta.Name),
+		},
+		Spec: &bundlev1.RuleSetSpec{
+			Rules: []*bundlev1.Rule{},
+		},
+	}
+
+	// Iterate over declared packages
+	names := map[string]int{}
+	for _, p := range b.Packages {
+		// Rule name is derived from the secret path
+		name := ruleName(p.Name)
+		names[name]++
+		if count := names[name]; count > 1 {
+			name = fmt.Sprintf("%s-%d", name, count)
+		}
+
+		// Prepare a rule
+		r := &bundlev1.Rule{
+			Name:        name,
+			Path:        p.Name,
+			Constraints: []string{},
+		}
+		if p.Secrets != nil && p.Secrets.Annotations != nil {
+			r.Description = p.Secrets.Annotations["description"]
+		}
+
+		// Declared labels
+		for _, label := range sortedMapKeys(p.Labels) {
+			r.Constraints = append(r.Constraints, fmt.Sprintf(`p.match_label(%q)`, label))
+		}
+
+		// Declared annotations
+		for _, annotation := range sortedMapKeys(p.Annotations) {
+			r.Constraints = append(r.Constraints, fmt.Sprintf(`p.match_annotation(%q)`, annotation))
+		}
+
+		// Secret keys declared by the suffix template
+		if p.Secrets != nil {
+			keys := make([]string, 0, len(p.Secrets.Data))
+			for _, kv := range p.Secrets.Data {
+				keys = append(keys, kv.Key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				r.Constraints = append(r.Constraints, fmt.Sprintf(`p.has_secret(%q)`, key))
+			}
+		}
+
+		// Add the rule
+		rs.Spec.Rules = append(rs.Spec.Rules, r)
+	}
+
+	// No error
+	return rs, nil
+}
+
+// ruleName returns a rule name derived from the given secret path.
+func ruleName(secretPath string) string {
+	name := strings.Trim(ruleNameSanitizer.ReplaceAllString(strings.ToUpper(secretPath), "-"), "-")
+	if name == "" {
+		name = "SECRET"
+	}
+
+	return fmt.Sprintf("LINT-%s", name)
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
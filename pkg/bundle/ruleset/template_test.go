@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/template"
+	"github.com/elastic/harp/pkg/bundle/template/visitor/secretbuilder"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+const testBundleTemplate = `apiVersion: harp.elastic.co/v1
+kind: BundleTemplate
+meta:
+  name: "Harp Server"
+  owner: security@elastic.co
+  description: "Harp server secrets"
+spec:
+  selector:
+    quality: "{{ .Values.quality }}"
+    platform: "security"
+    product: "harp"
+    version: "v1.0.0"
+  namespaces:
+    application:
+    - name: "server"
+      description: "Harp server"
+      secrets:
+      - suffix: "database/credentials"
+        description: "Database connection settings"
+        labels:
+          database: "postgresql"
+        annotations:
+          infosec.elastic.co/v1/SecretPolicy#severity: high
+        template: |-
+          {
+            "DB_HOST": "db.{{ .Values.quality }}.internal",
+            "DB_PASSWORD": "{{ paranoidPassword | b64enc }}",
+            {{ if eq .Values.quality "production" }}"DB_REPLICA_PASSWORD": {{ paranoidPassword | toJson }},{{ end }}
+            "DB_ROOT_PASSWORD": "{{ with secret "infra/database/root" }}{{ .password }}{{ end }}"
+          }
+      - suffix: "http/session"
+        description: "Session encryption key"
+        content:
+          session.key: "{{ cryptoKey \"aes:256\" }}"
+`
+
+func mustLoadTemplate(t *testing.T) *bundlev1.Template {
+	t.Helper()
+	spec, err := template.YAML(strings.NewReader(testBundleTemplate))
+	assert.NoError(t, err)
+	return spec
+}
+
+func TestFromTemplate(t *testing.T) {
+	templateContext := engine.NewContext(engine.WithValues(map[string]interface{}{
+		"quality": "production",
+	}))
+
+	t.Run("nil", func(t *testing.T) {
+		_, err := FromTemplate(nil, templateContext)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing values", func(t *testing.T) {
+		_, err := FromTemplate(mustLoadTemplate(t), engine.NewContext(engine.WithStrictMode(true)))
+		assert.Error(t, err)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		rs, err := FromTemplate(mustLoadTemplate(t), templateContext)
+		assert.NoError(t, err)
+		assert.NoError(t, Validate(rs))
+		assert.Equal(t, "Harp Server", rs.Meta.Name)
+		assert.Equal(t, []*bundlev1.Rule{
+			{
+				Name:        "LINT-APP-PRODUCTION-SECURITY-HARP-V1-0-0-SERVER-DATABASE-CREDENTIALS",
+				Description: "Database connection settings",
+				Path:        "app/production/security/harp/v1.0.0/server/database/credentials",
+				Constraints: []string{
+					`p.match_label("database")`,
+					`p.match_annotation("infosec.elastic.co/v1/SecretPolicy#severity")`,
+					`p.has_secret("DB_HOST")`,
+					`p.has_secret("DB_PASSWORD")`,
+					`p.has_secret("DB_REPLICA_PASSWORD")`,
+					`p.has_secret("DB_ROOT_PASSWORD")`,
+				},
+			},
+			{
+				Name:        "LINT-APP-PRODUCTION-SECURITY-HARP-V1-0-0-SERVER-HTTP-SESSION",
+				Description: "Session encryption key",
+				Path:        "app/production/security/harp/v1.0.0/server/http/session",
+				Constraints: []string{
+					`p.has_secret("session.key")`,
+				},
+			},
+		}, rs.Spec.Rules)
+
+		// Bundles generated from the template follow the same contract
+		generationContext := engine.NewContext(
+			engine.WithValues(templateContext.Values()),
+			engine.WithSecretReaders(func(path string) (map[string]interface{}, error) {
+				return map[string]interface{}{"password": "root"}, nil
+			}),
+		)
+		b := &bundlev1.Bundle{}
+		assert.NoError(t, template.Execute(mustLoadTemplate(t), secretbuilder.New(b, generationContext)))
+		assert.NoError(t, Evaluate(context.Background(), b, rs))
+
+		// Imported bundles missing a secret are rejected
+		b.Packages[0].Secrets.Data = b.Packages[0].Secrets.Data[:1]
+		assert.Error(t, Evaluate(context.Background(), b, rs))
+	})
+}
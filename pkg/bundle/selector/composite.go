@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selector
+
+// AllOf returns a specification satisfied when all given specifications are
+// satisfied.
+func AllOf(specs ...Specification) Specification {
+	return &allOf{
+		specs: specs,
+	}
+}
+
+// AnyOf returns a specification satisfied when at least one of the given
+// specifications is satisfied.
+func AnyOf(specs ...Specification) Specification {
+	return &anyOf{
+		specs: specs,
+	}
+}
+
+// Not returns a specification satisfied when the given specification is not.
+func Not(spec Specification) Specification {
+	return &not{
+		spec: spec,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type allOf struct {
+	specs []Specification
+}
+
+// IsSatisfiedBy returns specification satisfaction status
+func (s *allOf) IsSatisfiedBy(object interface{}) bool {
+	if len(s.specs) == 0 {
+		return false
+	}
+
+	for _, spec := range s.specs {
+		if spec == nil || !spec.IsSatisfiedBy(object) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type anyOf struct {
+	specs []Specification
+}
+
+// IsSatisfiedBy returns specification satisfaction status
+func (s *anyOf) IsSatisfiedBy(object interface{}) bool {
+	for _, spec := range s.specs {
+		if spec != nil && spec.IsSatisfiedBy(object) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type not struct {
+	spec Specification
+}
+
+// IsSatisfiedBy returns specification satisfaction status
+func (s *not) IsSatisfiedBy(object interface{}) bool {
+	if s.spec == nil {
+		return false
+	}
+
+	return !s.spec.IsSatisfiedBy(object)
+}
@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selector
+
+import (
+	"testing"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+)
+
+func TestComposite_IsSatisfiedBy(t *testing.T) {
+	var (
+		production = MatchPathStrict("app/production/db")
+		staging    = MatchPathStrict("app/staging/db")
+		pkg        = &bundlev1.Package{Name: "app/production/db"}
+	)
+
+	tests := []struct {
+		name   string
+		spec   Specification
+		object interface{}
+		want   bool
+	}{
+		{
+			name:   "allOf - empty",
+			spec:   AllOf(),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "allOf - nil",
+			spec:   AllOf(production, nil),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "allOf - satisfied",
+			spec:   AllOf(production, Not(staging)),
+			object: pkg,
+			want:   true,
+		},
+		{
+			name:   "allOf - not satisfied",
+			spec:   AllOf(production, staging),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "anyOf - empty",
+			spec:   AnyOf(),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "anyOf - satisfied",
+			spec:   AnyOf(nil, staging, production),
+			object: pkg,
+			want:   true,
+		},
+		{
+			name:   "anyOf - not satisfied",
+			spec:   AnyOf(staging),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "not - nil",
+			spec:   Not(nil),
+			object: pkg,
+			want:   false,
+		},
+		{
+			name:   "not - satisfied",
+			spec:   Not(staging),
+			object: pkg,
+			want:   true,
+		},
+		{
+			name:   "nested",
+			spec:   Not(AnyOf(staging, AllOf(production, Not(staging)))),
+			object: pkg,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.IsSatisfiedBy(tt.object); got != tt.want {
+				t.Errorf("IsSatisfiedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
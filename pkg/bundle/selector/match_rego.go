@@ -22,35 +22,93 @@ import (
 	"errors"
 	"fmt"
 
+	//nolint:staticcheck // deprecated package requires refactor
+	"github.com/open-policy-agent/opa/ast"
 	//nolint:staticcheck // deprecated package requires refactor
 	"github.com/open-policy-agent/opa/rego"
 	"go.uber.org/zap"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/internal/policy"
 	"github.com/elastic/harp/pkg/sdk/log"
 )
 
-// MatchRego returns a Rego package matcher specification.
-func MatchRego(ctx context.Context, policy string) (Specification, error) {
+// MatchRego returns a Rego package matcher specification. The policy can be
+// blank when policy paths are provided.
+func MatchRego(ctx context.Context, policyContent string, opts ...RegoOptionFunc) (Specification, error) {
+	// Default options
+	dopts := &regoOptions{
+		query: "data.harp.matched",
+	}
+	for _, o := range opts {
+		o(dopts)
+	}
+
 	// Prepare query filter
-	query, err := rego.New(
-		rego.Query("data.harp.matched"),
-		rego.Module("harp.rego", policy),
-	).PrepareForEval(ctx)
+	query, err := policy.Prepare(ctx, &policy.Source{
+		Query:  dopts.query,
+		Module: policyContent,
+		Paths:  dopts.paths,
+		Data:   dopts.data,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("unable to prepare for eval: %w", err)
+		return nil, err
 	}
 
 	// Wrap as a builder
 	return &regoMatcher{
-		ctx:   ctx,
-		query: query,
+		ctx:    ctx,
+		query:  query,
+		bundle: dopts.bundle,
 	}, nil
 }
 
+type regoOptions struct {
+	query  string
+	paths  []string
+	data   []string
+	bundle *bundlev1.Bundle
+}
+
+// RegoOptionFunc is used to customize the Rego matcher.
+type RegoOptionFunc func(o *regoOptions)
+
+// WithRegoQuery sets the boolean query evaluated by the policy.
+func WithRegoQuery(value string) RegoOptionFunc {
+	return func(o *regoOptions) {
+		o.query = value
+	}
+}
+
+// WithRegoPaths adds policy files, directories or OPA bundle archives.
+func WithRegoPaths(values ...string) RegoOptionFunc {
+	return func(o *regoOptions) {
+		o.paths = append(o.paths, values...)
+	}
+}
+
+// WithRegoData adds JSON or YAML data document files exposed as `data`.
+func WithRegoData(values ...string) RegoOptionFunc {
+	return func(o *regoOptions) {
+		o.data = append(o.data, values...)
+	}
+}
+
+// WithRegoBundle exposes the given bundle as `input.bundle` during package
+// evaluation.
+func WithRegoBundle(b *bundlev1.Bundle) RegoOptionFunc {
+	return func(o *regoOptions) {
+		o.bundle = b
+	}
+}
+
 type regoMatcher struct {
-	ctx   context.Context
-	query rego.PreparedEvalQuery
+	ctx    context.Context
+	query  rego.PreparedEvalQuery
+	bundle *bundlev1.Bundle
+
+	// Bundle input cache
+	bundleValue ast.Value
 }
 
 // IsSatisfiedBy returns specification satisfaction status
@@ -72,9 +130,30 @@ func (s *regoMatcher) IsSatisfiedBy(object interface{}) bool {
 
 // -----------------------------------------------------------------------------
 
-func (s *regoMatcher) regoEvaluate(ctx context.Context, query rego.PreparedEvalQuery, input interface{}) (bool, error) {
+func (s *regoMatcher) regoEvaluate(ctx context.Context, query rego.PreparedEvalQuery, p *bundlev1.Package) (bool, error) {
+	// Convert the package as input
+	input, err := ast.InterfaceToValue(p)
+	if err != nil {
+		return false, fmt.Errorf("unable to prepare package input: %w", err)
+	}
+
+	// Expose bundle context
+	if s.bundle != nil {
+		obj, ok := input.(ast.Object)
+		if !ok {
+			return false, fmt.Errorf("unexpected package input type %T", input)
+		}
+
+		if s.bundleValue == nil {
+			if s.bundleValue, err = ast.InterfaceToValue(s.bundle); err != nil {
+				return false, fmt.Errorf("unable to prepare bundle input: %w", err)
+			}
+		}
+		obj.Insert(ast.StringTerm("bundle"), ast.NewTerm(s.bundleValue))
+	}
+
 	// Evaluate the package with the policy
-	results, err := query.Eval(ctx, rego.EvalInput(input))
+	results, err := query.Eval(ctx, rego.EvalParsedInput(input))
 	if err != nil {
 		return false, fmt.Errorf("unable to evaluate the policy: %w", err)
 	} else if len(results) == 0 {
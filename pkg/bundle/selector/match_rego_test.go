@@ -27,6 +27,7 @@ import (
 func Test_matchRego_IsSatisfiedBy(t *testing.T) {
 	type fields struct {
 		policy string
+		opts   []RegoOptionFunc
 	}
 	type args struct {
 		object interface{}
@@ -93,10 +94,66 @@ func Test_matchRego_IsSatisfiedBy(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "custom query with data document",
+			fields: fields{
+				policy: "package harp.selector\ndefault owned = false\nowned { data.owners[input.labels.owner] }",
+				opts: []RegoOptionFunc{
+					WithRegoQuery("data.harp.selector.owned"),
+					WithRegoData("../../../test/fixtures/ruleset/valid/owners.json"),
+				},
+			},
+			args: args{
+				object: &bundlev1.Package{
+					Name: "foo",
+					Labels: map[string]string{
+						"owner": "security",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "bundle context",
+			fields: fields{
+				policy: "package harp\ndefault matched = false\nmatched { input.bundle.labels.environment == \"production\" }",
+				opts: []RegoOptionFunc{
+					WithRegoBundle(&bundlev1.Bundle{
+						Labels: map[string]string{
+							"environment": "production",
+						},
+					}),
+				},
+			},
+			args: args{
+				object: &bundlev1.Package{
+					Name: "foo",
+				},
+			},
+			want: true,
+		},
+		{
+			name: "policy directory",
+			fields: fields{
+				opts: []RegoOptionFunc{
+					WithRegoQuery("data.harp.lib.has_owner(input)"),
+					WithRegoPaths("../../../test/fixtures/ruleset/valid/policies"),
+				},
+			},
+			args: args{
+				object: &bundlev1.Package{
+					Name: "foo",
+					Annotations: map[string]string{
+						"harp.elastic.co/v1/package#owner": "security",
+					},
+				},
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, err := MatchRego(context.Background(), tt.fields.policy)
+			s, err := MatchRego(context.Background(), tt.fields.policy, tt.fields.opts...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Error got %v, expected %v", err, tt.wantErr)
 				return
@@ -0,0 +1,326 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selector
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gobwas/glob"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/internal/inspect"
+	"github.com/elastic/harp/pkg/bundle/secret"
+	"github.com/elastic/harp/pkg/sdk/security"
+	"github.com/elastic/harp/pkg/sdk/types"
+)
+
+// Supported value types.
+var valueTypes = []string{"string", "bytes", "map", "list", "number", "bool"}
+
+// Supported value formats.
+var valueFormats = []string{
+	"pem",
+	inspect.FormatCertificate,
+	inspect.FormatPrivateKey,
+	inspect.FormatPublicKey,
+	inspect.FormatJWK,
+	inspect.FormatJWT,
+	inspect.FormatJSON,
+	inspect.FormatURL,
+	inspect.FormatUUID,
+	inspect.FormatEmail,
+	inspect.FormatBase64,
+}
+
+// Supported key types.
+var valueKeyTypes = []string{"rsa", "ec", "ed25519"}
+
+// MatchValue returns a secret value matcher specification. A package is
+// matched when one of its secrets satisfies all the given criteria.
+func MatchValue(opts ...ValueOptionFunc) (Specification, error) {
+	// Default options
+	dopts := &valueOptions{}
+	for _, o := range opts {
+		o(dopts)
+	}
+
+	// Check options
+	if dopts.valueType != "" && !types.StringArray(valueTypes).Contains(dopts.valueType) {
+		return nil, fmt.Errorf("unsupported value type '%s'", dopts.valueType)
+	}
+	if dopts.format != "" && !types.StringArray(valueFormats).Contains(dopts.format) {
+		return nil, fmt.Errorf("unsupported value format '%s'", dopts.format)
+	}
+	if dopts.keyType != "" && !types.StringArray(valueKeyTypes).Contains(dopts.keyType) {
+		return nil, fmt.Errorf("unsupported key type '%s'", dopts.keyType)
+	}
+	if dopts.maxSize > 0 && dopts.maxSize < dopts.minSize {
+		return nil, errors.New("maximum value size must be greater than minimum size")
+	}
+
+	m := &matchValue{
+		opts: dopts,
+	}
+
+	// Compile key matcher
+	if dopts.key != "" {
+		g, err := glob.Compile(dopts.key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile secret key glob '%s': %w", dopts.key, err)
+		}
+		m.key = g
+	}
+
+	// Compile value regex
+	if dopts.regex != "" {
+		re, err := regexp.Compile(dopts.regex)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile value regexp '%s': %w", dopts.regex, err)
+		}
+		m.regex = re
+	}
+
+	// Decode expected hash
+	if dopts.hashValue != "" {
+		if dopts.hashKey == "" {
+			return nil, errors.New("hash key must not be blank")
+		}
+		digest, err := hex.DecodeString(dopts.hashValue)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode expected hash value: %w", err)
+		}
+		if len(digest) != sha256.Size {
+			return nil, fmt.Errorf("invalid hash value length, expected %d bytes", sha256.Size)
+		}
+		m.digest = digest
+	}
+
+	// No error
+	return m, nil
+}
+
+type valueOptions struct {
+	key       string
+	valueType string
+	minSize   int
+	maxSize   int
+	regex     string
+	format    string
+	keyType   string
+	keyBits   int
+	hashKey   string
+	hashValue string
+}
+
+// ValueOptionFunc is used to customize the value matcher.
+type ValueOptionFunc func(o *valueOptions)
+
+// WithValueKey restricts inspected secrets to keys matching the glob pattern.
+func WithValueKey(pattern string) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.key = pattern
+	}
+}
+
+// WithValueType sets the expected decoded value type (string, bytes, map,
+// list, number, bool).
+func WithValueType(value string) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.valueType = strings.ToLower(value)
+	}
+}
+
+// WithValueSize sets the value size bounds, 0 disables the bound.
+func WithValueSize(minSize, maxSize int) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.minSize = minSize
+		o.maxSize = maxSize
+	}
+}
+
+// WithValueRegex sets the regex matched against string and bytes values.
+func WithValueRegex(value string) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.regex = value
+	}
+}
+
+// WithValueFormat sets the expected detected format.
+func WithValueFormat(value string) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.format = strings.ToLower(value)
+	}
+}
+
+// WithValueKeyType sets the expected key algorithm (rsa, ec, ed25519) and key
+// size in bits (0 for any size) of certificates, keys and JWK.
+func WithValueKeyType(keyType string, bits int) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.keyType = strings.ToLower(keyType)
+		o.keyBits = bits
+	}
+}
+
+// WithValueHash sets the expected hex encoded HMAC-SHA256 of the value
+// computed with the given key.
+func WithValueHash(key, value string) ValueOptionFunc {
+	return func(o *valueOptions) {
+		o.hashKey = key
+		o.hashValue = strings.ToLower(value)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type matchValue struct {
+	opts   *valueOptions
+	key    glob.Glob
+	regex  *regexp.Regexp
+	digest []byte
+}
+
+// IsSatisfiedBy returns specification satisfaction status
+func (s *matchValue) IsSatisfiedBy(object interface{}) bool {
+	// If object is a package
+	p, ok := object.(*bundlev1.Package)
+	if !ok || p == nil || p.Secrets == nil {
+		return false
+	}
+
+	for _, kv := range p.Secrets.Data {
+		if kv == nil {
+			continue
+		}
+		if s.key != nil && !s.key.Match(kv.Key) {
+			continue
+		}
+
+		// Unpack the value
+		var value interface{}
+		if err := secret.Unpack(kv.Value, &value); err != nil {
+			continue
+		}
+
+		if s.matchValue(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//nolint:gocyclo // criteria evaluation
+func (s *matchValue) matchValue(value interface{}) bool {
+	raw, isRaw := rawValue(value)
+
+	// Check type
+	if s.opts.valueType != "" && valueType(value) != s.opts.valueType {
+		return false
+	}
+
+	// Check size
+	if s.opts.minSize > 0 || s.opts.maxSize > 0 {
+		size := len(raw)
+		if !isRaw {
+			payload, err := json.Marshal(value)
+			if err != nil {
+				return false
+			}
+			size = len(payload)
+		}
+		if size < s.opts.minSize || (s.opts.maxSize > 0 && size > s.opts.maxSize) {
+			return false
+		}
+	}
+
+	// Check regex
+	if s.regex != nil && (!isRaw || !s.regex.Match(raw)) {
+		return false
+	}
+
+	// Check format and key properties
+	if s.opts.format != "" || s.opts.keyType != "" {
+		if !isRaw {
+			return false
+		}
+
+		info := inspect.Inspect(string(raw))
+		switch {
+		case s.opts.format == "pem" && !info.IsPEM():
+			return false
+		case s.opts.format != "" && s.opts.format != "pem" && info.Format != s.opts.format:
+			return false
+		case s.opts.keyType != "" && info.KeyType != s.opts.keyType:
+			return false
+		case s.opts.keyBits > 0 && info.KeyBits != s.opts.keyBits:
+			return false
+		}
+	}
+
+	// Check keyed hash
+	if s.digest != nil {
+		if !isRaw {
+			return false
+		}
+
+		h := hmac.New(sha256.New, []byte(s.opts.hashKey))
+		h.Write(raw)
+		if !security.SecureCompare(h.Sum(nil), s.digest) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func rawValue(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case []byte:
+		return "bytes"
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return ""
+	}
+}
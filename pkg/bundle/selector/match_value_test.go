@@ -0,0 +1,372 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selector
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/secret"
+)
+
+func mustPackage(t *testing.T, name string, kv map[string]interface{}) *bundlev1.Package {
+	t.Helper()
+
+	p := &bundlev1.Package{
+		Name: name,
+		Secrets: &bundlev1.SecretChain{
+			Data: []*bundlev1.KV{},
+		},
+	}
+	for k, v := range kv {
+		packed, err := secret.Pack(v)
+		if err != nil {
+			t.Fatalf("unable to pack secret value: %v", err)
+		}
+		p.Secrets.Data = append(p.Secrets.Data, &bundlev1.KV{
+			Key:   k,
+			Value: packed,
+		})
+	}
+
+	return p
+}
+
+func mustPrivateKeyPEM(t *testing.T, key interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to encode private key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestMatchValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []ValueOptionFunc
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "invalid type",
+			opts:    []ValueOptionFunc{WithValueType("object")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			opts:    []ValueOptionFunc{WithValueFormat("xml")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key type",
+			opts:    []ValueOptionFunc{WithValueKeyType("dsa", 0)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid size bounds",
+			opts:    []ValueOptionFunc{WithValueSize(10, 5)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key glob",
+			opts:    []ValueOptionFunc{WithValueKey("[")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			opts:    []ValueOptionFunc{WithValueRegex("(")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid hash encoding",
+			opts:    []ValueOptionFunc{WithValueHash("key", "zz")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid hash length",
+			opts:    []ValueOptionFunc{WithValueHash("key", "0011")},
+			wantErr: true,
+		},
+		{
+			name:    "blank hash key",
+			opts:    []ValueOptionFunc{WithValueHash("", hex.EncodeToString(make([]byte, 32)))},
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			opts: []ValueOptionFunc{
+				WithValueKey("*"),
+				WithValueType("String"),
+				WithValueSize(1, 10),
+				WithValueRegex("^[a-z]+$"),
+				WithValueFormat("PEM"),
+				WithValueKeyType("RSA", 2048),
+				WithValueHash("key", hex.EncodeToString(make([]byte, 32))),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MatchValue(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MatchValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_matchValue_IsSatisfiedBy(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate EC key: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("hash-key"))
+	mac.Write([]byte("changeme"))
+	digest := hex.EncodeToString(mac.Sum(nil))
+
+	rsaPackage := mustPackage(t, "infra/aws/security/server/ssh", map[string]interface{}{
+		"private": mustPrivateKeyPEM(t, rsaKey),
+	})
+	ecPackage := mustPackage(t, "infra/aws/security/server/tls", map[string]interface{}{
+		"private": mustPrivateKeyPEM(t, ecKey),
+	})
+	passwordPackage := mustPackage(t, "app/production/database/credentials", map[string]interface{}{
+		"user":     "admin",
+		"password": "changeme",
+		"port":     5432,
+		"raw":      []byte{0x00, 0x01, 0x02, 0x03},
+		"url":      "https://db.example.com:5432",
+	})
+
+	tests := []struct {
+		name   string
+		opts   []ValueOptionFunc
+		object interface{}
+		want   bool
+	}{
+		{
+			name: "nil",
+			want: false,
+		},
+		{
+			name:   "not supported type",
+			object: struct{}{},
+			want:   false,
+		},
+		{
+			name:   "package without secrets",
+			object: &bundlev1.Package{Name: "foo"},
+			want:   false,
+		},
+		{
+			name:   "no criteria",
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "type: string",
+			opts:   []ValueOptionFunc{WithValueKey("user"), WithValueType("string")},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "type: bytes",
+			opts:   []ValueOptionFunc{WithValueKey("raw"), WithValueType("bytes")},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "type: number",
+			opts:   []ValueOptionFunc{WithValueKey("port"), WithValueType("number")},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "type: not match",
+			opts:   []ValueOptionFunc{WithValueType("bool")},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name:   "size: match",
+			opts:   []ValueOptionFunc{WithValueKey("password"), WithValueSize(8, 8)},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "size: too short",
+			opts:   []ValueOptionFunc{WithValueKey("password"), WithValueSize(16, 0)},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name:   "size: too long",
+			opts:   []ValueOptionFunc{WithValueKey("password"), WithValueSize(0, 4)},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name:   "regex: match",
+			opts:   []ValueOptionFunc{WithValueRegex("^change")},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "regex: not match",
+			opts:   []ValueOptionFunc{WithValueRegex("^secret")},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name:   "format: url",
+			opts:   []ValueOptionFunc{WithValueFormat("url")},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "format: pem",
+			opts:   []ValueOptionFunc{WithValueFormat("pem")},
+			object: ecPackage,
+			want:   true,
+		},
+		{
+			name:   "format: private key",
+			opts:   []ValueOptionFunc{WithValueFormat("private_key")},
+			object: rsaPackage,
+			want:   true,
+		},
+		{
+			name:   "format: not match",
+			opts:   []ValueOptionFunc{WithValueFormat("jwt")},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name:   "key type: rsa-2048",
+			opts:   []ValueOptionFunc{WithValueKeyType("rsa", 2048)},
+			object: rsaPackage,
+			want:   true,
+		},
+		{
+			name:   "key type: rsa-4096",
+			opts:   []ValueOptionFunc{WithValueKeyType("rsa", 4096)},
+			object: rsaPackage,
+			want:   false,
+		},
+		{
+			name:   "key type: ec any size",
+			opts:   []ValueOptionFunc{WithValueKeyType("ec", 0)},
+			object: ecPackage,
+			want:   true,
+		},
+		{
+			name:   "key type: not rsa",
+			opts:   []ValueOptionFunc{WithValueKeyType("rsa", 2048)},
+			object: ecPackage,
+			want:   false,
+		},
+		{
+			name:   "hash: match",
+			opts:   []ValueOptionFunc{WithValueHash("hash-key", digest)},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name:   "hash: wrong key",
+			opts:   []ValueOptionFunc{WithValueHash("other-key", digest)},
+			object: passwordPackage,
+			want:   false,
+		},
+		{
+			name: "all criteria on the same secret",
+			opts: []ValueOptionFunc{
+				WithValueKey("pass*"),
+				WithValueType("string"),
+				WithValueSize(8, 32),
+				WithValueHash("hash-key", digest),
+			},
+			object: passwordPackage,
+			want:   true,
+		},
+		{
+			name: "criteria spread over different secrets",
+			opts: []ValueOptionFunc{
+				WithValueKey("user"),
+				WithValueHash("hash-key", digest),
+			},
+			object: passwordPackage,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := MatchValue(tt.opts...)
+			if err != nil {
+				t.Fatalf("unable to build specification: %v", err)
+			}
+			if got := s.IsSatisfiedBy(tt.object); got != tt.want {
+				t.Errorf("matchValue.IsSatisfiedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchValue_IsSatisfiedBy_Fuzz(t *testing.T) {
+	// Making sure the function never panics
+	for i := 0; i < 50; i++ {
+		f := fuzz.New()
+
+		// Prepare arguments
+		opts := []ValueOptionFunc{
+			WithValueType("string"),
+			WithValueSize(1, 100),
+			WithValueFormat("pem"),
+		}
+		s, err := MatchValue(opts...)
+		if err != nil {
+			t.Fatalf("unable to build specification: %v", err)
+		}
+
+		var p bundlev1.Package
+		f.Fuzz(&p)
+
+		// Execute
+		s.IsSatisfiedBy(&p)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle/template/visitor"
 	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/template/values/schema"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -57,6 +58,28 @@ func Validate(spec *bundlev1.Template) error {
 	return nil
 }
 
+// ValidateValues checks the given values using the template values schema.
+func ValidateValues(spec *bundlev1.Template, values map[string]interface{}) error {
+	// Check if a schema is defined
+	if spec == nil || spec.Spec == nil || spec.Spec.ValuesSchema == nil {
+		return nil
+	}
+
+	// Encode schema definition
+	definition, err := schema.FromStruct(spec.Spec.ValuesSchema)
+	if err != nil {
+		return fmt.Errorf("unable to prepare values schema: %w", err)
+	}
+
+	// Validate values
+	if err := schema.Validate(definition, values); err != nil {
+		return fmt.Errorf("invalid template values: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
 // Checksum calculates the bundle template checksum.
 func Checksum(spec *bundlev1.Template) (string, error) {
 	// Check if spec is nil
@@ -31,6 +31,18 @@ func New(result *bundlev1.Bundle, templateCtx engine.Context) visitor.TemplateVi
 	return &secretBuilder{
 		bundle:          result,
 		templateContext: templateCtx,
+		compile:         parseSecretTemplate,
+	}
+}
+
+// Declare returns a secret builder visitor instance which only resolves
+// package paths, labels, annotations and secret keys. Secret values are not
+// generated, and secret suffix templates are not executed.
+func Declare(result *bundlev1.Bundle, templateCtx engine.Context) visitor.TemplateVisitor {
+	return &secretBuilder{
+		bundle:          result,
+		templateContext: templateCtx,
+		compile:         declareSecretTemplate,
 	}
 }
 
@@ -39,9 +51,13 @@ func New(result *bundlev1.Bundle, templateCtx engine.Context) visitor.TemplateVi
 type secretBuilder struct {
 	bundle          *bundlev1.Bundle
 	templateContext engine.Context
+	compile         compiler
 	err             error
 }
 
+// compiler builds a secret package from a secret suffix.
+type compiler func(templateContext engine.Context, secretPath string, item *bundlev1.SecretSuffix, data interface{}) (*bundlev1.Package, error)
+
 //nolint:gocyclo,gocognit,funlen // refactoring later
 func (sb *secretBuilder) Visit(t *bundlev1.Template) {
 	results := make(chan *bundlev1.Package)
@@ -67,7 +83,7 @@ func (sb *secretBuilder) Visit(t *bundlev1.Template) {
 		if t.Spec.Namespaces.Infrastructure != nil {
 			for _, obj := range t.Spec.Namespaces.Infrastructure {
 				// Initialize a infrastructure visitor
-				v := infrastructure(results, sb.templateContext)
+				v := infrastructure(results, sb.templateContext, sb.compile)
 
 				// Traverse the object-tree
 				visitor.InfrastructureDecorator(obj).Accept(v)
@@ -90,7 +106,7 @@ func (sb *secretBuilder) Visit(t *bundlev1.Template) {
 				}
 
 				// Initialize a infrastructure visitor
-				v, err := platform(results, sb.templateContext, t.Spec.Selector.Quality, t.Spec.Selector.Platform)
+				v, err := platform(results, sb.templateContext, sb.compile, t.Spec.Selector.Quality, t.Spec.Selector.Platform)
 				if err != nil {
 					sb.err = err
 					return
@@ -117,7 +133,7 @@ func (sb *secretBuilder) Visit(t *bundlev1.Template) {
 				}
 
 				// Initialize a infrastructure visitor
-				v, err := product(results, sb.templateContext, t.Spec.Selector.Product, t.Spec.Selector.Version)
+				v, err := product(results, sb.templateContext, sb.compile, t.Spec.Selector.Product, t.Spec.Selector.Version)
 				if err != nil {
 					sb.err = err
 					return
@@ -144,7 +160,7 @@ func (sb *secretBuilder) Visit(t *bundlev1.Template) {
 				}
 
 				// Initialize a infrastructure visitor
-				v, err := application(results, sb.templateContext, t.Spec.Selector.Quality, t.Spec.Selector.Platform, t.Spec.Selector.Product, t.Spec.Selector.Version)
+				v, err := application(results, sb.templateContext, sb.compile, t.Spec.Selector.Quality, t.Spec.Selector.Platform, t.Spec.Selector.Product, t.Spec.Selector.Version)
 				if err != nil {
 					sb.err = err
 					return
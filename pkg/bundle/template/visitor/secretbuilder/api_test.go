@@ -18,11 +18,13 @@
 package secretbuilder
 
 import (
+	"bytes"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/build/testbuild"
 	"github.com/elastic/harp/pkg/template/engine"
 )
 
@@ -256,3 +258,66 @@ func TestVisit_Content_Fuzz(t *testing.T) {
 		v.Visit(tmpl)
 	}
 }
+
+func TestVisit_Deterministic(t *testing.T) {
+	if !testbuild.Enabled() {
+		t.Skip("deterministic mode is only available in test builds")
+	}
+
+	build := func(t *testing.T, suffixes ...string) map[string][]byte {
+		t.Helper()
+
+		d, err := engine.NewDeterministic("seed")
+		if err != nil {
+			t.Fatalf("unable to initialize deterministic mode: %v", err)
+		}
+
+		secrets := []*bundlev1.SecretSuffix{}
+		for _, suffix := range suffixes {
+			secrets = append(secrets, &bundlev1.SecretSuffix{
+				Suffix:   suffix,
+				Template: `{"password":{{ paranoidPassword | toJson }}}`,
+			})
+		}
+
+		b := &bundlev1.Bundle{}
+		v := New(b, engine.NewContext(engine.WithDeterministic(d)))
+		v.Visit(&bundlev1.Template{
+			Spec: &bundlev1.TemplateSpec{
+				Selector: &bundlev1.Selector{
+					Quality:  "production",
+					Product:  "harp",
+					Version:  "v1.0.0",
+					Platform: "test",
+				},
+				Namespaces: &bundlev1.Namespaces{
+					Application: []*bundlev1.ApplicationComponentNS{
+						{Name: "server", Secrets: secrets},
+					},
+				},
+			},
+		})
+		if err := v.Error(); err != nil {
+			t.Fatalf("unable to build secrets: %v", err)
+		}
+
+		res := map[string][]byte{}
+		for _, p := range b.Packages {
+			res[p.Name] = p.Secrets.Data[0].Value
+		}
+		return res
+	}
+
+	values := build(t, "database", "cache")
+	if bytes.Equal(values["app/production/test/harp/v1.0.0/server/database"], values["app/production/test/harp/v1.0.0/server/cache"]) {
+		t.Error("secrets generated with the same template must have distinct values")
+	}
+
+	// Inserting a secret doesn't change the values of the other secrets
+	inserted := build(t, "database", "session", "cache")
+	for name, value := range values {
+		if !bytes.Equal(value, inserted[name]) {
+			t.Errorf("secret value of '%s' changed", name)
+		}
+	}
+}
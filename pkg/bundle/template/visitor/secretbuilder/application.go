@@ -30,6 +30,7 @@ import (
 type applicationSecretBuilder struct {
 	results         chan *bundlev1.Package
 	templateContext engine.Context
+	compile         compiler
 
 	// Context
 	quality   string
@@ -44,7 +45,7 @@ type applicationSecretBuilder struct {
 
 // Infrastructure returns a visitor instance to generate secretpath
 // and values.
-func application(results chan *bundlev1.Package, templateContext engine.Context, quality, platform, product, version string) (visitor.ApplicationVisitor, error) {
+func application(results chan *bundlev1.Package, templateContext engine.Context, compile compiler, quality, platform, product, version string) (visitor.ApplicationVisitor, error) {
 	// Parse selector values
 	platformQuality, err := engine.RenderContext(templateContext, quality)
 	if err != nil {
@@ -81,6 +82,7 @@ func application(results chan *bundlev1.Package, templateContext engine.Context,
 	return &applicationSecretBuilder{
 		results:         results,
 		templateContext: templateContext,
+		compile:         compile,
 		quality:         platformQuality,
 		platform:        platformName,
 		product:         productName,
@@ -144,7 +146,7 @@ func (b *applicationSecretBuilder) VisitForComponent(obj *bundlev1.ApplicationCo
 		}
 
 		// Compile template
-		p, err := parseSecretTemplate(b.templateContext, secretPath, item, tmplModel)
+		p, err := b.compile(b.templateContext, secretPath, item, tmplModel)
 		if err != nil {
 			b.err = err
 			return
@@ -0,0 +1,286 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package secretbuilder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+	"text/template/parse"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+// skeletonPlaceholder replaces actions which can't be rendered without
+// calling template functions. It is a valid JSON value and string content.
+const skeletonPlaceholder = "null"
+
+// Template builtins without side effects, allowed in rendered actions.
+var skeletonBuiltins = map[string]bool{
+	"and": true, "or": true, "not": true, "len": true, "index": true, "slice": true,
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+	"print": true, "printf": true, "println": true,
+}
+
+func declareSecretTemplate(templateContext engine.Context, secretPath string, item *bundlev1.SecretSuffix, data interface{}) (*bundlev1.Package, error) {
+	// Check arguments
+	if types.IsNil(templateContext) {
+		return nil, errors.New("unable to process with nil context")
+	}
+	if secretPath == "" {
+		return nil, errors.New("unable to process with blank secret path")
+	}
+	if item == nil {
+		return nil, errors.New("unable to process with nil secret suffix")
+	}
+
+	// Extract declared secret keys
+	keys, err := declaredKeys(templateContext, secretPath, item, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract secret keys (path:%s suffix:%s): %w", secretPath, item.Suffix, err)
+	}
+
+	// Prepare secret list without values
+	chain := secretChain(item)
+	for _, key := range keys {
+		chain.Data = append(chain.Data, &bundlev1.KV{
+			Key: key,
+		})
+	}
+
+	// No error
+	return buildPackage(templateContext, secretPath, chain, item)
+}
+
+// declaredKeys returns the sorted secret keys declared by the secret suffix.
+// Filenames of the content are rendered, and the keys of the template are
+// extracted from its skeleton.
+func declaredKeys(templateContext engine.Context, secretPath string, item *bundlev1.SecretSuffix, data interface{}) ([]string, error) {
+	if len(item.Content) == 0 && item.Template == "" {
+		return nil, fmt.Errorf("content or template property must be defined")
+	}
+
+	keys := map[string]struct{}{}
+
+	if item.Template != "" {
+		payload, err := renderSkeleton(templateContext, item.Template, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render suffix template skeleton: %w", err)
+		}
+
+		// Extract top level keys
+		kv := map[string]json.RawMessage{}
+		if err := json.Unmarshal([]byte(payload), &kv); err != nil {
+			return nil, fmt.Errorf("unable to extract keys from the suffix template of secret path '%s': %w", secretPath, err)
+		}
+		for key := range kv {
+			keys[key] = struct{}{}
+		}
+	}
+
+	for _, filename := range sortedKeys(item.Content) {
+		// Render filename
+		renderedFilename, err := engine.RenderContextWithData(templateContext, filename, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render filename template: %w", err)
+		}
+
+		keys[renderedFilename] = struct{}{}
+	}
+
+	// Sort keys and skip empty key
+	result := make([]string, 0, len(keys))
+	for key := range keys {
+		if key == "" {
+			continue
+		}
+		result = append(result, key)
+	}
+	sort.Strings(result)
+
+	// No error
+	return result, nil
+}
+
+// renderSkeleton renders the template structure without calling template
+// functions. Actions calling functions are replaced by a placeholder so that
+// generators and secret lookups are never executed, the other actions are
+// rendered with template values.
+func renderSkeleton(templateContext engine.Context, input string, data interface{}) (string, error) {
+	leftDelim, rightDelim := templateContext.Delims()
+
+	t, err := template.New(templateContext.Name()).
+		Delims(leftDelim, rightDelim).
+		Funcs(engine.FuncMap(nil)).
+		Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("unable to compile template: %w", err)
+	}
+
+	s := &skeleton{
+		// Parse tree nodes are printed with default delimiters
+		templateContext: engine.NewContext(
+			engine.WithName(templateContext.Name()),
+			engine.WithStrictMode(templateContext.StrictMode()),
+			engine.WithValues(templateContext.Values()),
+			engine.WithFiles(templateContext.Files()),
+			engine.WithSandbox(templateContext.Sandbox()),
+		),
+		data: data,
+	}
+	if err := s.walk(t.Tree.Root, false); err != nil {
+		return "", err
+	}
+
+	// No error
+	return s.out.String(), nil
+}
+
+// -----------------------------------------------------------------------------
+
+type skeleton struct {
+	templateContext engine.Context
+	data            interface{}
+	out             bytes.Buffer
+}
+
+// walk renders the given node. Scoped nodes are located in a range or with
+// block, where the dot value is unknown.
+func (s *skeleton) walk(node parse.Node, scoped bool) error {
+	// Skip missing lists
+	if l, ok := node.(*parse.ListNode); ok && l == nil {
+		return nil
+	}
+
+	// Render function free nodes
+	if !scoped && isStatic(node, map[string]bool{}) {
+		return s.render(node.String())
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			if err := s.walk(child, scoped); err != nil {
+				return err
+			}
+		}
+	case *parse.TextNode:
+		s.out.Write(n.Text)
+	case *parse.ActionNode:
+		// Variable declarations don't produce output
+		if len(n.Pipe.Decl) == 0 {
+			s.out.WriteString(skeletonPlaceholder)
+		}
+	case *parse.IfNode:
+		// Evaluate the condition when possible
+		if !scoped && isStatic(n.Pipe, map[string]bool{}) {
+			var cond bytes.Buffer
+			cond.WriteString("{{if ")
+			cond.WriteString(n.Pipe.String())
+			cond.WriteString("}}true{{end}}")
+
+			value, err := engine.RenderContextWithData(s.templateContext, cond.String(), s.data)
+			if err != nil {
+				return fmt.Errorf("unable to evaluate condition '%s': %w", n.Pipe, err)
+			}
+			if value != "true" {
+				return s.walk(n.ElseList, scoped)
+			}
+		}
+		return s.walk(n.List, scoped)
+	case *parse.RangeNode:
+		return s.walk(n.List, true)
+	case *parse.WithNode:
+		return s.walk(n.List, true)
+	case *parse.TemplateNode:
+		s.out.WriteString(skeletonPlaceholder)
+	}
+
+	return nil
+}
+
+func (s *skeleton) render(input string) error {
+	out, err := engine.RenderContextWithData(s.templateContext, input, s.data)
+	if err != nil {
+		return fmt.Errorf("unable to render template skeleton: %w", err)
+	}
+
+	s.out.WriteString(out)
+	return nil
+}
+
+// isStatic returns true if the node doesn't call template functions, named
+// templates, or variables declared outside of the node.
+func isStatic(node parse.Node, declared map[string]bool) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return true
+		}
+		for _, child := range n.Nodes {
+			if !isStatic(child, declared) {
+				return false
+			}
+		}
+	case *parse.ActionNode:
+		return isStatic(n.Pipe, declared)
+	case *parse.IfNode:
+		return isStaticBranch(&n.BranchNode, declared)
+	case *parse.RangeNode:
+		return isStaticBranch(&n.BranchNode, declared)
+	case *parse.WithNode:
+		return isStaticBranch(&n.BranchNode, declared)
+	case *parse.TemplateNode:
+		return false
+	case *parse.PipeNode:
+		if n == nil {
+			return true
+		}
+		for _, cmd := range n.Cmds {
+			if !isStatic(cmd, declared) {
+				return false
+			}
+		}
+		for _, v := range n.Decl {
+			declared[v.Ident[0]] = true
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if !isStatic(arg, declared) {
+				return false
+			}
+		}
+	case *parse.ChainNode:
+		return isStatic(n.Node, declared)
+	case *parse.IdentifierNode:
+		return skeletonBuiltins[n.Ident]
+	case *parse.VariableNode:
+		return n.Ident[0] == "$" || declared[n.Ident[0]]
+	}
+
+	return true
+}
+
+func isStaticBranch(n *parse.BranchNode, declared map[string]bool) bool {
+	return isStatic(n.Pipe, declared) && isStatic(n.List, declared) && isStatic(n.ElseList, declared)
+}
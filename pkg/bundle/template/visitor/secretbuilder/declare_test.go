@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package secretbuilder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+func TestDeclaredKeys(t *testing.T) {
+	templateContext := engine.NewContext(engine.WithValues(map[string]interface{}{
+		"prefix":  "db",
+		"replica": false,
+		"users":   []interface{}{"admin", "reader"},
+	}))
+
+	type args struct {
+		item *bundlev1.SecretSuffix
+		data interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			args: args{
+				item: &bundlev1.SecretSuffix{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid json",
+			args: args{
+				item: &bundlev1.SecretSuffix{
+					Template: `{"foo`,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported ranged generators",
+			args: args{
+				item: &bundlev1.SecretSuffix{
+					Template: `{ {{ range $i, $u := .Values.users }}{{ if $i }},{{ end }}"{{ $u }}": "{{ paranoidPassword }}"{{ end }} }`,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "generators and secret lookups",
+			args: args{
+				item: &bundlev1.SecretSuffix{
+					Template: `{
+  "{{ .Values.prefix }}_USER": "{{ .Data.Component }}",
+  "PASSWORD": {{ paranoidPassword | toJson }},
+  {{ if .Values.replica }}"REPLICA_PASSWORD": "{{ strongPassword }}",{{ end }}
+  {{ $root := secret "infra/database/root" }}"ROOT_PASSWORD": "{{ $root.password }}"
+}`,
+				},
+				data: map[string]interface{}{
+					"Component": "server",
+				},
+			},
+			want: []string{"PASSWORD", "ROOT_PASSWORD", "db_USER"},
+		},
+		{
+			name: "content",
+			args: args{
+				item: &bundlev1.SecretSuffix{
+					Template: `{"foo": "{{ cryptoKey "aes:256" }}"}`,
+					Content: map[string]string{
+						"{{ .Values.prefix }}.key": `{{ cryptoKey "aes:256" }}`,
+					},
+				},
+			},
+			want: []string{"db.key", "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := declaredKeys(templateContext, "app/qa/security/harp/v1.0.0/server/database", tt.args.item, tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("declaredKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("declaredKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
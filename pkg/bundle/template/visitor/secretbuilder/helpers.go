@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
@@ -30,6 +31,15 @@ import (
 )
 
 func parseSecretTemplate(templateContext engine.Context, secretPath string, item *bundlev1.SecretSuffix, data interface{}) (*bundlev1.Package, error) {
+	// Check arguments
+	if types.IsNil(templateContext) {
+		return nil, errors.New("unable to process with nil context")
+	}
+
+	// Scope rendering to the secret path, so that deterministic values of a
+	// secret don't depend on the other secrets of the template.
+	templateContext = engine.NamedContext(templateContext, fmt.Sprintf("%s#%s", templateContext.Name(), secretPath))
+
 	// Prepare secret chain
 	chain, err := buildSecretChain(templateContext, secretPath, item, data)
 	if err != nil {
@@ -59,25 +69,7 @@ func buildSecretChain(templateContext engine.Context, secretPath string, item *b
 	}
 
 	// Prepare secret list
-	chain := &bundlev1.SecretChain{
-		Version: uint32(0),
-		Labels: map[string]string{
-			"generated": "true",
-		},
-		Annotations: map[string]string{
-			"creationDate": fmt.Sprintf("%d", time.Now().UTC().Unix()),
-			"description":  item.Description,
-			"template":     item.Template,
-		},
-		Data:            make([]*bundlev1.KV, 0),
-		NextVersion:     nil,
-		PreviousVersion: nil,
-	}
-
-	// Check vendor status
-	if item.Vendor {
-		chain.Labels["vendor"] = "true"
-	}
+	chain := secretChain(item)
 
 	// Iterate over K/V
 	for key, value := range kv {
@@ -104,6 +96,31 @@ func buildSecretChain(templateContext engine.Context, secretPath string, item *b
 	return chain, nil
 }
 
+// secretChain returns an empty secret chain described by the secret suffix.
+func secretChain(item *bundlev1.SecretSuffix) *bundlev1.SecretChain {
+	chain := &bundlev1.SecretChain{
+		Version: uint32(0),
+		Labels: map[string]string{
+			"generated": "true",
+		},
+		Annotations: map[string]string{
+			"creationDate": fmt.Sprintf("%d", time.Now().UTC().Unix()),
+			"description":  item.Description,
+			"template":     item.Template,
+		},
+		Data:            make([]*bundlev1.KV, 0),
+		NextVersion:     nil,
+		PreviousVersion: nil,
+	}
+
+	// Check vendor status
+	if item.Vendor {
+		chain.Labels["vendor"] = "true"
+	}
+
+	return chain
+}
+
 // suffix is a function used for suffix template compiler.
 func renderSuffix(templateContext engine.Context, secretPath string, item *bundlev1.SecretSuffix, data interface{}) (map[string]interface{}, error) {
 	// Check input
@@ -137,15 +154,18 @@ func renderSuffix(templateContext engine.Context, secretPath string, item *bundl
 	}
 
 	if len(item.Content) > 0 {
-		for filename, content := range item.Content {
+		// Render in a stable order for deterministic generators
+		for _, filename := range sortedKeys(item.Content) {
+			content := item.Content[filename]
+
 			// Render filename
 			renderedFilename, err := engine.RenderContextWithData(templateContext, filename, data)
 			if err != nil {
 				return nil, fmt.Errorf("unable to render filename template: %w", err)
 			}
 
-			// Render content
-			payload, err := engine.RenderContextWithData(templateContext, content, data)
+			// Render content, scoped to the file
+			payload, err := engine.RenderContextWithData(engine.NamedContext(templateContext, fmt.Sprintf("%s/%s", templateContext.Name(), filename)), content, data)
 			if err != nil {
 				return nil, fmt.Errorf("unable to render file content template: %w", err)
 			}
@@ -176,7 +196,9 @@ func buildPackage(templateContext engine.Context, secretPath string, chain *bund
 
 	// Evaluate annotation values
 	if item.Annotations != nil {
-		for k, v := range item.Annotations {
+		for _, k := range sortedKeys(item.Annotations) {
+			v := item.Annotations[k]
+
 			// Evaluate using template engine
 			renderedValue, err := engine.RenderContext(templateContext, v)
 			if err != nil {
@@ -189,7 +211,9 @@ func buildPackage(templateContext engine.Context, secretPath string, chain *bund
 
 	// Evaluate labels values
 	if item.Labels != nil {
-		for k, v := range item.Labels {
+		for _, k := range sortedKeys(item.Labels) {
+			v := item.Labels[k]
+
 			// Evaluate using template engine
 			renderedValue, err := engine.RenderContext(templateContext, v)
 			if err != nil {
@@ -208,3 +232,12 @@ func buildPackage(templateContext engine.Context, secretPath string, chain *bund
 		Labels:      item.GetLabels(),
 	}, nil
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
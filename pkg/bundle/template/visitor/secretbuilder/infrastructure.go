@@ -29,6 +29,7 @@ import (
 type infrastructureSecretBuilder struct {
 	results         chan *bundlev1.Package
 	templateContext engine.Context
+	compile         compiler
 
 	// Context
 	provider    string
@@ -43,10 +44,11 @@ type infrastructureSecretBuilder struct {
 
 // Infrastructure returns a visitor instance to generate secretpath
 // and values.
-func infrastructure(results chan *bundlev1.Package, templateContext engine.Context) visitor.InfrastructureVisitor {
+func infrastructure(results chan *bundlev1.Package, templateContext engine.Context, compile compiler) visitor.InfrastructureVisitor {
 	return &infrastructureSecretBuilder{
 		results:         results,
 		templateContext: templateContext,
+		compile:         compile,
 	}
 }
 
@@ -150,7 +152,7 @@ func (b *infrastructureSecretBuilder) VisitForService(obj *bundlev1.Infrastructu
 		}
 
 		// Compile template
-		p, err := parseSecretTemplate(b.templateContext, secretPath, item, tmplModel)
+		p, err := b.compile(b.templateContext, secretPath, item, tmplModel)
 		if err != nil {
 			b.err = err
 			return
@@ -30,6 +30,7 @@ import (
 type platformSecretBuilder struct {
 	results         chan *bundlev1.Package
 	templateContext engine.Context
+	compile         compiler
 
 	// Context
 	quality   string
@@ -43,7 +44,7 @@ type platformSecretBuilder struct {
 
 // Infrastructure returns a visitor instance to generate secretpath
 // and values.
-func platform(results chan *bundlev1.Package, templateContext engine.Context, quality, name string) (visitor.PlatformVisitor, error) {
+func platform(results chan *bundlev1.Package, templateContext engine.Context, compile compiler, quality, name string) (visitor.PlatformVisitor, error) {
 	// Parse selector values
 	platformQuality, err := engine.RenderContext(templateContext, quality)
 	if err != nil {
@@ -64,6 +65,7 @@ func platform(results chan *bundlev1.Package, templateContext engine.Context, qu
 	return &platformSecretBuilder{
 		results:         results,
 		templateContext: templateContext,
+		compile:         compile,
 		quality:         platformQuality,
 		name:            platformName,
 	}, nil
@@ -141,7 +143,7 @@ func (b *platformSecretBuilder) VisitForComponent(obj *bundlev1.PlatformComponen
 		}
 
 		// Compile template
-		p, err := parseSecretTemplate(b.templateContext, secretPath, item, tmplModel)
+		p, err := b.compile(b.templateContext, secretPath, item, tmplModel)
 		if err != nil {
 			b.err = err
 			return
@@ -30,6 +30,7 @@ import (
 type productSecretBuilder struct {
 	results         chan *bundlev1.Package
 	templateContext engine.Context
+	compile         compiler
 
 	// Context
 	name      string
@@ -42,7 +43,7 @@ type productSecretBuilder struct {
 
 // Infrastructure returns a visitor instance to generate secretpath
 // and values.
-func product(results chan *bundlev1.Package, templateContext engine.Context, name, version string) (visitor.ProductVisitor, error) {
+func product(results chan *bundlev1.Package, templateContext engine.Context, compile compiler, name, version string) (visitor.ProductVisitor, error) {
 	// Parse selector values
 	productName, err := engine.RenderContext(templateContext, name)
 	if err != nil {
@@ -63,6 +64,7 @@ func product(results chan *bundlev1.Package, templateContext engine.Context, nam
 	return &productSecretBuilder{
 		results:         results,
 		templateContext: templateContext,
+		compile:         compile,
 		name:            productName,
 		version:         productVersion,
 	}, nil
@@ -120,7 +122,7 @@ func (b *productSecretBuilder) VisitForComponent(obj *bundlev1.ProductComponentN
 		}
 
 		// Compile template
-		p, err := parseSecretTemplate(b.templateContext, secretPath, item, tmplModel)
+		p, err := b.compile(b.templateContext, secretPath, item, tmplModel)
 		if err != nil {
 			b.err = err
 			return
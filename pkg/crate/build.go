@@ -28,6 +28,7 @@ import (
 
 	"go.uber.org/zap"
 
+	containerv1 "github.com/elastic/harp/api/gen/go/harp/container/v1"
 	"github.com/elastic/harp/pkg/container"
 	"github.com/elastic/harp/pkg/crate/cratefile"
 	schemav1 "github.com/elastic/harp/pkg/crate/schema/v1"
@@ -39,13 +40,37 @@ const (
 	maxContainerSize = 25 * 1024 * 1024
 )
 
+// ContainerHook is invoked with the loaded container before sealing. It can
+// reject or replace the container.
+type ContainerHook func(c *containerv1.Container) (*containerv1.Container, error)
+
+type buildOptions struct {
+	containerHook ContainerHook
+}
+
+// BuildOption is used to customize the crate build.
+type BuildOption func(o *buildOptions)
+
+// WithContainerHook registers a hook invoked with the loaded container.
+func WithContainerHook(hook ContainerHook) BuildOption {
+	return func(o *buildOptions) {
+		o.containerHook = hook
+	}
+}
+
 // Build a crate from the given specification.
-func Build(rootFs fs.FS, spec *cratefile.Config) (*Image, error) {
+func Build(rootFs fs.FS, spec *cratefile.Config, opts ...BuildOption) (*Image, error) {
 	// Check arguments
 	if spec == nil {
 		return nil, errors.New("unable to build a crate with nil specification")
 	}
 
+	// Default options
+	dopts := &buildOptions{}
+	for _, o := range opts {
+		o(dopts)
+	}
+
 	log.Bg().Info("Extract container ...", zap.String("container", spec.Container.Path))
 
 	// Open container file
@@ -60,6 +85,14 @@ func Build(rootFs fs.FS, spec *cratefile.Config) (*Image, error) {
 		return nil, fmt.Errorf("unable to load input container '%s': %w", spec.Container.Path, err)
 	}
 
+	// Apply container hook
+	if dopts.containerHook != nil {
+		c, err = dopts.containerHook(c)
+		if err != nil {
+			return nil, fmt.Errorf("unable to process input container '%s': %w", spec.Container.Path, err)
+		}
+	}
+
 	// Check container sealing status
 	if !container.IsSealed(c) {
 		log.Bg().Info("Sealing container ...", zap.String("container", spec.Container.Path))
@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SecretReader returns a secret reader backed by the given store.
+//
+// A secret is either stored as a JSON object at the secret path, or as leaf
+// keys under the secret path (one key per secret key).
+func SecretReader(ctx context.Context, store Store) func(string) (map[string]interface{}, error) {
+	return func(secretPath string) (map[string]interface{}, error) {
+		// Try to retrieve the secret as a JSON object
+		item, err := store.Get(ctx, secretPath)
+		switch {
+		case err == nil:
+			var data map[string]interface{}
+			if errJSON := json.Unmarshal(item.Value, &data); errJSON != nil {
+				return nil, fmt.Errorf("unable to decode '%s' value as a JSON object: %w", secretPath, errJSON)
+			}
+
+			// No error
+			return data, nil
+		case errors.Is(err, ErrKeyNotFound):
+		default:
+			return nil, fmt.Errorf("unable to retrieve '%s' value: %w", secretPath, err)
+		}
+
+		// Try to retrieve the secret as leaf keys
+		items, err := store.List(ctx, secretPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list '%s' keys: %w", secretPath, err)
+		}
+
+		basePath := strings.Trim(secretPath, "/")
+		data := map[string]interface{}{}
+		for _, item := range items {
+			// Keep direct children only
+			key := strings.TrimPrefix(strings.Trim(item.Key, "/"), basePath+"/")
+			if key == "" || strings.Contains(key, "/") {
+				continue
+			}
+
+			data[key] = string(item.Value)
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("unable to retrieve '%s' value: %w", secretPath, ErrKeyNotFound)
+		}
+
+		// No error
+		return data, nil
+	}
+}
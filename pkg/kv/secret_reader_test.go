@@ -0,0 +1,133 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type memoryStore map[string][]byte
+
+func (s memoryStore) Get(_ context.Context, key string) (*Pair, error) {
+	if key == "failure" {
+		return nil, errors.New("test")
+	}
+	v, ok := s[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return &Pair{Key: key, Value: v}, nil
+}
+
+func (s memoryStore) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := s[key]
+	return ok, nil
+}
+
+func (s memoryStore) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s memoryStore) Put(_ context.Context, key string, value []byte) error {
+	s[key] = value
+	return nil
+}
+
+func (s memoryStore) List(_ context.Context, path string) ([]*Pair, error) {
+	res := []*Pair{}
+	for k, v := range s {
+		if strings.HasPrefix(k, path+"/") {
+			res = append(res, &Pair{Key: k, Value: v})
+		}
+	}
+	if len(res) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return res, nil
+}
+
+func (s memoryStore) Close() error {
+	return nil
+}
+
+func TestSecretReader(t *testing.T) {
+	store := memoryStore{
+		"app/production/database":          []byte(`{"user":"app","password":"foo"}`),
+		"app/production/invalid":           []byte(`not-json`),
+		"app/production/api/key":           []byte(`123456`),
+		"app/production/api/secret":        []byte(`azerty`),
+		"app/production/api/nested/secret": []byte(`ignored`),
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "json object",
+			path: "app/production/database",
+			want: map[string]interface{}{
+				"user":     "app",
+				"password": "foo",
+			},
+		},
+		{
+			name:    "not a json object",
+			path:    "app/production/invalid",
+			wantErr: true,
+		},
+		{
+			name: "leaf keys",
+			path: "app/production/api",
+			want: map[string]interface{}{
+				"key":    "123456",
+				"secret": "azerty",
+			},
+		},
+		{
+			name:    "not found",
+			path:    "app/production/unknown",
+			wantErr: true,
+		},
+		{
+			name:    "store error",
+			path:    "failure",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SecretReader(context.Background(), store)(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("%q. SecretReader()\n%s", tt.name, diff)
+			}
+		})
+	}
+}
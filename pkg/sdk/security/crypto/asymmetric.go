@@ -18,24 +18,33 @@
 package crypto
 
 import (
-	"crypto/ecdsa"
-	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/base64"
 	"fmt"
+	"io"
 
+	"filippo.io/age"
 	"github.com/pkg/errors"
 
 	"github.com/elastic/harp/build/fips"
+	"github.com/elastic/harp/pkg/sdk/security/crypto/bech32"
 
+	"golang.org/x/crypto/curve25519"
 	"golang.org/x/crypto/nacl/box"
 )
 
 // Keypair generates crypto keys according to given key type.
 func Keypair(keyType string) (interface{}, error) {
+	return GenerateKeypair(rand.Reader, keyType)
+}
+
+// GenerateKeypair generates crypto keys according to given key type using
+// the given random source.
+func GenerateKeypair(random io.Reader, keyType string) (interface{}, error) {
 	// Generate crypto materials
-	pub, priv, err := generateKeyPair(keyType)
+	pub, priv, err := generateKeyPair(random, keyType)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate a '%s' key pair: %w", keyType, err)
 	}
@@ -53,38 +62,38 @@ func Keypair(keyType string) (interface{}, error) {
 // -----------------------------------------------------------------------------
 
 //nolint:gocyclo // To refactor
-func generateKeyPair(keyType string) (publicKey, privateKey interface{}, err error) {
+func generateKeyPair(random io.Reader, keyType string) (publicKey, privateKey interface{}, err error) {
 	switch keyType {
 	case "rsa", "rsa:normal", "rsa:2048":
-		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		key, err := generateRSAKey(random, 2048)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate rsa-2048 key: %w", err)
 		}
 		pub := key.Public()
 		return pub, key, nil
 	case "rsa:strong", "rsa:4096":
-		key, err := rsa.GenerateKey(rand.Reader, 4096)
+		key, err := generateRSAKey(random, 4096)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate rsa-4096 key: %w", err)
 		}
 		pub := key.Public()
 		return pub, key, nil
 	case "ec", "ec:normal", "ec:p256":
-		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		key, err := generateECDSAKey(random, elliptic.P256())
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate ec-p256 key: %w", err)
 		}
 		pub := key.Public()
 		return pub, key, nil
 	case "ec:high", "ec:p384":
-		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		key, err := generateECDSAKey(random, elliptic.P384())
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate ec-p384 key: %w", err)
 		}
 		pub := key.Public()
 		return pub, key, nil
 	case "ec:strong", "ec:p521":
-		key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		key, err := generateECDSAKey(random, elliptic.P521())
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate ec-p521 key: %w", err)
 		}
@@ -94,7 +103,7 @@ func generateKeyPair(keyType string) (publicKey, privateKey interface{}, err err
 		if fips.Enabled() {
 			return nil, nil, errors.New("ed25519 key processing is disabled in FIPS Mode")
 		}
-		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		pub, priv, err := generateEd25519Key(random)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate ed25519 key: %w", err)
 		}
@@ -103,12 +112,103 @@ func generateKeyPair(keyType string) (publicKey, privateKey interface{}, err err
 		if fips.Enabled() {
 			return nil, nil, errors.New("x25519 key processing is disabled in FIPS Mode")
 		}
-		pub, priv, err := box.GenerateKey(rand.Reader)
+		pub, priv, err := box.GenerateKey(keySource(random))
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to generate naclbox key: %w", err)
 		}
 		return pub, priv, nil
+	case "age":
+		if fips.Enabled() {
+			return nil, nil, errors.New("age key processing is disabled in FIPS Mode")
+		}
+		identity, err := generateAgeIdentity(random)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to generate age identity: %w", err)
+		}
+		return identity.Recipient().String(), identity.String(), nil
+	case "wireguard", "wg":
+		if fips.Enabled() {
+			return nil, nil, errors.New("wireguard key processing is disabled in FIPS Mode")
+		}
+		return generateWireGuardKeyPair(random)
+	case "ssh:ed25519", "ssh:ecdsa", "ssh:rsa":
+		return generateOpenSSHKeyPair(random, keyType)
+	default:
+		return nil, nil, fmt.Errorf("invalid keytype (%s) [(rsa, rsa:normal, rsa:2048), (rsa:strong, rsa:4096), (ec, ec:normal, ec:p256), (ec:high, ec:p384), (ec:strong, ec:p521), (ssh, ed25519), (naclbox, x25519), age, (wireguard, wg), ssh:ed25519, ssh:ecdsa, ssh:rsa]", keyType)
+	}
+}
+
+// generateWireGuardKeyPair generates a base64 encoded Curve25519 key pair
+// compatible with `wg genkey` / `wg pubkey`.
+func generateWireGuardKeyPair(random io.Reader) (publicKey, privateKey string, err error) {
+	var priv [curve25519.ScalarSize]byte
+	if _, err := io.ReadFull(keySource(random), priv[:]); err != nil {
+		return "", "", fmt.Errorf("unable to generate wireguard private key: %w", err)
+	}
+
+	// Clamp the scalar
+	priv[0] &= 248
+	priv[31] = (priv[31] & 127) | 64
+
+	pub, err := curve25519.X25519(priv[:], curve25519.Basepoint)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to derive wireguard public key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv[:]), nil
+}
+
+// generateOpenSSHKeyPair generates a key pair encoded as an OpenSSH private
+// key and an authorized_keys public key.
+func generateOpenSSHKeyPair(random io.Reader, keyType string) (publicKey, privateKey string, err error) {
+	var pub, priv interface{}
+	switch keyType {
+	case "ssh:ed25519":
+		pub, priv, err = generateKeyPair(random, "ed25519")
+	case "ssh:ecdsa":
+		pub, priv, err = generateKeyPair(random, "ec:p256")
+	case "ssh:rsa":
+		var key *rsa.PrivateKey
+		if key, err = generateRSAKey(random, 3072); err == nil {
+			pub, priv = key.Public(), key
+		}
 	default:
-		return nil, nil, fmt.Errorf("invalid keytype (%s) [(rsa, rsa:normal, rsa:2048), (rsa:strong, rsa:4096), (ec, ec:normal, ec:p256), (ec:high, ec:p384), (ec:strong, ec:p521), (ssh, ed25519), (naclbox, x25519)]", keyType)
+		err = fmt.Errorf("unsupported OpenSSH key type '%s'", keyType)
+	}
+	if err != nil {
+		return "", "", err
 	}
+
+	// Encode keys
+	if privateKey, err = ToSSH(priv); err != nil {
+		return "", "", err
+	}
+	if privateKey, err = setOpenSSHCheck(random, privateKey); err != nil {
+		return "", "", err
+	}
+	if publicKey, err = ToSSH(pub); err != nil {
+		return "", "", err
+	}
+
+	// No error
+	return publicKey, privateKey, nil
+}
+
+// generateAgeIdentity generates an age X25519 identity.
+func generateAgeIdentity(random io.Reader) (*age.X25519Identity, error) {
+	if !deriveFrom(random) {
+		return age.GenerateX25519Identity()
+	}
+
+	// Encode the random scalar as an age identity
+	var scalar [curve25519.ScalarSize]byte
+	if _, err := io.ReadFull(random, scalar[:]); err != nil {
+		return nil, fmt.Errorf("unable to generate age identity: %w", err)
+	}
+	encoded, err := bech32.Encode("AGE-SECRET-KEY-", scalar[:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode age identity: %w", err)
+	}
+
+	return age.ParseX25519Identity(encoded)
 }
@@ -69,17 +69,17 @@ func TestAlgorithmForECDSACurve(t *testing.T) {
 
 func TestAlgorithmForKey(t *testing.T) {
 	// Generate test keys
-	rsaPriv, rsaPub, err := generateKeyPair("rsa")
+	rsaPriv, rsaPub, err := generateKeyPair(cryptorand.Reader, "rsa")
 	if err != nil {
 		t.Fatalf("unable to generate rsa key: %v", err)
 	}
 
-	ecPriv, ecPub, err := generateKeyPair("ec")
+	ecPriv, ecPub, err := generateKeyPair(cryptorand.Reader, "ec")
 	if err != nil {
 		t.Fatalf("unable to generate ec key: %v", err)
 	}
 
-	edPriv, edPub, err := generateKeyPair("ssh")
+	edPriv, edPub, err := generateKeyPair(cryptorand.Reader, "ssh")
 	if err != nil {
 		t.Fatalf("unable to generate ed25519 key: %v", err)
 	}
@@ -136,7 +136,7 @@ func TestAlgorithmForKey(t *testing.T) {
 }
 
 func TestToJWK(t *testing.T) {
-	priv, pub, err := generateKeyPair("rsa")
+	priv, pub, err := generateKeyPair(cryptorand.Reader, "rsa")
 	if err != nil {
 		t.Error("unable to generate rsa key")
 		return
@@ -235,7 +235,7 @@ func TestToJWK_AlgorithmField(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Generate key pair
-			pub, priv, err := generateKeyPair(tt.keyType)
+			pub, priv, err := generateKeyPair(cryptorand.Reader, tt.keyType)
 			if err != nil {
 				t.Fatalf("unable to generate %s key: %v", tt.keyType, err)
 			}
@@ -450,19 +450,19 @@ func TestToJWK_Ed25519KeyTypes(t *testing.T) {
 }
 
 func TestToPEM(t *testing.T) {
-	rsaPriv, rsaPub, err := generateKeyPair("rsa")
+	rsaPriv, rsaPub, err := generateKeyPair(cryptorand.Reader, "rsa")
 	if err != nil {
 		t.Error("unable to generate rsa key")
 		return
 	}
 
-	ecPriv, ecPub, err := generateKeyPair("ec")
+	ecPriv, ecPub, err := generateKeyPair(cryptorand.Reader, "ec")
 	if err != nil {
 		t.Error("unable to generate ec key")
 		return
 	}
 
-	edPriv, edPub, err := generateKeyPair("ssh")
+	edPriv, edPub, err := generateKeyPair(cryptorand.Reader, "ssh")
 	if err != nil {
 		t.Error("unable to generate ssh key")
 		return
@@ -522,7 +522,7 @@ func TestToPEM(t *testing.T) {
 }
 
 func TestEncryptPEM(t *testing.T) {
-	_, rsaPriv, err := generateKeyPair("rsa")
+	_, rsaPriv, err := generateKeyPair(cryptorand.Reader, "rsa")
 	if err != nil {
 		t.Error("unable to generate rsa key")
 		return
@@ -594,19 +594,19 @@ func TestEncryptPEM(t *testing.T) {
 }
 
 func TestToSSH(t *testing.T) {
-	rsaPub, rsaPriv, err := generateKeyPair("rsa")
+	rsaPub, rsaPriv, err := generateKeyPair(cryptorand.Reader, "rsa")
 	if err != nil {
 		t.Error("unable to generate rsa key")
 		return
 	}
 
-	ecPub, ecPriv, err := generateKeyPair("ec")
+	ecPub, ecPriv, err := generateKeyPair(cryptorand.Reader, "ec")
 	if err != nil {
 		t.Error("unable to generate ec key")
 		return
 	}
 
-	edPub, edPriv, err := generateKeyPair("ssh")
+	edPub, edPriv, err := generateKeyPair(cryptorand.Reader, "ssh")
 	if err != nil {
 		t.Error("unable to generate ssh key")
 		return
@@ -764,7 +764,7 @@ func Test_EncryptDecryptJWE(t *testing.T) {
 
 func TestToJWS(t *testing.T) {
 
-	_, ecPriv, err := generateKeyPair("ec")
+	_, ecPriv, err := generateKeyPair(cryptorand.Reader, "ec")
 	if err != nil {
 		t.Error("unable to generate ec key")
 		return
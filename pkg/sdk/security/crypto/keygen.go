@@ -0,0 +1,255 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/elastic/harp/build/testbuild"
+)
+
+// Since Go 1.26, the standard library key generators ignore custom random
+// sources. In test builds, keys are derived from the random source when it is
+// not the system CSPRNG, so that a deterministic random source produces
+// deterministic keys. Other builds always use the system CSPRNG.
+
+// deriveFrom returns true if keys must be derived from the given random source
+// instead of the standard library generators.
+func deriveFrom(random io.Reader) bool {
+	return random != rand.Reader && testbuild.Enabled()
+}
+
+// keySource returns the random source used to generate keys.
+func keySource(random io.Reader) io.Reader {
+	if !deriveFrom(random) {
+		return rand.Reader
+	}
+	return random
+}
+
+// generateRSAKey generates a RSA private key of the given size.
+func generateRSAKey(random io.Reader, bits int) (*rsa.PrivateKey, error) {
+	if !deriveFrom(random) {
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+
+	e := big.NewInt(65537)
+	one := big.NewInt(1)
+	for {
+		// Generate primes
+		p, err := generatePrime(random, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := generatePrime(random, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		// Check modulus size
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+
+		// Compute private exponent
+		phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d := new(big.Int).ModInverse(e, phi)
+		if d == nil {
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: n,
+				E: int(e.Int64()),
+			},
+			D:      d,
+			Primes: []*big.Int{p, q},
+		}
+		key.Precompute()
+		if err := key.Validate(); err != nil {
+			return nil, fmt.Errorf("unable to validate rsa key: %w", err)
+		}
+
+		// No error
+		return key, nil
+	}
+}
+
+// generatePrime returns a prime of the given size with the two most
+// significant bits set.
+func generatePrime(random io.Reader, bits int) (*big.Int, error) {
+	b := uint(bits % 8)
+	if b == 0 {
+		b = 8
+	}
+
+	buf := make([]byte, (bits+7)/8)
+	p := new(big.Int)
+	for {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, fmt.Errorf("unable to generate prime: %w", err)
+		}
+
+		// Clear bits in the first byte to make sure the candidate has a size <= bits.
+		buf[0] &= uint8(int(1<<b) - 1)
+		// Set the top two bits so that the product of two primes has the expected size.
+		if b >= 2 {
+			buf[0] |= 3 << (b - 2)
+		} else {
+			buf[0] |= 1
+			if len(buf) > 1 {
+				buf[1] |= 0x80
+			}
+		}
+		// Make the value odd.
+		buf[len(buf)-1] |= 1
+
+		p.SetBytes(buf)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// generateECDSAKey generates an ECDSA private key for the given curve.
+func generateECDSAKey(random io.Reader, curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	if !deriveFrom(random) {
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	}
+
+	var c ecdh.Curve
+	switch curve {
+	case elliptic.P256():
+		c = ecdh.P256()
+	case elliptic.P384():
+		c = ecdh.P384()
+	case elliptic.P521():
+		c = ecdh.P521()
+	default:
+		return nil, errors.New("unsupported curve")
+	}
+
+	bitSize := curve.Params().BitSize
+	buf := make([]byte, (bitSize+7)/8)
+	for {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, fmt.Errorf("unable to generate ecdsa key: %w", err)
+		}
+
+		// Clear extra bits to reduce candidate rejection
+		buf[0] &= 0xff >> (len(buf)*8 - bitSize)
+
+		// Rejected if the scalar is zero or not lower than the curve order
+		priv, err := c.NewPrivateKey(buf)
+		if err != nil {
+			continue
+		}
+
+		// Decode uncompressed public point
+		pub := priv.PublicKey().Bytes()
+		size := (len(pub) - 1) / 2
+
+		// No error
+		return &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).SetBytes(pub[1 : 1+size]),
+				Y:     new(big.Int).SetBytes(pub[1+size:]),
+			},
+			D: new(big.Int).SetBytes(buf),
+		}, nil
+	}
+}
+
+// generateEd25519Key generates an Ed25519 key pair.
+func generateEd25519Key(random io.Reader) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	if !deriveFrom(random) {
+		return ed25519.GenerateKey(rand.Reader)
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, fmt.Errorf("unable to generate ed25519 key: %w", err)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+
+	// No error
+	return priv.Public().(ed25519.PublicKey), priv, nil
+}
+
+// setOpenSSHCheck replaces the random check value of an unencrypted OpenSSH
+// private key by one drawn from the given random source.
+func setOpenSSHCheck(random io.Reader, privateKey string) (string, error) {
+	if !deriveFrom(random) {
+		return privateKey, nil
+	}
+
+	// Decode PEM block
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return "", errors.New("unable to decode OpenSSH private key")
+	}
+
+	// Skip magic, cipher name, kdf name, kdf options, key count and public key
+	const magic = "openssh-key-v1\x00"
+	if len(block.Bytes) < len(magic) {
+		return "", errors.New("invalid OpenSSH private key")
+	}
+	offset := len(magic)
+	for i := 0; i < 5; i++ {
+		if len(block.Bytes) < offset+4 {
+			return "", errors.New("invalid OpenSSH private key")
+		}
+		if i == 3 {
+			// Key count is not length prefixed
+			offset += 4
+			continue
+		}
+		offset += 4 + int(binary.BigEndian.Uint32(block.Bytes[offset:]))
+	}
+
+	// Overwrite check values (private block length + 2 x uint32)
+	if len(block.Bytes) < offset+12 {
+		return "", errors.New("invalid OpenSSH private key")
+	}
+	var check [4]byte
+	if _, err := io.ReadFull(random, check[:]); err != nil {
+		return "", fmt.Errorf("unable to generate OpenSSH check value: %w", err)
+	}
+	copy(block.Bytes[offset+4:], check[:])
+	copy(block.Bytes[offset+8:], check[:])
+
+	// No error
+	return string(pem.EncodeToMemory(block)), nil
+}
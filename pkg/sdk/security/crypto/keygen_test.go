@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	mathrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/elastic/harp/build/testbuild"
+)
+
+func TestGenerateKeypair_Deterministic(t *testing.T) {
+	keyTypes := []string{
+		"rsa", "ec:p256", "ec:p384", "ec:p521", "ed25519", "x25519",
+		"age", "wireguard", "ssh:ed25519", "ssh:ecdsa",
+	}
+
+	for _, keyType := range keyTypes {
+		t.Run(keyType, func(t *testing.T) {
+			//nolint:gosec // deterministic random source for test
+			first, err := GenerateKeypair(mathrand.New(mathrand.NewSource(1)), keyType)
+			require.NoError(t, err)
+			//nolint:gosec // deterministic random source for test
+			second, err := GenerateKeypair(mathrand.New(mathrand.NewSource(1)), keyType)
+			require.NoError(t, err)
+
+			// Keys are derived from custom random sources in test builds only
+			if testbuild.Enabled() {
+				assert.Equal(t, first, second)
+
+				//nolint:gosec // deterministic random source for test
+				other, err := GenerateKeypair(mathrand.New(mathrand.NewSource(2)), keyType)
+				require.NoError(t, err)
+				assert.NotEqual(t, first, other)
+			} else {
+				assert.NotEqual(t, first, second)
+			}
+
+			// Check generated keys
+			kp, ok := first.(struct {
+				Private interface{}
+				Public  interface{}
+			})
+			require.True(t, ok)
+
+			if strings.HasPrefix(keyType, "ssh:") {
+				_, err = ssh.ParseRawPrivateKey([]byte(kp.Private.(string)))
+				require.NoError(t, err)
+			}
+
+			digest := sha256.Sum256([]byte("test"))
+			switch priv := kp.Private.(type) {
+			case *rsa.PrivateKey:
+				require.NoError(t, priv.Validate())
+				sig, err := rsa.SignPKCS1v15(nil, priv, crypto.SHA256, digest[:])
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPKCS1v15(kp.Public.(*rsa.PublicKey), crypto.SHA256, digest[:], sig))
+			case *ecdsa.PrivateKey:
+				assert.True(t, priv.Curve.IsOnCurve(priv.X, priv.Y))
+				sig, err := ecdsa.SignASN1(mathrand.New(mathrand.NewSource(1)), priv, digest[:]) //nolint:gosec // test only
+				require.NoError(t, err)
+				assert.True(t, ecdsa.VerifyASN1(kp.Public.(*ecdsa.PublicKey), digest[:], sig))
+			case ed25519.PrivateKey:
+				assert.True(t, ed25519.Verify(kp.Public.(ed25519.PublicKey), digest[:], ed25519.Sign(priv, digest[:])))
+			}
+		})
+	}
+}
+
+func TestGenerateKey_Deterministic(t *testing.T) {
+	for _, keyType := range []string{"aes:128", "aes:256", "chacha20", "fernet"} {
+		t.Run(keyType, func(t *testing.T) {
+			//nolint:gosec // deterministic random source for test
+			first, err := GenerateKey(mathrand.New(mathrand.NewSource(1)), keyType)
+			require.NoError(t, err)
+			//nolint:gosec // deterministic random source for test
+			second, err := GenerateKey(mathrand.New(mathrand.NewSource(1)), keyType)
+			require.NoError(t, err)
+			assert.Equal(t, first, second)
+		})
+	}
+}
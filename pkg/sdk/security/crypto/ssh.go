@@ -0,0 +1,383 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/elastic/harp/build/fips"
+	"github.com/elastic/harp/pkg/sdk/types"
+)
+
+const (
+	defaultSSHUserValidity = 24 * time.Hour
+	defaultSSHHostValidity = 365 * 24 * time.Hour
+)
+
+// Extensions granted by ssh-keygen to user certificates by default.
+var defaultSSHUserExtensions = []string{
+	"permit-X11-forwarding",
+	"permit-agent-forwarding",
+	"permit-port-forwarding",
+	"permit-pty",
+	"permit-user-rc",
+}
+
+// SSHUserCertificate signs the given user public key with the SSH certificate
+// authority key and returns the OpenSSH certificate (`-cert.pub` content).
+//
+// Supported options:
+//   - principals: user names allowed to authenticate with the certificate (required)
+//   - validity: certificate lifetime as a duration (default: 24h)
+//   - criticalOptions: critical options map (force-command, source-address)
+//   - extensions: extension list or map (default: ssh-keygen default extensions)
+//   - serial: certificate serial number (default: random)
+func SSHUserCertificate(caKey, publicKey interface{}, keyID string, opts ...map[string]interface{}) (string, error) {
+	return sshCertificate(ssh.UserCert, caKey, publicKey, keyID, opts...)
+}
+
+// SSHHostCertificate signs the given host public key with the SSH certificate
+// authority key and returns the OpenSSH certificate (`-cert.pub` content).
+//
+// Supported options are `principals` (host names, required), `validity`
+// (default: 8760h) and `serial`.
+func SSHHostCertificate(caKey, publicKey interface{}, keyID string, opts ...map[string]interface{}) (string, error) {
+	return sshCertificate(ssh.HostCert, caKey, publicKey, keyID, opts...)
+}
+
+// SSHKnownHostsCA returns the known_hosts line used to trust host certificates
+// signed by the given certificate authority for the given host patterns
+// (default: *).
+func SSHKnownHostsCA(caKey interface{}, hostPatterns ...string) (string, error) {
+	// Check arguments
+	if len(hostPatterns) == 0 {
+		hostPatterns = []string{"*"}
+	}
+	for _, pattern := range hostPatterns {
+		if pattern == "" || strings.ContainsAny(pattern, " \t\r\n,") {
+			return "", fmt.Errorf("invalid host pattern %q", pattern)
+		}
+	}
+
+	// Extract CA public key
+	pub, err := sshPublicKey(caKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode certificate authority key: %w", err)
+	}
+
+	// No error
+	return fmt.Sprintf("@cert-authority %s %s", strings.Join(hostPatterns, ","), ssh.MarshalAuthorizedKey(pub)), nil
+}
+
+// SSHTrustedUserCAKey returns the certificate authority public key line to
+// add to the sshd TrustedUserCAKeys file.
+func SSHTrustedUserCAKey(caKey interface{}) (string, error) {
+	// Extract CA public key
+	pub, err := sshPublicKey(caKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode certificate authority key: %w", err)
+	}
+
+	// No error
+	return string(ssh.MarshalAuthorizedKey(pub)), nil
+}
+
+// -----------------------------------------------------------------------------
+
+type sshCertificateOptions struct {
+	principals      []string
+	validity        time.Duration
+	criticalOptions map[string]string
+	extensions      map[string]string
+	serial          *uint64
+}
+
+func sshCertificate(certType uint32, caKey, publicKey interface{}, keyID string, opts ...map[string]interface{}) (string, error) {
+	// Check arguments
+	if keyID == "" {
+		return "", errors.New("unable to sign a SSH certificate without key identifier")
+	}
+
+	// Parse options
+	o, err := parseSSHCertificateOptions(certType, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare the certificate authority signer
+	signer, err := sshSigner(caKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode certificate authority key: %w", err)
+	}
+
+	// Decode the key to certify
+	pub, err := sshPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode public key: %w", err)
+	}
+	if _, ok := pub.(*ssh.Certificate); ok {
+		return "", errors.New("unable to certify a SSH certificate")
+	}
+
+	// Generate serial number
+	serial, err := o.serialNumber()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	cert := &ssh.Certificate{
+		Key:             pub,
+		Serial:          serial,
+		CertType:        certType,
+		KeyId:           keyID,
+		ValidPrincipals: o.principals,
+		ValidAfter:      uint64(now.Add(-clockSkewTolerance).Unix()),
+		ValidBefore:     uint64(now.Add(o.validity).Unix()),
+		Permissions: ssh.Permissions{
+			CriticalOptions: o.criticalOptions,
+			Extensions:      o.extensions,
+		},
+	}
+
+	// Sign the certificate
+	if err := cert.SignCert(rand.Reader, signer); err != nil {
+		return "", fmt.Errorf("unable to sign SSH certificate: %w", err)
+	}
+
+	// No error
+	return string(ssh.MarshalAuthorizedKey(cert)), nil
+}
+
+//nolint:gocyclo // options parsing
+func parseSSHCertificateOptions(certType uint32, opts ...map[string]interface{}) (*sshCertificateOptions, error) {
+	o := &sshCertificateOptions{
+		validity: defaultSSHHostValidity,
+	}
+	if certType == ssh.UserCert {
+		o.validity = defaultSSHUserValidity
+		o.extensions = map[string]string{}
+		for _, ext := range defaultSSHUserExtensions {
+			o.extensions[ext] = ""
+		}
+	}
+
+	for _, opt := range opts {
+		for k, v := range opt {
+			var err error
+			switch k {
+			case "principals":
+				o.principals, err = stringsOption(v)
+			case "validity":
+				var raw string
+				if raw, err = stringOption(v); err == nil {
+					o.validity, err = time.ParseDuration(raw)
+					if err == nil && o.validity <= 0 {
+						err = errors.New("validity must be positive")
+					}
+				}
+			case "criticalOptions":
+				if certType != ssh.UserCert {
+					err = errors.New("only supported for user certificates")
+					break
+				}
+				o.criticalOptions, err = stringMapOption(v)
+			case "extensions":
+				if certType != ssh.UserCert {
+					err = errors.New("only supported for user certificates")
+					break
+				}
+				o.extensions, err = stringMapOption(v)
+			case "serial":
+				var serial int
+				if serial, err = intOption(v); err == nil {
+					if serial < 0 {
+						err = errors.New("serial must be positive")
+						break
+					}
+					s := uint64(serial)
+					o.serial = &s
+				}
+			default:
+				err = errors.New("unsupported option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid SSH certificate option '%s': %w", k, err)
+			}
+		}
+	}
+
+	// Empty principals are valid for any user or host
+	if len(o.principals) == 0 {
+		return nil, errors.New("unable to sign a SSH certificate without principals")
+	}
+	for _, principal := range o.principals {
+		if strings.TrimSpace(principal) == "" {
+			return nil, errors.New("invalid SSH certificate option 'principals': blank principal")
+		}
+	}
+
+	// No error
+	return o, nil
+}
+
+func (o *sshCertificateOptions) serialNumber() (uint64, error) {
+	if o.serial != nil {
+		return *o.serial, nil
+	}
+
+	var raw [8]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return 0, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
+	return binary.BigEndian.Uint64(raw[:]), nil
+}
+
+func sshSigner(key interface{}) (ssh.Signer, error) {
+	// Check arguments
+	if types.IsNil(key) {
+		return nil, errors.New("key is nil")
+	}
+
+	var (
+		signer ssh.Signer
+		err    error
+	)
+	switch k := key.(type) {
+	case ssh.Signer:
+		signer = k
+	case string:
+		signer, err = ssh.ParsePrivateKey([]byte(k))
+	case []byte:
+		signer, err = ssh.ParsePrivateKey(k)
+	default:
+		signer, err = ssh.NewSignerFromKey(k)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize SSH signer: %w", err)
+	}
+	if err := checkSSHKeyType(signer.PublicKey()); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+func sshPublicKey(key interface{}) (ssh.PublicKey, error) {
+	// Check arguments
+	if types.IsNil(key) {
+		return nil, errors.New("key is nil")
+	}
+
+	var (
+		pub ssh.PublicKey
+		err error
+	)
+	switch k := key.(type) {
+	case ssh.PublicKey:
+		pub = k
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		pub, err = ssh.NewPublicKey(k)
+	case string:
+		pub, err = parseSSHPublicKey([]byte(k))
+	case []byte:
+		pub, err = parseSSHPublicKey(k)
+	default:
+		// Private key
+		var signer ssh.Signer
+		if signer, err = sshSigner(k); err == nil {
+			pub = signer.PublicKey()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSSHKeyType(pub); err != nil {
+		return nil, err
+	}
+
+	return pub, nil
+}
+
+func parseSSHPublicKey(raw []byte) (ssh.PublicKey, error) {
+	// Authorized key format
+	if pub, _, _, _, err := ssh.ParseAuthorizedKey(raw); err == nil {
+		return pub, nil
+	}
+
+	// Fallback to private key
+	signer, err := sshSigner(raw)
+	if err != nil {
+		return nil, errors.New("unable to decode the key as SSH public or private key")
+	}
+
+	return signer.PublicKey(), nil
+}
+
+func checkSSHKeyType(pub ssh.PublicKey) error {
+	keyType := pub.Type()
+	if cert, ok := pub.(*ssh.Certificate); ok {
+		keyType = cert.Key.Type()
+	}
+	if keyType == ssh.KeyAlgoED25519 && fips.Enabled() {
+		return errors.New("ed25519 key processing is disabled in FIPS Mode")
+	}
+
+	return nil
+}
+
+func stringMapOption(v interface{}) (map[string]string, error) {
+	res := map[string]string{}
+
+	switch values := v.(type) {
+	case map[string]string:
+		for k, item := range values {
+			res[k] = item
+		}
+	case map[string]interface{}:
+		for k, item := range values {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string value for '%s', got %T", k, item)
+			}
+			res[k] = s
+		}
+	default:
+		// Value-less items
+		items, err := stringsOption(v)
+		if err != nil {
+			return nil, fmt.Errorf("expected a string map or a string list, got %T", v)
+		}
+		for _, k := range items {
+			res[k] = ""
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,312 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func testSSHKeyPair(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pub, err := ToSSH(&pk.PublicKey)
+	require.NoError(t, err)
+
+	return pk, pub
+}
+
+func parseSSHCertificate(t *testing.T, raw string) *ssh.Certificate {
+	t.Helper()
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(raw))
+	require.NoError(t, err)
+
+	cert, ok := pub.(*ssh.Certificate)
+	require.True(t, ok)
+
+	return cert
+}
+
+func TestSSHUserCertificate(t *testing.T) {
+	_, caPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	caPEM, err := ToSSH(caPriv)
+	require.NoError(t, err)
+	caPub, err := ssh.NewPublicKey(caPriv.Public())
+	require.NoError(t, err)
+	_, userPub := testSSHKeyPair(t)
+
+	tests := []struct {
+		name    string
+		caKey   interface{}
+		pubKey  interface{}
+		keyID   string
+		opts    []map[string]interface{}
+		wantErr bool
+		check   func(*testing.T, *ssh.Certificate)
+	}{
+		{
+			name:    "nil ca key",
+			pubKey:  userPub,
+			keyID:   "alice",
+			wantErr: true,
+		},
+		{
+			name:    "blank key id",
+			caKey:   caPriv,
+			pubKey:  userPub,
+			wantErr: true,
+		},
+		{
+			name:    "invalid public key",
+			caKey:   caPriv,
+			pubKey:  "ssh-ed25519 invalid",
+			keyID:   "alice",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported option",
+			caKey:   caPriv,
+			pubKey:  userPub,
+			keyID:   "alice",
+			opts:    []map[string]interface{}{{"foo": "bar"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid validity",
+			caKey:   caPriv,
+			pubKey:  userPub,
+			keyID:   "alice",
+			opts:    []map[string]interface{}{{"validity": "-1h"}},
+			wantErr: true,
+		},
+		{
+			name:    "without principals",
+			caKey:   caPriv,
+			pubKey:  userPub,
+			keyID:   "alice",
+			wantErr: true,
+		},
+		{
+			name:    "blank principal",
+			caKey:   caPriv,
+			pubKey:  userPub,
+			keyID:   "alice",
+			opts:    []map[string]interface{}{{"principals": []string{"alice", ""}}},
+			wantErr: true,
+		},
+		{
+			name:   "default",
+			caKey:  caPEM,
+			pubKey: userPub,
+			keyID:  "alice",
+			opts:   []map[string]interface{}{{"principals": "alice"}},
+			check: func(t *testing.T, cert *ssh.Certificate) {
+				assert.Equal(t, uint32(ssh.UserCert), cert.CertType)
+				assert.Equal(t, "alice", cert.KeyId)
+				assert.Equal(t, []string{"alice"}, cert.ValidPrincipals)
+				assert.Len(t, cert.Extensions, len(defaultSSHUserExtensions))
+				assert.Contains(t, cert.Extensions, "permit-pty")
+				assert.WithinDuration(t, time.Now().Add(defaultSSHUserValidity), time.Unix(int64(cert.ValidBefore), 0), time.Minute)
+			},
+		},
+		{
+			name:   "with options",
+			caKey:  caPriv,
+			pubKey: userPub,
+			keyID:  "alice@bastion",
+			opts: []map[string]interface{}{
+				{
+					"principals":      []interface{}{"alice", "admin"},
+					"validity":        "1h",
+					"criticalOptions": map[string]interface{}{"force-command": "/bin/true", "source-address": "10.0.0.0/8"},
+					"extensions":      []interface{}{"permit-pty"},
+					"serial":          42,
+				},
+			},
+			check: func(t *testing.T, cert *ssh.Certificate) {
+				assert.Equal(t, []string{"alice", "admin"}, cert.ValidPrincipals)
+				assert.Equal(t, uint64(42), cert.Serial)
+				assert.Equal(t, map[string]string{"force-command": "/bin/true", "source-address": "10.0.0.0/8"}, cert.CriticalOptions)
+				assert.Equal(t, map[string]string{"permit-pty": ""}, cert.Extensions)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), time.Unix(int64(cert.ValidBefore), 0), time.Minute)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SSHUserCertificate(tt.caKey, tt.pubKey, tt.keyID, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SSHUserCertificate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cert := parseSSHCertificate(t, got)
+
+			// Validate certificate with the CA
+			checker := ssh.CertChecker{
+				IsUserAuthority: func(auth ssh.PublicKey) bool {
+					return ssh.FingerprintSHA256(auth) == ssh.FingerprintSHA256(caPub)
+				},
+				SupportedCriticalOptions: []string{"force-command", "source-address"},
+			}
+			assert.NoError(t, checker.CheckCert("alice", cert))
+			assert.Error(t, checker.CheckCert("root", cert))
+
+			if tt.check != nil {
+				tt.check(t, cert)
+			}
+		})
+	}
+}
+
+func TestSSHHostCertificate(t *testing.T) {
+	caPriv, caAuthorizedKey := testSSHKeyPair(t)
+	caPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caAuthorizedKey))
+	require.NoError(t, err)
+	hostPriv, _ := testSSHKeyPair(t)
+
+	tests := []struct {
+		name    string
+		opts    []map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:    "without principals",
+			wantErr: true,
+		},
+		{
+			name: "default",
+			opts: []map[string]interface{}{{"principals": "bastion.example.com"}},
+		},
+		{
+			name: "with principals",
+			opts: []map[string]interface{}{
+				{"principals": []string{"bastion.example.com", "10.0.0.1"}, "validity": "720h"},
+			},
+		},
+		{
+			name:    "critical options",
+			opts:    []map[string]interface{}{{"principals": "bastion", "criticalOptions": map[string]string{"force-command": "/bin/true"}}},
+			wantErr: true,
+		},
+		{
+			name:    "extensions",
+			opts:    []map[string]interface{}{{"principals": "bastion", "extensions": "permit-pty"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Host private key is accepted as public key source
+			got, err := SSHHostCertificate(caPriv, hostPriv, "bastion", tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SSHHostCertificate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cert := parseSSHCertificate(t, got)
+			assert.Equal(t, uint32(ssh.HostCert), cert.CertType)
+			assert.Empty(t, cert.Extensions)
+
+			checker := ssh.CertChecker{
+				IsHostAuthority: func(auth ssh.PublicKey, _ string) bool {
+					return ssh.FingerprintSHA256(auth) == ssh.FingerprintSHA256(caPub)
+				},
+			}
+			assert.NoError(t, checker.CheckCert("bastion.example.com", cert))
+		})
+	}
+}
+
+func TestSSHKnownHostsCA(t *testing.T) {
+	caPriv, caPub := testSSHKeyPair(t)
+
+	tests := []struct {
+		name     string
+		caKey    interface{}
+		patterns []string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name:     "invalid pattern",
+			caKey:    caPriv,
+			patterns: []string{"foo bar"},
+			wantErr:  true,
+		},
+		{
+			name:  "default pattern",
+			caKey: caPriv,
+			want:  "@cert-authority * " + caPub,
+		},
+		{
+			name:     "patterns from public key",
+			caKey:    caPub,
+			patterns: []string{"*.example.com", "10.0.0.*"},
+			want:     "@cert-authority *.example.com,10.0.0.* " + caPub,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SSHKnownHostsCA(tt.caKey, tt.patterns...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SSHKnownHostsCA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSSHTrustedUserCAKey(t *testing.T) {
+	caPriv, caPub := testSSHKeyPair(t)
+	caPEM, err := ToSSH(caPriv)
+	require.NoError(t, err)
+
+	for _, key := range []interface{}{caPriv, &caPriv.PublicKey, caPEM, caPub} {
+		got, err := SSHTrustedUserCAKey(key)
+		assert.NoError(t, err)
+		assert.Equal(t, caPub, got)
+	}
+
+	_, err = SSHTrustedUserCAKey("invalid")
+	assert.Error(t, err)
+}
@@ -18,10 +18,11 @@
 package crypto
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"io"
 
-	"github.com/awnumar/memguard"
 	"github.com/fernet/fernet-go"
 	"github.com/pkg/errors"
 
@@ -32,42 +33,58 @@ import (
 
 // Key generates symmetric encryption keys according to given keyType.
 func Key(keyType string) (string, error) {
+	return GenerateKey(rand.Reader, keyType)
+}
+
+// GenerateKey generates symmetric encryption keys according to given keyType
+// using the given random source.
+func GenerateKey(random io.Reader, keyType string) (string, error) {
 	switch keyType {
 	case "aes:128":
-		key := memguard.NewBufferRandom(16).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 16)
 	case "aes:192":
-		key := memguard.NewBufferRandom(24).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 24)
 	case "aes:256":
-		key := memguard.NewBufferRandom(32).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 32)
 	case "aes:siv":
 		if fips.Enabled() {
 			return "", errors.New("aes:siv key generation is disabled in FIPS Mode")
 		}
-		key := memguard.NewBufferRandom(64).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 64)
 	case "secretbox":
 		if fips.Enabled() {
 			return "", errors.New("secretbox key generation is disabled in FIPS Mode")
 		}
-		key := memguard.NewBufferRandom(32).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 32)
 	case "chacha20":
 		if fips.Enabled() {
 			return "", errors.New("chacha20 key generation is disabled in FIPS Mode")
 		}
-		key := memguard.NewBufferRandom(32).Bytes()
-		return base64.StdEncoding.EncodeToString(key), nil
+		return randomKey(random, 32)
 	case "fernet":
 		// Generate a fernet key
 		k := &fernet.Key{}
-		if err := k.Generate(); err != nil {
-			return "", err
+		if _, err := io.ReadFull(random, k[:]); err != nil {
+			return "", fmt.Errorf("unable to generate fernet key: %w", err)
 		}
 		return k.Encode(), nil
+	case "wireguard:psk", "wg:psk":
+		if fips.Enabled() {
+			return "", errors.New("wireguard preshared key generation is disabled in FIPS Mode")
+		}
+		return randomKey(random, 32)
 	default:
-		return "", fmt.Errorf("invalid keytype (%s) [aes:128, aes:192, aes:256, aes:siv, secretbox, chacha20, fernet]", keyType)
+		return "", fmt.Errorf("invalid keytype (%s) [aes:128, aes:192, aes:256, aes:siv, secretbox, chacha20, fernet, wireguard:psk]", keyType)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func randomKey(random io.Reader, size int) (string, error) {
+	key := make([]byte, size)
+	if _, err := io.ReadFull(random, key); err != nil {
+		return "", fmt.Errorf("unable to generate key: %w", err)
 	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
 }
@@ -56,6 +56,11 @@ func TestKey(t *testing.T) {
 			args:    "fernet",
 			wantErr: false,
 		},
+		{
+			name:    "wireguard-psk",
+			args:    "wireguard:psk",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
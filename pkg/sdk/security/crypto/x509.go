@@ -0,0 +1,592 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.step.sm/crypto/pemutil"
+
+	"github.com/elastic/harp/pkg/sdk/types"
+)
+
+const (
+	defaultCAValidity   = 10 * 365 * 24 * time.Hour
+	defaultLeafValidity = 365 * 24 * time.Hour
+	defaultX509KeyType  = "ec"
+	serialNumberBits    = 128
+	clockSkewTolerance  = 5 * time.Minute
+)
+
+// Certificate holds a generated certificate, its key pair and the issuer
+// chain.
+type Certificate struct {
+	Certificate *x509.Certificate
+	Private     interface{}
+	Public      interface{}
+	// Chain holds the issuer certificates, from the direct issuer to the
+	// root.
+	Chain []*x509.Certificate
+}
+
+// X509SelfSignedCA generates a self-signed certificate authority.
+//
+// Supported options are described by X509IssueCertificate.
+func X509SelfSignedCA(commonName string, opts ...map[string]interface{}) (*Certificate, error) {
+	// Parse options
+	o, err := parseCertificateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Self-signed certificates are always CA
+	o.isCA = true
+
+	// Generate key pair
+	pub, priv, err := generateSignerKeyPair(o.keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare certificate template
+	tmpl, err := o.template(commonName, pub, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Self-sign the certificate
+	cert, err := createCertificate(tmpl, tmpl, pub, priv)
+	if err != nil {
+		return nil, err
+	}
+
+	// No error
+	return &Certificate{
+		Certificate: cert,
+		Private:     priv,
+		Public:      pub,
+		Chain:       []*x509.Certificate{},
+	}, nil
+}
+
+// X509LoadCA decodes a certificate authority from its PEM encoded certificate
+// (optionally followed by its issuer chain) and private key, as stored in a
+// bundle secret.
+func X509LoadCA(certPEM, keyPEM string) (*Certificate, error) {
+	// Decode certificates
+	certs, err := pemutil.ParseCertificateBundle([]byte(certPEM))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode CA certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("unable to decode CA certificate: no certificate found")
+	}
+	if !certs[0].IsCA {
+		return nil, errors.New("the given certificate is not a certificate authority")
+	}
+
+	// Decode private key
+	key, err := pemutil.Parse([]byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode CA private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CA private key type %T can't be used to sign", key)
+	}
+
+	// Ensure key pair consistency
+	if !publicKeyEqual(signer.Public(), certs[0].PublicKey) {
+		return nil, errors.New("CA private key doesn't match the certificate public key")
+	}
+
+	// No error
+	return &Certificate{
+		Certificate: certs[0],
+		Private:     signer,
+		Public:      signer.Public(),
+		Chain:       certs[1:],
+	}, nil
+}
+
+// X509IssueCertificate generates a key pair and issues a certificate signed
+// by the given certificate authority.
+//
+// Supported options:
+//   - keyType: key type supported by cryptoPair (default: ec)
+//   - validity: certificate lifetime as a duration (default: 8760h, 87600h for CA)
+//   - sans: subject alternative names (DNS names, IP addresses, emails and URIs)
+//   - keyUsages: key usages and extended key usages
+//   - isCA: issue an intermediate certificate authority
+//   - pathLen: maximum intermediate CA count below this CA
+//   - organization, organizationalUnit, country, province, locality: subject attributes
+func X509IssueCertificate(ca *Certificate, commonName string, opts ...map[string]interface{}) (*Certificate, error) {
+	// Check arguments
+	if err := checkIssuer(ca); err != nil {
+		return nil, err
+	}
+
+	// Parse options
+	o, err := parseCertificateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate key pair
+	pub, priv, err := generateSignerKeyPair(o.keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare certificate template
+	tmpl, err := o.template(commonName, pub, ca.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the certificate
+	cert, err := createCertificate(tmpl, ca.Certificate, pub, ca.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	// No error
+	return &Certificate{
+		Certificate: cert,
+		Private:     priv,
+		Public:      pub,
+		Chain:       append([]*x509.Certificate{ca.Certificate}, ca.Chain...),
+	}, nil
+}
+
+// X509SignCSR issues a certificate for the given PEM encoded certificate
+// signing request. The subject and the subject alternative names are copied
+// from the request, additional names can be provided with `sans` option.
+func X509SignCSR(ca *Certificate, csrPEM string, opts ...map[string]interface{}) (*Certificate, error) {
+	// Check arguments
+	if err := checkIssuer(ca); err != nil {
+		return nil, err
+	}
+
+	// Decode the request
+	csr, err := pemutil.ParseCertificateRequest([]byte(csrPEM))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode certificate request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+
+	// Parse options
+	o, err := parseCertificateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare certificate template
+	tmpl, err := o.template(csr.Subject.CommonName, csr.PublicKey, ca.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Subject = csr.Subject
+	tmpl.DNSNames = append(tmpl.DNSNames, csr.DNSNames...)
+	tmpl.IPAddresses = append(tmpl.IPAddresses, csr.IPAddresses...)
+	tmpl.EmailAddresses = append(tmpl.EmailAddresses, csr.EmailAddresses...)
+	tmpl.URIs = append(tmpl.URIs, csr.URIs...)
+
+	// Sign the certificate
+	cert, err := createCertificate(tmpl, ca.Certificate, csr.PublicKey, ca.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	// No error
+	return &Certificate{
+		Certificate: cert,
+		Public:      csr.PublicKey,
+		Chain:       append([]*x509.Certificate{ca.Certificate}, ca.Chain...),
+	}, nil
+}
+
+// X509FullChain encodes the given certificates as a PEM bundle. Arguments can
+// be generated certificates (the issuer chain is appended), x509
+// certificates, certificate collections or PEM encoded strings.
+func X509FullChain(items ...interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	encode := func(c *x509.Certificate) error {
+		if c == nil {
+			return errors.New("unable to encode nil certificate")
+		}
+		return pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+
+	for i, item := range items {
+		var certs []*x509.Certificate
+		switch v := item.(type) {
+		case *Certificate:
+			if v == nil {
+				return "", fmt.Errorf("unable to encode nil certificate at index %d", i)
+			}
+			certs = append([]*x509.Certificate{v.Certificate}, v.Chain...)
+		case *x509.Certificate:
+			certs = []*x509.Certificate{v}
+		case []*x509.Certificate:
+			certs = v
+		case string:
+			parsed, err := pemutil.ParseCertificateBundle([]byte(v))
+			if err != nil {
+				return "", fmt.Errorf("unable to decode PEM certificates at index %d: %w", i, err)
+			}
+			certs = parsed
+		default:
+			return "", fmt.Errorf("unsupported certificate type %T at index %d", item, i)
+		}
+
+		for _, c := range certs {
+			if err := encode(c); err != nil {
+				return "", fmt.Errorf("unable to encode certificate at index %d: %w", i, err)
+			}
+		}
+	}
+
+	// No error
+	return buf.String(), nil
+}
+
+// -----------------------------------------------------------------------------
+
+type certificateOptions struct {
+	keyType            string
+	validity           time.Duration
+	sans               []string
+	keyUsages          []string
+	isCA               bool
+	pathLen            int
+	organization       []string
+	organizationalUnit []string
+	country            []string
+	province           []string
+	locality           []string
+}
+
+//nolint:gocyclo // options parsing
+func parseCertificateOptions(opts ...map[string]interface{}) (*certificateOptions, error) {
+	o := &certificateOptions{
+		keyType: defaultX509KeyType,
+		pathLen: -1,
+	}
+
+	for _, opt := range opts {
+		for k, v := range opt {
+			var err error
+			switch k {
+			case "keyType":
+				o.keyType, err = stringOption(v)
+			case "validity":
+				var raw string
+				if raw, err = stringOption(v); err == nil {
+					o.validity, err = time.ParseDuration(raw)
+					if err == nil && o.validity <= 0 {
+						err = errors.New("validity must be positive")
+					}
+				}
+			case "sans":
+				o.sans, err = stringsOption(v)
+			case "keyUsages":
+				o.keyUsages, err = stringsOption(v)
+			case "isCA":
+				var ok bool
+				if o.isCA, ok = v.(bool); !ok {
+					err = fmt.Errorf("expected a boolean, got %T", v)
+				}
+			case "pathLen":
+				o.pathLen, err = intOption(v)
+			case "organization":
+				o.organization, err = stringsOption(v)
+			case "organizationalUnit":
+				o.organizationalUnit, err = stringsOption(v)
+			case "country":
+				o.country, err = stringsOption(v)
+			case "province":
+				o.province, err = stringsOption(v)
+			case "locality":
+				o.locality, err = stringsOption(v)
+			default:
+				err = errors.New("unsupported option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate option '%s': %w", k, err)
+			}
+		}
+	}
+
+	// No error
+	return o, nil
+}
+
+//nolint:gocyclo // certificate template assembly
+func (o *certificateOptions) template(commonName string, pub interface{}, issuer *x509.Certificate) (*x509.Certificate, error) {
+	// Generate serial number
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
+	// Compute validity
+	validity := o.validity
+	if validity == 0 {
+		validity = defaultLeafValidity
+		if o.isCA {
+			validity = defaultCAValidity
+		}
+	}
+	now := time.Now().UTC()
+	notBefore := now.Add(-clockSkewTolerance)
+	notAfter := notBefore.Add(validity)
+	if issuer != nil {
+		if !now.Before(issuer.NotAfter) {
+			return nil, fmt.Errorf("the issuer certificate has expired on %s", issuer.NotAfter.UTC().Format(time.RFC3339))
+		}
+		if notAfter.After(issuer.NotAfter) {
+			// Don't outlive the issuer
+			notAfter = issuer.NotAfter
+		}
+	}
+	if !notAfter.After(notBefore) {
+		return nil, errors.New("the certificate validity period is empty")
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         commonName,
+			Organization:       o.organization,
+			OrganizationalUnit: o.organizationalUnit,
+			Country:            o.country,
+			Province:           o.province,
+			Locality:           o.locality,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  o.isCA,
+	}
+
+	// Path length constraint
+	if o.isCA {
+		pathLen := o.pathLen
+		if issuer != nil {
+			switch {
+			case issuer.MaxPathLenZero:
+				return nil, errors.New("issuer path length constraint doesn't allow intermediate certificate authorities")
+			case issuer.MaxPathLen > 0 && pathLen < 0:
+				pathLen = issuer.MaxPathLen - 1
+			case issuer.MaxPathLen > 0 && pathLen >= issuer.MaxPathLen:
+				return nil, fmt.Errorf("path length must be lower than the issuer path length (%d)", issuer.MaxPathLen)
+			}
+		}
+		if pathLen >= 0 {
+			tmpl.MaxPathLen = pathLen
+			tmpl.MaxPathLenZero = pathLen == 0
+		} else {
+			tmpl.MaxPathLen = -1
+		}
+	} else if o.pathLen >= 0 {
+		return nil, errors.New("path length can only be set on certificate authorities")
+	}
+
+	// Subject alternative names
+	for _, san := range o.sans {
+		switch {
+		case net.ParseIP(san) != nil:
+			tmpl.IPAddresses = append(tmpl.IPAddresses, net.ParseIP(san))
+		case strings.Contains(san, "://"):
+			u, err := url.Parse(san)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URI SAN '%s': %w", san, err)
+			}
+			tmpl.URIs = append(tmpl.URIs, u)
+		case strings.Contains(san, "@"):
+			addr, err := mail.ParseAddress(san)
+			if err != nil {
+				return nil, fmt.Errorf("invalid email SAN '%s': %w", san, err)
+			}
+			tmpl.EmailAddresses = append(tmpl.EmailAddresses, addr.Address)
+		default:
+			tmpl.DNSNames = append(tmpl.DNSNames, san)
+		}
+	}
+
+	// Key usages
+	usages := o.keyUsages
+	if len(usages) == 0 {
+		usages = defaultKeyUsages(o.isCA, pub)
+	}
+	for _, usage := range usages {
+		if ku, ok := keyUsages[usage]; ok {
+			tmpl.KeyUsage |= ku
+			continue
+		}
+		if eku, ok := extKeyUsages[usage]; ok {
+			tmpl.ExtKeyUsage = append(tmpl.ExtKeyUsage, eku)
+			continue
+		}
+		return nil, fmt.Errorf("unsupported key usage '%s'", usage)
+	}
+
+	// No error
+	return tmpl, nil
+}
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"certSign":          x509.KeyUsageCertSign,
+	"crlSign":           x509.KeyUsageCRLSign,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"any":             x509.ExtKeyUsageAny,
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+func defaultKeyUsages(isCA bool, pub interface{}) []string {
+	if isCA {
+		return []string{"certSign", "crlSign", "digitalSignature"}
+	}
+
+	usages := []string{"digitalSignature", "serverAuth", "clientAuth"}
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		usages = append(usages, "keyEncipherment")
+	}
+
+	return usages
+}
+
+func generateSignerKeyPair(keyType string) (crypto.PublicKey, crypto.Signer, error) {
+	pub, priv, err := generateKeyPair(rand.Reader, keyType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate a '%s' key pair: %w", keyType, err)
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("key type '%s' can't be used for certificates", keyType)
+	}
+
+	return pub, signer, nil
+}
+
+func createCertificate(tmpl, parent *x509.Certificate, pub, priv interface{}) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, priv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse created certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+func checkIssuer(ca *Certificate) error {
+	if ca == nil || ca.Certificate == nil {
+		return errors.New("unable to issue a certificate with a nil certificate authority")
+	}
+	if !ca.Certificate.IsCA {
+		return errors.New("the issuer certificate is not a certificate authority")
+	}
+	if types.IsNil(ca.Private) {
+		return errors.New("the certificate authority private key is required to issue certificates")
+	}
+
+	return nil
+}
+
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
+
+func stringOption(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, got %T", v)
+	}
+
+	return s, nil
+}
+
+func stringsOption(v interface{}) ([]string, error) {
+	switch values := v.(type) {
+	case string:
+		return []string{values}, nil
+	case []string:
+		return values, nil
+	case []interface{}:
+		res := make([]string, 0, len(values))
+		for _, item := range values {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string item, got %T", item)
+			}
+			res = append(res, s)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a string list, got %T", v)
+	}
+}
+
+func intOption(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", v)
+	}
+}
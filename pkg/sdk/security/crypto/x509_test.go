@@ -0,0 +1,363 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestX509SelfSignedCA(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "rsa with subject and path length",
+			opts: []map[string]interface{}{
+				{
+					"keyType":      "rsa",
+					"validity":     "8760h",
+					"organization": "Elastic",
+					"country":      []interface{}{"US"},
+					"pathLen":      1,
+				},
+			},
+		},
+		{
+			name:    "invalid key type",
+			opts:    []map[string]interface{}{{"keyType": "naclbox"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid validity",
+			opts:    []map[string]interface{}{{"validity": "1y"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative validity",
+			opts:    []map[string]interface{}{{"validity": "-1h"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key usage",
+			opts:    []map[string]interface{}{{"keyUsages": []string{"teleport"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported option",
+			opts:    []map[string]interface{}{{"serial": 1}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid option type",
+			opts:    []map[string]interface{}{{"sans": 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := X509SelfSignedCA("Harp Root CA", tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("X509SelfSignedCA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assert.True(t, got.Certificate.IsCA)
+			assert.Equal(t, "Harp Root CA", got.Certificate.Subject.CommonName)
+			assert.Equal(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign|x509.KeyUsageDigitalSignature, got.Certificate.KeyUsage)
+			assert.NoError(t, got.Certificate.CheckSignatureFrom(got.Certificate))
+			assert.Empty(t, got.Chain)
+		})
+	}
+}
+
+func TestX509IssueCertificate(t *testing.T) {
+	root, err := X509SelfSignedCA("Harp Root CA", map[string]interface{}{"pathLen": 1, "validity": "24h"})
+	require.NoError(t, err)
+
+	intermediate, err := X509IssueCertificate(root, "Harp Intermediate CA", map[string]interface{}{"isCA": true})
+	require.NoError(t, err)
+	assert.True(t, intermediate.Certificate.IsCA)
+	assert.True(t, intermediate.Certificate.MaxPathLenZero)
+
+	leaf, err := X509IssueCertificate(intermediate, "server.example.com", map[string]interface{}{
+		"keyType":  "rsa",
+		"validity": "8760h",
+		"sans":     []interface{}{"server.example.com", "127.0.0.1", "admin@example.com", "spiffe://example.com/server"},
+	})
+	require.NoError(t, err)
+
+	// Check leaf properties
+	assert.False(t, leaf.Certificate.IsCA)
+	assert.Equal(t, []string{"server.example.com"}, leaf.Certificate.DNSNames)
+	assert.Equal(t, "127.0.0.1", leaf.Certificate.IPAddresses[0].String())
+	assert.Equal(t, []string{"admin@example.com"}, leaf.Certificate.EmailAddresses)
+	assert.Equal(t, "spiffe://example.com/server", leaf.Certificate.URIs[0].String())
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment, leaf.Certificate.KeyUsage)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, leaf.Certificate.ExtKeyUsage)
+	assert.False(t, leaf.Certificate.NotAfter.After(root.Certificate.NotAfter), "leaf must not outlive its issuers")
+	assert.Equal(t, []*x509.Certificate{intermediate.Certificate, root.Certificate}, leaf.Chain)
+
+	// Verify the chain
+	roots := x509.NewCertPool()
+	roots.AddCert(root.Certificate)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate.Certificate)
+	_, err = leaf.Certificate.Verify(x509.VerifyOptions{
+		DNSName:       "server.example.com",
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	assert.NoError(t, err)
+
+	// Path length constraints
+	_, err = X509IssueCertificate(intermediate, "Sub CA", map[string]interface{}{"isCA": true})
+	assert.Error(t, err)
+	_, err = X509IssueCertificate(root, "Sub CA", map[string]interface{}{"isCA": true, "pathLen": 1})
+	assert.Error(t, err)
+	_, err = X509IssueCertificate(root, "leaf", map[string]interface{}{"pathLen": 0})
+	assert.Error(t, err)
+
+	// Invalid issuers
+	_, err = X509IssueCertificate(nil, "leaf")
+	assert.Error(t, err)
+	_, err = X509IssueCertificate(leaf, "leaf")
+	assert.Error(t, err)
+	_, err = X509IssueCertificate(&Certificate{Certificate: root.Certificate}, "leaf")
+	assert.Error(t, err)
+}
+
+func TestX509IssueCertificate_ExpiredIssuer(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Expired CA"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(-time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	expired := &Certificate{Certificate: cert, Private: key}
+	_, err = X509IssueCertificate(expired, "leaf")
+	assert.Error(t, err)
+	_, err = X509IssueCertificate(expired, "Sub CA", map[string]interface{}{"isCA": true})
+	assert.Error(t, err)
+}
+
+func TestX509LoadCA(t *testing.T) {
+	root, err := X509SelfSignedCA("Harp Root CA")
+	require.NoError(t, err)
+	intermediate, err := X509IssueCertificate(root, "Harp Intermediate CA", map[string]interface{}{"isCA": true})
+	require.NoError(t, err)
+
+	chainPEM, err := X509FullChain(intermediate)
+	require.NoError(t, err)
+	keyPEM, err := ToPEM(intermediate.Private)
+	require.NoError(t, err)
+	otherKeyPEM, err := ToPEM(root.Private)
+	require.NoError(t, err)
+	leaf, err := X509IssueCertificate(intermediate, "leaf")
+	require.NoError(t, err)
+	leafPEM, err := X509FullChain(leaf.Certificate)
+	require.NoError(t, err)
+	leafKeyPEM, err := ToPEM(leaf.Private)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		certPEM string
+		keyPEM  string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			certPEM: chainPEM,
+			keyPEM:  keyPEM,
+		},
+		{
+			name:    "invalid certificate",
+			certPEM: "invalid",
+			keyPEM:  keyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			certPEM: chainPEM,
+			keyPEM:  "invalid",
+			wantErr: true,
+		},
+		{
+			name:    "key mismatch",
+			certPEM: chainPEM,
+			keyPEM:  otherKeyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "not a CA",
+			certPEM: leafPEM,
+			keyPEM:  leafKeyPEM,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := X509LoadCA(tt.certPEM, tt.keyPEM)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("X509LoadCA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assert.Equal(t, intermediate.Certificate.Raw, got.Certificate.Raw)
+			assert.Equal(t, []*x509.Certificate{root.Certificate}, got.Chain)
+
+			// Loaded CA can issue certificates
+			_, err = X509IssueCertificate(got, "leaf")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestX509SignCSR(t *testing.T) {
+	ca, err := X509SelfSignedCA("Harp Root CA")
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "client", Organization: []string{"Elastic"}},
+		DNSNames: []string{"client.example.com"},
+	}, key)
+	require.NoError(t, err)
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+
+	cert, err := X509SignCSR(ca, csrPEM, map[string]interface{}{
+		"sans":      "client.internal",
+		"keyUsages": []interface{}{"digitalSignature", "clientAuth"},
+		"validity":  "1h",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, cert.Private)
+	assert.Equal(t, "client", cert.Certificate.Subject.CommonName)
+	assert.Equal(t, []string{"Elastic"}, cert.Certificate.Subject.Organization)
+	assert.Equal(t, []string{"client.internal", "client.example.com"}, cert.Certificate.DNSNames)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.Certificate.ExtKeyUsage)
+	assert.WithinDuration(t, time.Now().Add(55*time.Minute), cert.Certificate.NotAfter, time.Minute)
+	assert.True(t, publicKeyEqual(cert.Certificate.PublicKey, &key.PublicKey))
+	assert.NoError(t, cert.Certificate.CheckSignatureFrom(ca.Certificate))
+
+	// Invalid request
+	_, err = X509SignCSR(ca, "invalid")
+	assert.Error(t, err)
+}
+
+func TestX509FullChain(t *testing.T) {
+	root, err := X509SelfSignedCA("Harp Root CA", map[string]interface{}{"keyType": "rsa"})
+	require.NoError(t, err)
+	leaf, err := X509IssueCertificate(root, "leaf")
+	require.NoError(t, err)
+
+	rootPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Certificate.Raw}))
+	leafPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate.Raw}))
+
+	tests := []struct {
+		name    string
+		items   []interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			items: []interface{}{},
+			want:  "",
+		},
+		{
+			name:  "generated certificate",
+			items: []interface{}{leaf},
+			want:  leafPEM + rootPEM,
+		},
+		{
+			name:  "mixed",
+			items: []interface{}{leaf.Certificate, rootPEM},
+			want:  leafPEM + rootPEM,
+		},
+		{
+			name:  "certificate collection",
+			items: []interface{}{[]*x509.Certificate{leaf.Certificate, root.Certificate}},
+			want:  leafPEM + rootPEM,
+		},
+		{
+			name:    "invalid PEM",
+			items:   []interface{}{"invalid"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			items:   []interface{}{1},
+			wantErr: true,
+		},
+		{
+			name:    "nil certificate",
+			items:   []interface{}{(*Certificate)(nil)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := X509FullChain(tt.items...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("X509FullChain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Ensure rsa keys are usable
+	_, ok := root.Private.(*rsa.PrivateKey)
+	assert.True(t, ok)
+}
@@ -18,7 +18,9 @@
 package diceware
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sethvargo/go-diceware/diceware"
@@ -41,6 +43,12 @@ const (
 
 // Diceware generates a passphrase using english words
 func Diceware(count int) (string, error) {
+	return DicewareFrom(rand.Reader, count)
+}
+
+// DicewareFrom generates a passphrase using english words and the given
+// random source.
+func DicewareFrom(random io.Reader, count int) (string, error) {
 	// Check parameters
 	if count < MinWordCount {
 		count = MinWordCount
@@ -50,7 +58,14 @@ func Diceware(count int) (string, error) {
 	}
 
 	// Generate word list
-	list, err := diceware.Generate(count)
+	g, err := diceware.NewGenerator(&diceware.GeneratorInput{
+		RandReader: random,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to initialize diceware generator: %w", err)
+	}
+
+	list, err := g.Generate(count)
 	if err != nil {
 		return "", fmt.Errorf("unable to generate daceware passphrase: %w", err)
 	}
@@ -18,6 +18,7 @@
 package diceware
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 
@@ -148,3 +149,19 @@ func TestDiceware_Fuzz(t *testing.T) {
 		Diceware(wordCount)
 	}
 }
+
+func TestDicewareFrom_Deterministic(t *testing.T) {
+	//nolint:gosec // deterministic random source for test
+	first, err := DicewareFrom(rand.New(rand.NewSource(1)), StrongWordCount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	//nolint:gosec // deterministic random source for test
+	second, err := DicewareFrom(rand.New(rand.NewSource(1)), StrongWordCount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("DicewareFrom() = %q, want %q", second, first)
+	}
+}
@@ -0,0 +1,301 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // required by RFC 4226
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAlgorithm defines the default HMAC hash algorithm.
+	DefaultAlgorithm = "SHA1"
+	// DefaultDigits defines the default code length.
+	DefaultDigits = 6
+	// DefaultPeriod defines the default TOTP time step in seconds.
+	DefaultPeriod = 30
+)
+
+var b32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Seed generates a random base32 encoded seed sized for the given HMAC
+// algorithm (SHA1, SHA256 or SHA512).
+func Seed(algorithm string) (string, error) {
+	return SeedFrom(rand.Reader, algorithm)
+}
+
+// SeedFrom generates a base32 encoded seed sized for the given HMAC algorithm
+// using the given random source.
+func SeedFrom(random io.Reader, algorithm string) (string, error) {
+	// Check arguments
+	if algorithm == "" {
+		algorithm = DefaultAlgorithm
+	}
+	h, err := hashFunc(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	// Key length matches the hash output length (RFC 4226 - Section 4)
+	seed := make([]byte, h().Size())
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return "", fmt.Errorf("unable to generate OTP seed: %w", err)
+	}
+
+	// No error
+	return b32NoPadding.EncodeToString(seed), nil
+}
+
+// TOTP computes the current time based one-time password (RFC 6238) for the
+// given base32 encoded seed.
+//
+// Supported options are `algorithm` (default: SHA1), `digits` (default: 6)
+// and `period` in seconds (default: 30).
+func TOTP(seed string, opts ...map[string]interface{}) (string, error) {
+	return totp(seed, time.Now(), opts...)
+}
+
+// HOTP computes the counter based one-time password (RFC 4226) for the
+// given base32 encoded seed.
+//
+// Supported options are `algorithm` (default: SHA1) and `digits` (default: 6).
+func HOTP(seed string, counter int, opts ...map[string]interface{}) (string, error) {
+	// Check arguments
+	if counter < 0 {
+		return "", errors.New("unable to compute HOTP code with a negative counter")
+	}
+
+	// Parse options
+	o, err := parseOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Decode seed
+	key, err := decodeSeed(seed)
+	if err != nil {
+		return "", err
+	}
+
+	// No error
+	return o.code(key, uint64(counter))
+}
+
+// TOTPURI builds an `otpauth://totp/` provisioning URI.
+func TOTPURI(issuer, account, seed string, opts ...map[string]interface{}) (string, error) {
+	return provisioningURI("totp", issuer, account, seed, 0, opts...)
+}
+
+// HOTPURI builds an `otpauth://hotp/` provisioning URI with the given
+// initial counter.
+func HOTPURI(issuer, account, seed string, counter int, opts ...map[string]interface{}) (string, error) {
+	// Check arguments
+	if counter < 0 {
+		return "", errors.New("unable to build HOTP URI with a negative counter")
+	}
+
+	return provisioningURI("hotp", issuer, account, seed, counter, opts...)
+}
+
+// -----------------------------------------------------------------------------
+
+type options struct {
+	algorithm string
+	digits    int
+	period    int
+}
+
+func parseOptions(opts ...map[string]interface{}) (*options, error) {
+	o := &options{
+		algorithm: DefaultAlgorithm,
+		digits:    DefaultDigits,
+		period:    DefaultPeriod,
+	}
+
+	for _, opt := range opts {
+		for k, v := range opt {
+			var err error
+			switch k {
+			case "algorithm":
+				s, ok := v.(string)
+				if !ok {
+					err = fmt.Errorf("expected a string, got %T", v)
+					break
+				}
+				o.algorithm = strings.ToUpper(s)
+				_, err = hashFunc(o.algorithm)
+			case "digits":
+				if o.digits, err = intOption(v); err == nil && (o.digits < 6 || o.digits > 8) {
+					err = errors.New("digits must be between 6 and 8")
+				}
+			case "period":
+				if o.period, err = intOption(v); err == nil && o.period <= 0 {
+					err = errors.New("period must be positive")
+				}
+			default:
+				err = errors.New("unsupported option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid OTP option '%s': %w", k, err)
+			}
+		}
+	}
+
+	// No error
+	return o, nil
+}
+
+// code implements the HOTP dynamic truncation (RFC 4226 - Section 5.3).
+func (o *options) code(key []byte, counter uint64) (string, error) {
+	h, err := hashFunc(o.algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(h, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	value %= uint32(math.Pow10(o.digits))
+
+	// No error
+	return fmt.Sprintf("%0*d", o.digits, value), nil
+}
+
+func totp(seed string, t time.Time, opts ...map[string]interface{}) (string, error) {
+	// Parse options
+	o, err := parseOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Decode seed
+	key, err := decodeSeed(seed)
+	if err != nil {
+		return "", err
+	}
+
+	// No error
+	return o.code(key, uint64(t.Unix())/uint64(o.period))
+}
+
+func provisioningURI(kind, issuer, account, seed string, counter int, opts ...map[string]interface{}) (string, error) {
+	// Check arguments
+	if account == "" {
+		return "", errors.New("unable to build OTP URI without account name")
+	}
+	if strings.Contains(issuer, ":") || strings.Contains(account, ":") {
+		return "", errors.New("issuer and account name must not contain ':'")
+	}
+
+	// Parse options
+	o, err := parseOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	// Validate seed
+	key, err := decodeSeed(seed)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare label
+	label := url.PathEscape(account)
+	if issuer != "" {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+
+	// Prepare parameters
+	params := url.Values{}
+	params.Set("secret", b32NoPadding.EncodeToString(key))
+	if issuer != "" {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", o.algorithm)
+	params.Set("digits", strconv.Itoa(o.digits))
+	if kind == "totp" {
+		params.Set("period", strconv.Itoa(o.period))
+	} else {
+		params.Set("counter", strconv.Itoa(counter))
+	}
+
+	// No error
+	return fmt.Sprintf("otpauth://%s/%s?%s", kind, label, params.Encode()), nil
+}
+
+func decodeSeed(seed string) ([]byte, error) {
+	// Normalize seed
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(seed))
+	normalized = strings.TrimRight(normalized, "=")
+
+	key, err := b32NoPadding.DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode OTP seed as base32: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, errors.New("unable to use an empty OTP seed")
+	}
+
+	return key, nil
+}
+
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTP algorithm %q", algorithm)
+	}
+}
+
+func intOption(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", v)
+	}
+}
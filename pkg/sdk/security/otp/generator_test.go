@@ -0,0 +1,189 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otp
+
+import (
+	"encoding/base32"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// RFC 4226 / RFC 6238 test seeds
+	rfcSeedSHA1   = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	rfcSeedSHA256 = base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012"))
+	rfcSeedSHA512 = base32.StdEncoding.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234"))
+)
+
+func TestSeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		wantLen   int
+		wantErr   bool
+	}{
+		{name: "default", wantLen: 20},
+		{name: "sha1", algorithm: "sha1", wantLen: 20},
+		{name: "sha256", algorithm: "SHA256", wantLen: 32},
+		{name: "sha512", algorithm: "SHA512", wantLen: 64},
+		{name: "unsupported", algorithm: "MD5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Seed(tt.algorithm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Seed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			key, err := decodeSeed(got)
+			assert.NoError(t, err)
+			assert.Len(t, key, tt.wantLen)
+		})
+	}
+}
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 - Appendix D
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, want := range expected {
+		got, err := HOTP(rfcSeedSHA1, counter)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := HOTP(rfcSeedSHA1, -1)
+	assert.Error(t, err)
+	_, err = HOTP("not-base32!", 0)
+	assert.Error(t, err)
+	_, err = HOTP(rfcSeedSHA1, 0, map[string]interface{}{"digits": 4})
+	assert.Error(t, err)
+}
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 - Appendix B
+	tests := []struct {
+		unix      int64
+		algorithm string
+		seed      string
+		want      string
+	}{
+		{unix: 59, algorithm: "SHA1", seed: rfcSeedSHA1, want: "94287082"},
+		{unix: 59, algorithm: "SHA256", seed: rfcSeedSHA256, want: "46119246"},
+		{unix: 59, algorithm: "SHA512", seed: rfcSeedSHA512, want: "90693936"},
+		{unix: 1111111109, algorithm: "SHA1", seed: rfcSeedSHA1, want: "07081804"},
+		{unix: 1111111111, algorithm: "SHA256", seed: rfcSeedSHA256, want: "67062674"},
+		{unix: 1234567890, algorithm: "SHA512", seed: rfcSeedSHA512, want: "93441116"},
+		{unix: 2000000000, algorithm: "SHA1", seed: rfcSeedSHA1, want: "69279037"},
+		{unix: 20000000000, algorithm: "SHA256", seed: rfcSeedSHA256, want: "77737706"},
+	}
+	for _, tt := range tests {
+		got, err := totp(tt.seed, time.Unix(tt.unix, 0), map[string]interface{}{"algorithm": tt.algorithm, "digits": 8})
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s at %d", tt.algorithm, tt.unix)
+	}
+
+	// Current code
+	got, err := TOTP(rfcSeedSHA1)
+	assert.NoError(t, err)
+	assert.Len(t, got, DefaultDigits)
+
+	_, err = TOTP(rfcSeedSHA1, map[string]interface{}{"period": 0})
+	assert.Error(t, err)
+	_, err = TOTP(rfcSeedSHA1, map[string]interface{}{"foo": "bar"})
+	assert.Error(t, err)
+}
+
+func TestTOTPURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		issuer  string
+		account string
+		opts    []map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "blank account",
+			issuer:  "Elastic",
+			wantErr: true,
+		},
+		{
+			name:    "invalid issuer",
+			issuer:  "Elastic:Security",
+			account: "break-glass",
+			wantErr: true,
+		},
+		{
+			name:    "default",
+			issuer:  "Elastic Security",
+			account: "break-glass@elastic.co",
+			want:    "otpauth://totp/Elastic%20Security:break-glass@elastic.co?algorithm=SHA1&digits=6&issuer=Elastic+Security&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		},
+		{
+			name:    "without issuer and with options",
+			account: "break-glass",
+			opts:    []map[string]interface{}{{"algorithm": "sha256", "digits": 8, "period": 60}},
+			want:    "otpauth://totp/break-glass?algorithm=SHA256&digits=8&period=60&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TOTPURI(tt.issuer, tt.account, rfcSeedSHA1, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TOTPURI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHOTPURI(t *testing.T) {
+	got, err := HOTPURI("Elastic", "break-glass", rfcSeedSHA1, 5)
+	require.NoError(t, err)
+
+	u, err := url.Parse(got)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "hotp", u.Host)
+	assert.Equal(t, "/Elastic:break-glass", u.Path)
+	assert.Equal(t, "5", u.Query().Get("counter"))
+	assert.Empty(t, u.Query().Get("period"))
+
+	_, err = HOTPURI("Elastic", "break-glass", rfcSeedSHA1, -1)
+	assert.Error(t, err)
+}
+
+func TestSeedFrom_Deterministic(t *testing.T) {
+	//nolint:gosec // deterministic random source for test
+	first, err := SeedFrom(rand.New(rand.NewSource(1)), "SHA256")
+	require.NoError(t, err)
+	//nolint:gosec // deterministic random source for test
+	second, err := SeedFrom(rand.New(rand.NewSource(1)), "SHA256")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, first, 52)
+}
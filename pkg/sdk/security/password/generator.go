@@ -18,7 +18,9 @@
 package password
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/sethvargo/go-password/password"
@@ -31,6 +33,11 @@ const (
 
 // Generate a custom password
 func Generate(length, numDigits, numSymbol int, noUpper, allowRepeat bool) (string, error) {
+	return GenerateFrom(rand.Reader, length, numDigits, numSymbol, noUpper, allowRepeat)
+}
+
+// GenerateFrom generates a custom password using the given random source.
+func GenerateFrom(random io.Reader, length, numDigits, numSymbol int, noUpper, allowRepeat bool) (string, error) {
 	// Check parameters
 	if length < 0 || length > MaxPasswordLen {
 		length = MaxPasswordLen
@@ -42,7 +49,14 @@ func Generate(length, numDigits, numSymbol int, noUpper, allowRepeat bool) (stri
 		numSymbol = int(math.Floor(0.1 * float64(length))) // 10% of length
 	}
 
-	p, err := password.Generate(length, numDigits, numSymbol, noUpper, allowRepeat)
+	g, err := password.NewGenerator(&password.GeneratorInput{
+		Reader: random,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to initialize password generator: %w", err)
+	}
+
+	p, err := g.Generate(length, numDigits, numSymbol, noUpper, allowRepeat)
 	if err != nil {
 		return "", fmt.Errorf("unable to generate a password: %w", err)
 	}
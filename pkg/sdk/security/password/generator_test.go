@@ -18,6 +18,7 @@
 package password
 
 import (
+	"math/rand"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
@@ -152,3 +153,19 @@ func TestFromProfile_Fuzz(t *testing.T) {
 		FromProfile(&p)
 	}
 }
+
+func TestGenerateFrom_Deterministic(t *testing.T) {
+	//nolint:gosec // deterministic random source for test
+	first, err := GenerateFrom(rand.New(rand.NewSource(1)), 32, 10, 10, false, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	//nolint:gosec // deterministic random source for test
+	second, err := GenerateFrom(rand.New(rand.NewSource(1)), 32, 10, 10, false, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("GenerateFrom() = %q, want %q", second, first)
+	}
+}
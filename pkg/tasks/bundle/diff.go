@@ -23,8 +23,10 @@ import (
 	"errors"
 	"fmt"
 
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
 	"github.com/elastic/harp/pkg/bundle/compare"
+	"github.com/elastic/harp/pkg/bundle/patch"
 	"github.com/elastic/harp/pkg/sdk/convert"
 	"github.com/elastic/harp/pkg/sdk/types"
 	"github.com/elastic/harp/pkg/tasks"
@@ -36,6 +38,9 @@ type DiffTask struct {
 	DestinationReader tasks.ReaderProvider
 	OutputWriter      tasks.WriterProvider
 	GeneratePatch     bool
+	WithPreconditions bool
+	FingerprintKey    string
+	Format            string
 }
 
 // Run the task.
@@ -50,6 +55,11 @@ func (t *DiffTask) Run(ctx context.Context) error {
 	if types.IsNil(t.OutputWriter) {
 		return errors.New("unable to run task with a nil outputWriter provider")
 	}
+	switch t.Format {
+	case "", "oplog", "jsonpatch", "mergepatch":
+	default:
+		return fmt.Errorf("unsupported output format '%s'", t.Format)
+	}
 
 	// Create input reader
 	readerSrc, err := t.SourceReader(ctx)
@@ -87,28 +97,99 @@ func (t *DiffTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to open output writer: %w", err)
 	}
 
-	if !t.GeneratePatch {
-		// Encode as JSON
-		if err := json.NewEncoder(writer).Encode(report); err != nil {
-			return fmt.Errorf("unable to marshal JSON OpLog: %w", err)
-		}
-	} else {
+	switch {
+	case t.GeneratePatch:
 		// Convert optlog as a patch
-		patch, err := compare.ToPatch(report)
+		spec, err := compare.ToPatch(report)
 		if err != nil {
 			return fmt.Errorf("unable to convert oplog as a bundle patch: %w", err)
 		}
 
+		// Bind the patch to the source bundle state
+		if t.WithPreconditions {
+			spec.Spec.Preconditions, err = patch.Preconditions(bSrc)
+			if err != nil {
+				return fmt.Errorf("unable to compute patch preconditions: %w", err)
+			}
+
+			// Fingerprint secrets altered by the patch
+			if t.FingerprintKey != "" {
+				refs := []string{}
+				for _, op := range report {
+					switch {
+					case op.Type == "secret" && (op.Operation == compare.Replace || op.Operation == compare.Remove):
+						refs = append(refs, op.Path)
+					case op.Type == "package" && op.Operation == compare.Remove:
+						refs = append(refs, packageSecretRefs(bSrc, op.Path)...)
+					}
+				}
+
+				spec.Spec.Preconditions.Fingerprints, err = patch.Fingerprints(bSrc, []byte(t.FingerprintKey), refs...)
+				if err != nil {
+					return fmt.Errorf("unable to compute secret fingerprints: %w", err)
+				}
+				spec.Spec.Preconditions.FingerprintKey = patch.FingerprintKeyTemplate
+			}
+		}
+
 		// Marshal as YAML
-		out, err := convert.PBtoYAML(patch)
+		out, err := convert.PBtoYAML(spec)
 		if err != nil {
 			return fmt.Errorf("unable to marshal patch as YAML: %w", err)
 		}
 
 		// Write output
 		fmt.Fprintln(writer, string(out))
+	case t.Format == "" || t.Format == "oplog":
+		// Encode as JSON
+		if err := json.NewEncoder(writer).Encode(report); err != nil {
+			return fmt.Errorf("unable to marshal JSON OpLog: %w", err)
+		}
+	case t.Format == "jsonpatch":
+		// Convert oplog as a JSON Patch
+		jsonPatch, err := compare.ToJSONPatch(report)
+		if err != nil {
+			return fmt.Errorf("unable to convert oplog as a JSON patch: %w", err)
+		}
+
+		// Encode as JSON
+		if err := json.NewEncoder(writer).Encode(jsonPatch); err != nil {
+			return fmt.Errorf("unable to marshal JSON patch: %w", err)
+		}
+	case t.Format == "mergepatch":
+		// Convert oplog as a JSON Merge Patch
+		mergePatch, err := compare.ToMergePatch(report)
+		if err != nil {
+			return fmt.Errorf("unable to convert oplog as a JSON merge patch: %w", err)
+		}
+
+		// Encode as JSON
+		if err := json.NewEncoder(writer).Encode(mergePatch); err != nil {
+			return fmt.Errorf("unable to marshal JSON merge patch: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format '%s'", t.Format)
 	}
 
 	// No error
 	return nil
 }
+
+// -----------------------------------------------------------------------------
+
+func packageSecretRefs(b *bundlev1.Bundle, path string) []string {
+	refs := []string{}
+	for _, p := range b.Packages {
+		if p == nil || p.Name != path || p.Secrets == nil {
+			continue
+		}
+		for _, kv := range p.Secrets.Data {
+			if kv == nil {
+				continue
+			}
+			refs = append(refs, fmt.Sprintf("%s#%s", p.Name, kv.Key))
+		}
+	}
+
+	return refs
+}
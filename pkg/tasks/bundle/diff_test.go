@@ -33,6 +33,8 @@ func TestDiffTask_Run(t *testing.T) {
 		DestinationReader tasks.ReaderProvider
 		OutputWriter      tasks.WriterProvider
 		GeneratePatch     bool
+		WithPreconditions bool
+		FingerprintKey    string
 	}
 	type args struct {
 		ctx context.Context
@@ -167,6 +169,18 @@ func TestDiffTask_Run(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "bundle diff - with patch and fingerprints",
+			fields: fields{
+				SourceReader:      cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				DestinationReader: cmdutil.FileReader("../../../test/fixtures/bundles/empty.bundle"),
+				OutputWriter:      cmdutil.DiscardWriter(),
+				GeneratePatch:     true,
+				WithPreconditions: true,
+				FingerprintKey:    "fingerprint-key",
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -175,6 +189,8 @@ func TestDiffTask_Run(t *testing.T) {
 				DestinationReader: tt.fields.DestinationReader,
 				OutputWriter:      tt.fields.OutputWriter,
 				GeneratePatch:     tt.fields.GeneratePatch,
+				WithPreconditions: tt.fields.WithPreconditions,
+				FingerprintKey:    tt.fields.FingerprintKey,
 			}
 			if err := tr.Run(tt.args.ctx); (err != nil) != tt.wantErr {
 				t.Errorf("DiffTask.Run() error = %v, wantErr %v", err, tt.wantErr)
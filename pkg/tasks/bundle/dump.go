@@ -41,6 +41,7 @@ type DumpTask struct {
 	MetadataOnly    bool
 	JMESPathFilter  string
 	IgnoreTemplate  bool
+	SelectorReader  tasks.ReaderProvider
 }
 
 // Run the task.
@@ -70,6 +71,14 @@ func (t *DumpTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to load bundle content: %w", err)
 	}
 
+	// Restrict packages to selector matches
+	if !types.IsNil(t.SelectorReader) {
+		b.Packages, err = selectorFilter(ctx, b.Packages, t.SelectorReader, false)
+		if err != nil {
+			return fmt.Errorf("unable to filter bundle packages: %w", err)
+		}
+	}
+
 	// Create output writer
 	writer, err := t.OutputWriter(ctx)
 	if err != nil {
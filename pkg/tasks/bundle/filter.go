@@ -28,6 +28,7 @@ import (
 
 	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/patch"
 	"github.com/elastic/harp/pkg/bundle/selector"
 	"github.com/elastic/harp/pkg/sdk/types"
 	"github.com/elastic/harp/pkg/tasks"
@@ -43,6 +44,7 @@ type FilterTask struct {
 	JMESPath        string
 	RegoPolicy      string
 	CELExpressions  []string
+	SelectorReader  tasks.ReaderProvider
 }
 
 // Run the task.
@@ -106,6 +108,13 @@ func (t *FilterTask) Run(ctx context.Context) error {
 		}
 	}
 
+	if !types.IsNil(t.SelectorReader) {
+		b.Packages, errFilter = selectorFilter(ctx, b.Packages, t.SelectorReader, t.ReverseLogic)
+		if errFilter != nil {
+			return fmt.Errorf("unable to filter bundle packages: %w", errFilter)
+		}
+	}
+
 	// Create output writer
 	writer, err := t.OutputWriter(ctx)
 	if err != nil {
@@ -276,3 +285,41 @@ func (t *FilterTask) celFilter(in []*bundlev1.Package, celExpressions []string,
 	// No error
 	return pkgs, nil
 }
+
+func selectorFilter(ctx context.Context, in []*bundlev1.Package, selectorReader tasks.ReaderProvider, reverseLogic bool) ([]*bundlev1.Package, error) {
+	// Check Arguments
+	if len(in) == 0 {
+		return in, nil
+	}
+
+	// Create selector reader
+	reader, err := selectorReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open selector file: %w", err)
+	}
+
+	// Parse selector
+	sel, err := patch.SelectorYAML(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse selector file: %w", err)
+	}
+
+	// Compile selector
+	s, err := patch.CompileSelector(sel, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile selector: %w", err)
+	}
+
+	pkgs := []*bundlev1.Package{}
+
+	// Apply package filtering
+	for _, p := range in {
+		matched := s.IsSatisfiedBy(p)
+		if matched && !reverseLogic || !matched && reverseLogic {
+			pkgs = append(pkgs, p)
+		}
+	}
+
+	// No error
+	return pkgs, nil
+}
@@ -61,6 +61,7 @@ func TestFilterTask_Run(t *testing.T) {
 		KeepPaths       []string
 		ExcludePaths    []string
 		JMESPath        string
+		SelectorReader  tasks.ReaderProvider
 	}
 	type args struct {
 		ctx context.Context
@@ -146,6 +147,15 @@ func TestFilterTask_Run(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "selector - not found",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				OutputWriter:    cmdutil.DiscardWriter(),
+				SelectorReader:  cmdutil.FileReader("../../../test/fixtures/selector/non-existent.yaml"),
+			},
+			wantErr: true,
+		},
 		// ---------------------------------------------------------------------
 		{
 			name: "valid - noop",
@@ -182,6 +192,15 @@ func TestFilterTask_Run(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid - selector",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				OutputWriter:    cmdutil.DiscardWriter(),
+				SelectorReader:  cmdutil.FileReader("../../../test/fixtures/selector/composite.yaml"),
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -192,6 +211,7 @@ func TestFilterTask_Run(t *testing.T) {
 				KeepPaths:       tt.fields.KeepPaths,
 				ExcludePaths:    tt.fields.ExcludePaths,
 				JMESPath:        tt.fields.JMESPath,
+				SelectorReader:  tt.fields.SelectorReader,
 			}
 			if err := tr.Run(tt.args.ctx); (err != nil) != tt.wantErr {
 				t.Errorf("FilterTask.Run() error = %v, wantErr %v", err, tt.wantErr)
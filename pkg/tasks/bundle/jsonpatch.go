@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/compare"
+	"github.com/elastic/harp/pkg/bundle/jsonpatch"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+// JSONPatchTask implements JSON Patch document application task.
+type JSONPatchTask struct {
+	PatchReader     tasks.ReaderProvider
+	ContainerReader tasks.ReaderProvider
+	OutputWriter    tasks.WriterProvider
+	MergePatch      bool
+}
+
+// Run the task.
+func (t *JSONPatchTask) Run(ctx context.Context) error {
+	// Check arguments
+	if types.IsNil(t.ContainerReader) {
+		return errors.New("unable to run task with a nil containerReader provider")
+	}
+	if types.IsNil(t.PatchReader) {
+		return errors.New("unable to run task with a nil patchReader provider")
+	}
+	if types.IsNil(t.OutputWriter) {
+		return errors.New("unable to run task with a nil outputWriter provider")
+	}
+
+	// Retrieve the container reader
+	containerReader, err := t.ContainerReader(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve container reader: %w", err)
+	}
+
+	// Load bundle
+	b, err := bundle.FromContainerReader(containerReader)
+	if err != nil {
+		return fmt.Errorf("unable to load bundle content: %w", err)
+	}
+
+	// Retrieve the patch reader
+	patchReader, err := t.PatchReader(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve patch reader: %w", err)
+	}
+
+	var patchedBundle *bundlev1.Bundle
+	if t.MergePatch {
+		// Decode the merge patch document
+		var doc map[string]interface{}
+		if err = json.NewDecoder(patchReader).Decode(&doc); err != nil {
+			return fmt.Errorf("unable to decode JSON merge patch: %w", err)
+		}
+
+		// Apply the document
+		patchedBundle, err = jsonpatch.Merge(b, doc)
+		if err != nil {
+			return fmt.Errorf("unable to generate output bundle from JSON merge patch: %w", err)
+		}
+	} else {
+		// Decode the patch document
+		var doc compare.JSONPatch
+		if err = json.NewDecoder(patchReader).Decode(&doc); err != nil {
+			return fmt.Errorf("unable to decode JSON patch: %w", err)
+		}
+
+		// Apply the document
+		patchedBundle, err = jsonpatch.Apply(b, doc)
+		if err != nil {
+			return fmt.Errorf("unable to generate output bundle from JSON patch: %w", err)
+		}
+	}
+
+	// Retrieve the output writer
+	outputWriter, err := t.OutputWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve output writer: %w", err)
+	}
+
+	// Dump all content
+	if err = bundle.ToContainerWriter(outputWriter, patchedBundle); err != nil {
+		return fmt.Errorf("unable to dump bundle content: %w", err)
+	}
+
+	// No error
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
 	"github.com/elastic/harp/pkg/bundle/ruleset"
 	"github.com/elastic/harp/pkg/sdk/types"
@@ -32,6 +33,10 @@ import (
 type LintTask struct {
 	ContainerReader tasks.ReaderProvider
 	RuleSetReader   tasks.ReaderProvider
+	OutputWriter    tasks.WriterProvider
+	ReportFormat    string
+	ContainerPath   string
+	RuleSetPath     string
 }
 
 // Run the task.
@@ -43,6 +48,14 @@ func (t *LintTask) Run(ctx context.Context) error {
 	if types.IsNil(t.RuleSetReader) {
 		return errors.New("unable to run task with a nil ruleSetReader provider")
 	}
+	if t.ReportFormat != "" {
+		if types.IsNil(t.OutputWriter) {
+			return errors.New("unable to run task with a nil outputWriter provider")
+		}
+		if !types.StringArray(ruleset.ReportFormats).Contains(t.ReportFormat) {
+			return fmt.Errorf("unsupported report format '%s'", t.ReportFormat)
+		}
+	}
 
 	// Create input reader
 	reader, err := t.ContainerReader(ctx)
@@ -62,12 +75,20 @@ func (t *LintTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to parse ruleset file: %w", err)
 	}
 
+	// Resolve policy paths relatively to the ruleset file
+	ruleset.ResolvePaths(spec, t.RuleSetPath)
+
 	// Load bundle
 	b, err := bundle.FromContainerReader(reader)
 	if err != nil {
 		return fmt.Errorf("unable to load bundle content: %w", err)
 	}
 
+	// Collect all violations when a report is requested
+	if t.ReportFormat != "" {
+		return t.report(ctx, b, spec)
+	}
+
 	if err := ruleset.Evaluate(ctx, b, spec); err != nil {
 		return fmt.Errorf("unable to validate given bundle: %w", err)
 	}
@@ -75,3 +96,37 @@ func (t *LintTask) Run(ctx context.Context) error {
 	// No error
 	return nil
 }
+
+// -----------------------------------------------------------------------------
+
+func (t *LintTask) report(ctx context.Context, b *bundlev1.Bundle, spec *bundlev1.RuleSet) error {
+	// Evaluate all rules
+	report, err := ruleset.EvaluateAll(ctx, b, spec)
+	if err != nil {
+		return fmt.Errorf("unable to evaluate ruleset: %w", err)
+	}
+
+	// Reference the linted bundle in the report
+	if t.ContainerPath != "-" {
+		report.Artifact = t.ContainerPath
+	}
+
+	// Create output writer
+	writer, err := t.OutputWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to open output writer: %w", err)
+	}
+
+	// Render the report
+	if err := ruleset.WriteReport(writer, report, t.ReportFormat); err != nil {
+		return fmt.Errorf("unable to write lint report: %w", err)
+	}
+
+	// Check blocking violations
+	if failures := report.Failures(); len(failures) > 0 {
+		return fmt.Errorf("unable to validate given bundle: %d violation(s) found", len(failures))
+	}
+
+	// No error
+	return nil
+}
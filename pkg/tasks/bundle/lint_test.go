@@ -29,6 +29,8 @@ func TestLintTask_Run(t *testing.T) {
 	type fields struct {
 		ContainerReader tasks.ReaderProvider
 		RuleSetReader   tasks.ReaderProvider
+		OutputWriter    tasks.WriterProvider
+		ReportFormat    string
 	}
 	type args struct {
 		ctx context.Context
@@ -83,6 +85,25 @@ func TestLintTask_Run(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "report - nil outputWriter",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				RuleSetReader:   cmdutil.FileReader("../../../test/fixtures/ruleset/valid/cso.yaml"),
+				ReportFormat:    "json",
+			},
+			wantErr: true,
+		},
+		{
+			name: "report - invalid format",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				RuleSetReader:   cmdutil.FileReader("../../../test/fixtures/ruleset/valid/cso.yaml"),
+				OutputWriter:    cmdutil.DiscardWriter(),
+				ReportFormat:    "html",
+			},
+			wantErr: true,
+		},
 		// ---------------------------------------------------------------------
 		{
 			name: "valid",
@@ -100,12 +121,34 @@ func TestLintTask_Run(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "report - valid",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				RuleSetReader:   cmdutil.FileReader("../../../test/fixtures/ruleset/valid/cso.yaml"),
+				OutputWriter:    cmdutil.DiscardWriter(),
+				ReportFormat:    "sarif",
+			},
+			wantErr: false,
+		},
+		{
+			name: "report - rule violation",
+			fields: fields{
+				ContainerReader: cmdutil.FileReader("../../../test/fixtures/bundles/complete.bundle"),
+				RuleSetReader:   cmdutil.FileReader("../../../test/fixtures/ruleset/valid/database-secret-validator.yaml"),
+				OutputWriter:    cmdutil.DiscardWriter(),
+				ReportFormat:    "junit",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tr := &LintTask{
 				ContainerReader: tt.fields.ContainerReader,
 				RuleSetReader:   tt.fields.RuleSetReader,
+				OutputWriter:    tt.fields.OutputWriter,
+				ReportFormat:    tt.fields.ReportFormat,
 			}
 			if err := tr.Run(tt.args.ctx); (err != nil) != tt.wantErr {
 				t.Errorf("LintTask.Run() error = %v, wantErr %v", err, tt.wantErr)
@@ -46,6 +46,7 @@ type SealTask struct {
 	JSONOutput               bool
 	DisableContainerIdentity bool
 	SealVersion              uint
+	PolicyGate               *tasks.RuleSetGate
 }
 
 // Run the task.
@@ -78,6 +79,12 @@ func (t *SealTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to read input container: %w", err)
 	}
 
+	// Enforce rulesets before sealing
+	in, err = t.PolicyGate.EnforceContainer(ctx, in)
+	if err != nil {
+		return fmt.Errorf("unable to enforce rulesets: %w", err)
+	}
+
 	var containerKey string
 	if !t.DisableContainerIdentity {
 		opts := []seal.GenerateOption{}
@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 	"oras.land/oras-go/pkg/content"
 
+	containerv1 "github.com/elastic/harp/api/gen/go/harp/container/v1"
 	"github.com/elastic/harp/pkg/crate"
 	"github.com/elastic/harp/pkg/crate/cratefile"
 	"github.com/elastic/harp/pkg/sdk/log"
@@ -42,6 +43,7 @@ type PushTask struct {
 	Ref          string
 	JSONOutput   bool
 	RegistryOpts content.RegistryOptions
+	PolicyGate   *tasks.RuleSetGate
 }
 
 // Run the task.
@@ -88,7 +90,10 @@ func (t *PushTask) Run(ctx context.Context) error {
 	log.For(ctx).Info("Building image from context ...", zap.String("context", absContextPath))
 
 	// Prepare image from cratefile
-	img, err := crate.Build(os.DirFS(absContextPath), spec)
+	img, err := crate.Build(os.DirFS(absContextPath), spec, crate.WithContainerHook(func(c *containerv1.Container) (*containerv1.Container, error) {
+		// Enforce rulesets before sealing
+		return t.PolicyGate.EnforceContainer(ctx, c)
+	}))
 	if err != nil {
 		return fmt.Errorf("unable to generate image descriptor from specification: %w", err)
 	}
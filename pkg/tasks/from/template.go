@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/harp/pkg/bundle"
 	"github.com/elastic/harp/pkg/bundle/template"
 	"github.com/elastic/harp/pkg/bundle/template/visitor/secretbuilder"
+	"github.com/elastic/harp/pkg/sdk/types"
 	"github.com/elastic/harp/pkg/tasks"
 	"github.com/elastic/harp/pkg/template/engine"
 )
@@ -59,6 +60,13 @@ func (t *BundleTemplateTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to parse template: %w", err)
 	}
 
+	// Validate values
+	if !types.IsNil(t.TemplateContext) {
+		if err = template.ValidateValues(spec, t.TemplateContext.Values()); err != nil {
+			return fmt.Errorf("unable to validate template values: %w", err)
+		}
+	}
+
 	// Initialize output
 	b := &bundlev1.Bundle{
 		Template: spec,
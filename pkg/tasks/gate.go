@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	containerv1 "github.com/elastic/harp/api/gen/go/harp/container/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/bundle/ruleset"
+	"github.com/elastic/harp/pkg/container"
+	"github.com/elastic/harp/pkg/sdk/types"
+)
+
+// RuleSetChecksumAnnotation is the bundle annotation used to record the
+// checksums of the rulesets enforced before the bundle publication.
+const RuleSetChecksumAnnotation = "harp.elastic.co/v1/ruleset#checksum"
+
+// RuleSetGate describes a policy gate evaluated before a bundle publication.
+type RuleSetGate struct {
+	// RuleSetReaders provides the rulesets to enforce.
+	RuleSetReaders []ReaderProvider
+	// RuleSetPaths holds the ruleset file paths, in the RuleSetReaders order,
+	// used to resolve relative policy paths.
+	RuleSetPaths []string
+	// ReportWriter receives the violation report. The report is attached to
+	// the returned error when not defined.
+	ReportWriter WriterProvider
+}
+
+// Enabled returns true if the gate has rulesets to enforce.
+func (g *RuleSetGate) Enabled() bool {
+	return g != nil && len(g.RuleSetReaders) > 0
+}
+
+// Enforce evaluates all rulesets against the given bundle and aborts on the
+// first ruleset reporting blocking violations. When all rulesets pass, their
+// checksums, including the evaluated policies, are recorded as a bundle
+// annotation so that the published bundle carries the policy-check evidence.
+func (g *RuleSetGate) Enforce(ctx context.Context, b *bundlev1.Bundle) error {
+	// Nothing to enforce
+	if !g.Enabled() {
+		return nil
+	}
+
+	// Check arguments
+	if b == nil {
+		return errors.New("unable to enforce rulesets on a nil bundle")
+	}
+
+	checksums := make([]string, 0, len(g.RuleSetReaders))
+	for i, provider := range g.RuleSetReaders {
+		if types.IsNil(provider) {
+			return fmt.Errorf("unable to enforce ruleset %d: nil reader provider", i)
+		}
+
+		// Create ruleset reader
+		reader, err := provider(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to open ruleset %d reader: %w", i, err)
+		}
+
+		// Parse the ruleset
+		spec, err := ruleset.YAML(reader)
+		if err != nil {
+			return fmt.Errorf("unable to parse ruleset %d: %w", i, err)
+		}
+		if i < len(g.RuleSetPaths) {
+			ruleset.ResolvePaths(spec, g.RuleSetPaths[i])
+		}
+
+		// Compute ruleset and policies checksum
+		checksum, err := ruleset.PolicyChecksum(spec)
+		if err != nil {
+			return fmt.Errorf("unable to compute ruleset '%s' checksum: %w", spec.Meta.Name, err)
+		}
+
+		// Evaluate all rules
+		report, err := ruleset.EvaluateAll(ctx, b, spec)
+		if err != nil {
+			return fmt.Errorf("unable to evaluate ruleset '%s': %w", spec.Meta.Name, err)
+		}
+
+		// Abort on blocking violations
+		if failures := report.Failures(); len(failures) > 0 {
+			return g.reject(ctx, spec.Meta.Name, report)
+		}
+
+		checksums = append(checksums, checksum)
+	}
+
+	// Record enforced rulesets
+	bundle.Annotate(b, RuleSetChecksumAnnotation, strings.Join(checksums, ","))
+
+	// No error
+	return nil
+}
+
+// EnforceContainer unwraps the bundle from the given unsealed container,
+// enforces the rulesets and returns a container wrapping the annotated bundle.
+// The given container is returned as-is when the gate is disabled.
+func (g *RuleSetGate) EnforceContainer(ctx context.Context, c *containerv1.Container) (*containerv1.Container, error) {
+	// Nothing to enforce
+	if !g.Enabled() {
+		return c, nil
+	}
+
+	// Check arguments
+	if types.IsNil(c) {
+		return nil, errors.New("unable to enforce rulesets on a nil container")
+	}
+	if container.IsSealed(c) {
+		return nil, errors.New("unable to enforce rulesets on a sealed container")
+	}
+
+	// Extract the bundle
+	b, err := bundle.FromContainer(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load bundle from container: %w", err)
+	}
+
+	// Enforce rulesets
+	if err := g.Enforce(ctx, b); err != nil {
+		return nil, err
+	}
+
+	// Wrap the annotated bundle
+	out, err := bundle.ToContainer(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wrap bundle in a container: %w", err)
+	}
+
+	// No error
+	return out, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (g *RuleSetGate) reject(ctx context.Context, name string, report *ruleset.Report) error {
+	errRejected := fmt.Errorf("bundle rejected by ruleset '%s': %d violation(s) found", name, len(report.Failures()))
+
+	// Attach the report to the error
+	if types.IsNil(g.ReportWriter) {
+		var buf bytes.Buffer
+		if err := ruleset.WriteReport(&buf, report, ruleset.ReportFormatText); err != nil {
+			return fmt.Errorf("unable to render violation report: %w", err)
+		}
+
+		return fmt.Errorf("%w\n%s", errRejected, strings.TrimSpace(buf.String()))
+	}
+
+	// Create report writer
+	writer, err := g.ReportWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to open report writer: %w", err)
+	}
+
+	// Render the report
+	if err := ruleset.WriteReport(writer, report, ruleset.ReportFormatText); err != nil {
+		return fmt.Errorf("unable to write violation report: %w", err)
+	}
+
+	return errRejected
+}
@@ -0,0 +1,197 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tasks_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/container"
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+func TestRuleSetGate_Enforce(t *testing.T) {
+	tests := []struct {
+		name           string
+		gate           *tasks.RuleSetGate
+		wantErr        bool
+		wantAnnotation bool
+		wantReport     string
+	}{
+		{
+			name: "nil gate",
+		},
+		{
+			name: "no ruleset",
+			gate: &tasks.RuleSetGate{},
+		},
+		{
+			name: "nil reader provider",
+			gate: &tasks.RuleSetGate{
+				RuleSetReaders: []tasks.ReaderProvider{nil},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ruleset reader error",
+			gate: &tasks.RuleSetGate{
+				RuleSetReaders: []tasks.ReaderProvider{
+					cmdutil.FileReader("non-existent.yaml"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid ruleset",
+			gate: &tasks.RuleSetGate{
+				RuleSetReaders: []tasks.ReaderProvider{
+					cmdutil.FileReader("../../test/fixtures/bundles/complete.bundle"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			gate: &tasks.RuleSetGate{
+				RuleSetReaders: []tasks.ReaderProvider{
+					cmdutil.FileReader("../../test/fixtures/ruleset/valid/cso.yaml"),
+				},
+			},
+			wantAnnotation: true,
+		},
+		{
+			name: "rule violation",
+			gate: &tasks.RuleSetGate{
+				RuleSetReaders: []tasks.ReaderProvider{
+					cmdutil.FileReader("../../test/fixtures/ruleset/valid/cso.yaml"),
+					cmdutil.FileReader("../../test/fixtures/ruleset/valid/database-secret-validator.yaml"),
+				},
+			},
+			wantErr:    true,
+			wantReport: "violation(s) found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bundle.FromContainerReader(mustReader(t, "../../test/fixtures/bundles/complete.bundle"))
+			if err != nil {
+				t.Fatalf("unable to load bundle: %v", err)
+			}
+
+			// Capture the report
+			var report bytes.Buffer
+			if tt.gate != nil {
+				tt.gate.ReportWriter = cmdutil.DirectWriter(&report)
+			}
+
+			err = tt.gate.Enforce(context.Background(), b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RuleSetGate.Enforce() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			_, annotated := b.Annotations[tasks.RuleSetChecksumAnnotation]
+			if annotated != tt.wantAnnotation {
+				t.Errorf("RuleSetGate.Enforce() annotated = %v, want %v", annotated, tt.wantAnnotation)
+			}
+			for _, p := range b.Packages {
+				if _, ok := p.Annotations[tasks.RuleSetChecksumAnnotation]; ok {
+					t.Errorf("RuleSetGate.Enforce() package '%s' must not be annotated", p.Name)
+				}
+			}
+			if !strings.Contains(report.String(), tt.wantReport) {
+				t.Errorf("RuleSetGate.Enforce() report = %q, want %q", report.String(), tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestRuleSetGate_Enforce_ReportInError(t *testing.T) {
+	b, err := bundle.FromContainerReader(mustReader(t, "../../test/fixtures/bundles/complete.bundle"))
+	if err != nil {
+		t.Fatalf("unable to load bundle: %v", err)
+	}
+
+	gate := &tasks.RuleSetGate{
+		RuleSetReaders: []tasks.ReaderProvider{
+			cmdutil.FileReader("../../test/fixtures/ruleset/valid/database-secret-validator.yaml"),
+		},
+	}
+
+	err = gate.Enforce(context.Background(), b)
+	if err == nil {
+		t.Fatal("RuleSetGate.Enforce() expected an error")
+	}
+	if !strings.Contains(err.Error(), "rule(s) evaluated") {
+		t.Errorf("RuleSetGate.Enforce() error = %v, expected the violation report", err)
+	}
+}
+
+func TestRuleSetGate_EnforceContainer(t *testing.T) {
+	c, err := container.Load(mustReader(t, "../../test/fixtures/bundles/complete.bundle"))
+	if err != nil {
+		t.Fatalf("unable to load container: %v", err)
+	}
+
+	// Disabled gate returns the input container
+	var disabled *tasks.RuleSetGate
+	out, err := disabled.EnforceContainer(context.Background(), c)
+	if err != nil || out != c {
+		t.Fatalf("RuleSetGate.EnforceContainer() = %v, %v, expected input container", out, err)
+	}
+
+	gate := &tasks.RuleSetGate{
+		RuleSetReaders: []tasks.ReaderProvider{
+			cmdutil.FileReader("../../test/fixtures/ruleset/valid/cso.yaml"),
+		},
+	}
+
+	// Nil container
+	if _, err := gate.EnforceContainer(context.Background(), nil); err == nil {
+		t.Error("RuleSetGate.EnforceContainer() expected an error for nil container")
+	}
+
+	// Annotated container
+	out, err = gate.EnforceContainer(context.Background(), c)
+	if err != nil {
+		t.Fatalf("RuleSetGate.EnforceContainer() error = %v", err)
+	}
+	b, err := bundle.FromContainer(out)
+	if err != nil {
+		t.Fatalf("unable to load bundle from container: %v", err)
+	}
+	if b.Annotations[tasks.RuleSetChecksumAnnotation] == "" {
+		t.Error("RuleSetGate.EnforceContainer() expected a ruleset checksum annotation")
+	}
+}
+
+func mustReader(t *testing.T, path string) *bytes.Reader {
+	t.Helper()
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read '%s': %v", path, err)
+	}
+
+	return bytes.NewReader(payload)
+}
@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package keygen
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+// OpenSSHTask implements OpenSSH key pair generation.
+type OpenSSHTask struct {
+	KeyType         string
+	OutputWriter    tasks.WriterProvider
+	PublicKeyWriter tasks.WriterProvider
+}
+
+// Run the task.
+func (t *OpenSSHTask) Run(ctx context.Context) error {
+	// Check arguments
+	if types.IsNil(t.OutputWriter) {
+		return fmt.Errorf("unable to run task with a nil outputWriter provider")
+	}
+
+	// Generate key pair
+	kp, err := crypto.Keypair(fmt.Sprintf("ssh:%s", t.KeyType))
+	if err != nil {
+		return fmt.Errorf("unable to generate key pair: %w", err)
+	}
+	pair, ok := kp.(struct {
+		Private interface{}
+		Public  interface{}
+	})
+	if !ok {
+		return fmt.Errorf("unexpected key pair type %T", kp)
+	}
+
+	// Write private key
+	writer, err := t.OutputWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to open output writer: %w", err)
+	}
+	if _, err := fmt.Fprint(writer, pair.Private); err != nil {
+		return fmt.Errorf("unable to write private key: %w", err)
+	}
+
+	// Write public key
+	if !types.IsNil(t.PublicKeyWriter) {
+		pubWriter, err := t.PublicKeyWriter(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to open public key writer: %w", err)
+		}
+		if _, err := fmt.Fprint(pubWriter, pair.Public); err != nil {
+			return fmt.Errorf("unable to write public key: %w", err)
+		}
+	}
+
+	// No error
+	return nil
+}
@@ -53,6 +53,7 @@ var schemaRegistry = map[string]struct {
 	"Bundle":         {Definition: bundle.JSONSchema(), LintFunc: bundle.Lint},
 	"BundlePatch":    {Definition: patch.JSONSchema(), LintFunc: patch.Lint},
 	"RuleSet":        {Definition: ruleset.JSONSchema(), LintFunc: ruleset.Lint},
+	"RuleSetTest":    {Definition: ruleset.SuiteJSONSchema(), LintFunc: ruleset.LintSuite},
 	"BundleTemplate": {Definition: template.JSONSchema(), LintFunc: template.Lint},
 }
 
@@ -0,0 +1,175 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
+	"github.com/elastic/harp/pkg/bundle/ruleset"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+// TestTask implements ruleset unit testing task.
+type TestTask struct {
+	Paths        []string
+	OutputWriter tasks.WriterProvider
+	Verbose      bool
+}
+
+// Run the task.
+func (t *TestTask) Run(ctx context.Context) error {
+	// Check arguments
+	if len(t.Paths) == 0 {
+		return errors.New("unable to run task without test paths")
+	}
+	if types.IsNil(t.OutputWriter) {
+		return errors.New("unable to run task with a nil outputWriter provider")
+	}
+
+	// Discover test files
+	files, err := discover(t.Paths)
+	if err != nil {
+		return fmt.Errorf("unable to discover ruleset test files: %w", err)
+	}
+	if len(files) == 0 {
+		return errors.New("no ruleset test files found")
+	}
+
+	// Create output writer
+	writer, err := t.OutputWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to open output writer: %w", err)
+	}
+
+	failed := 0
+	for _, file := range files {
+		// Run the test suite
+		report, err := runFile(ctx, file)
+		if err != nil {
+			return fmt.Errorf("unable to run '%s' test suite: %w", file, err)
+		}
+
+		// Render the result
+		if err := ruleset.WriteSuiteReport(writer, report, file, t.Verbose); err != nil {
+			return fmt.Errorf("unable to write test report: %w", err)
+		}
+		if !report.Passed() {
+			failed++
+		}
+	}
+
+	// Check failures
+	if failed > 0 {
+		return fmt.Errorf("%d ruleset test suite(s) failed", failed)
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// discover returns test files from the given paths. Directories are walked
+// recursively to find `*_test.yaml` and `*_test.yml` files.
+func discover(paths []string) ([]string, error) {
+	res := []string{}
+	for _, root := range paths {
+		fi, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("unable to access '%s': %w", root, err)
+		}
+
+		// Explicit test file
+		if !fi.IsDir() {
+			res = append(res, root)
+			continue
+		}
+
+		if err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if strings.HasSuffix(d.Name(), "_test.yaml") || strings.HasSuffix(d.Name(), "_test.yml") {
+				res = append(res, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("unable to walk '%s': %w", root, err)
+		}
+	}
+
+	// Stable execution order
+	sort.Strings(res)
+
+	return res, nil
+}
+
+func runFile(ctx context.Context, path string) (*ruleset.SuiteReport, error) {
+	// Parse the test specification
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open test file: %w", err)
+	}
+	defer f.Close()
+
+	spec, err := ruleset.SuiteYAML(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse test file: %w", err)
+	}
+
+	// Load the ruleset relatively to the test file
+	baseDir := filepath.Dir(path)
+	rs, err := loadRuleSet(filepath.Join(baseDir, spec.Spec.RuleSet))
+	if err != nil {
+		return nil, err
+	}
+
+	// No error
+	return ruleset.RunSuite(ctx, spec, rs, baseDir)
+}
+
+func loadRuleSet(path string) (*bundlev1.RuleSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open ruleset file: %w", err)
+	}
+	defer f.Close()
+
+	rs, err := ruleset.YAML(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ruleset file: %w", err)
+	}
+
+	// Resolve policy paths relatively to the ruleset file
+	ruleset.ResolvePaths(rs, path)
+
+	// No error
+	return rs, nil
+}
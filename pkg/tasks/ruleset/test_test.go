@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ruleset
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/tasks"
+)
+
+func TestTestTask_Run(t *testing.T) {
+	type fields struct {
+		Paths        []string
+		OutputWriter tasks.WriterProvider
+		Verbose      bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name: "nil outputWriter",
+			fields: fields{
+				Paths: []string{"../../../test/fixtures/ruleset/tests"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-existent path",
+			fields: fields{
+				Paths:        []string{"non-existent"},
+				OutputWriter: cmdutil.DiscardWriter(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "no test files",
+			fields: fields{
+				Paths:        []string{"../../../test/fixtures/ruleset/valid"},
+				OutputWriter: cmdutil.DiscardWriter(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid test file",
+			fields: fields{
+				Paths:        []string{"../../../test/fixtures/ruleset/valid/cso.yaml"},
+				OutputWriter: cmdutil.DiscardWriter(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid - file",
+			fields: fields{
+				Paths:        []string{"../../../test/fixtures/ruleset/tests/harp-server_test.yaml"},
+				OutputWriter: cmdutil.DiscardWriter(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid - directory",
+			fields: fields{
+				Paths:        []string{"../../../test/fixtures/ruleset/tests"},
+				OutputWriter: cmdutil.DiscardWriter(),
+				Verbose:      true,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TestTask{
+				Paths:        tt.fields.Paths,
+				OutputWriter: tt.fields.OutputWriter,
+				Verbose:      tt.fields.Verbose,
+			}
+			if err := tr.Run(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("TestTask.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTestTask_Run_Output(t *testing.T) {
+	out := &bytes.Buffer{}
+	tr := &TestTask{
+		Paths:        []string{"../../../test/fixtures/ruleset/tests"},
+		OutputWriter: cmdutil.DirectWriter(out),
+	}
+	assert.NoError(t, tr.Run(context.Background()))
+	assert.Contains(t, out.String(), "ok  \t../../../test/fixtures/ruleset/tests/harp-bundle_test.yaml\t")
+	assert.Contains(t, out.String(), "coverage: 100.0% of rules (3/3)")
+	assert.Contains(t, out.String(), "ok  \t../../../test/fixtures/ruleset/tests/harp-server_test.yaml\t")
+	assert.Contains(t, out.String(), "coverage: 66.7% of rules (2/3)")
+	assert.NotContains(t, out.String(), "=== RUN")
+}
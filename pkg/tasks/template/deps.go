@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/harp/pkg/sdk/fsutil"
+	"github.com/elastic/harp/pkg/sdk/types"
+	"github.com/elastic/harp/pkg/tasks"
+	"github.com/elastic/harp/pkg/template/engine"
+	"github.com/elastic/harp/pkg/template/values/schema"
+)
+
+// DependenciesTask implements template dependencies analysis task.
+type DependenciesTask struct {
+	InputPath    string
+	OutputWriter tasks.WriterProvider
+	OutputFormat string
+	LeftDelims   string
+	RightDelims  string
+	AltDelims    bool
+}
+
+// Run the task.
+func (t *DependenciesTask) Run(ctx context.Context) error {
+	// Check arguments
+	if t.InputPath == "" {
+		return errors.New("unable to run task with a blank input path")
+	}
+	if types.IsNil(t.OutputWriter) {
+		return errors.New("unable to run task with a nil outputWriter provider")
+	}
+	switch t.OutputFormat {
+	case "", "json", "dot":
+	default:
+		return fmt.Errorf("unsupported output format '%s'", t.OutputFormat)
+	}
+
+	// If alternative delimiters is used
+	if t.AltDelims {
+		t.LeftDelims = "[["
+		t.RightDelims = "]]"
+	}
+
+	// Prepare input filesystem
+	inFS, files, err := t.inputFiles()
+	if err != nil {
+		return err
+	}
+
+	var (
+		deps  = &engine.Dependencies{}
+		graph = map[string]*engine.Dependencies{}
+	)
+	for _, path := range files {
+		// Get file content.
+		body, err := fs.ReadFile(inFS, path)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve file content %q: %w", path, err)
+		}
+
+		// Analyze template
+		fileDeps, err := engine.AnalyzeContext(engine.NewContext(
+			engine.WithName(path),
+			engine.WithDelims(t.LeftDelims, t.RightDelims),
+		), string(body))
+		if err != nil {
+			return fmt.Errorf("unable to analyze file %q: %w", path, err)
+		}
+
+		graph[path] = fileDeps
+		deps.Merge(fileDeps)
+	}
+
+	// Create output writer
+	writer, err := t.OutputWriter(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to open output writer: %w", err)
+	}
+
+	switch t.OutputFormat {
+	case "", "json":
+		// Encode as JSON
+		if err := json.NewEncoder(writer).Encode(deps); err != nil {
+			return fmt.Errorf("unable to marshal JSON dependencies: %w", err)
+		}
+	case "dot":
+		if err := writeDependencyGraph(writer, files, graph); err != nil {
+			return fmt.Errorf("unable to write dependency graph: %w", err)
+		}
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (t *DependenciesTask) inputFiles() (fs.FS, []string, error) {
+	// Check input path
+	fi, err := os.Stat(t.InputPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to retrieve input information: %w", err)
+	}
+
+	// Single template file
+	if !fi.IsDir() && !strings.HasSuffix(t.InputPath, ".tar.gz") {
+		return os.DirFS(filepath.Dir(t.InputPath)), []string{filepath.Base(t.InputPath)}, nil
+	}
+
+	// Prepare input filesystem
+	inFS, err := fsutil.From(t.InputPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to prepare input filesystem: %w", err)
+	}
+
+	files := []string{}
+	if err := fs.WalkDir(inFS, ".", func(path string, d fs.DirEntry, errWalk error) error {
+		if errWalk != nil {
+			return errWalk
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		// Skip the embedded values schema
+		if path == schema.DefaultFileName {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	}); err != nil {
+		return nil, nil, fmt.Errorf("unable to list template files: %w", err)
+	}
+
+	// No error
+	return inFS, files, nil
+}
+
+func writeDependencyGraph(w io.Writer, files []string, graph map[string]*engine.Dependencies) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, path := range files {
+		deps := graph[path]
+		fmt.Fprintf(&sb, "  %s [shape=note];\n", strconv.Quote(path))
+
+		for _, kind := range []struct {
+			prefix string
+			deps   []*engine.Dependency
+		}{
+			{prefix: "secret", deps: deps.Secrets},
+			{prefix: "values", deps: deps.Values},
+			{prefix: "file", deps: deps.Files},
+		} {
+			for _, d := range kind.deps {
+				style := ""
+				if d.Unresolved {
+					style = " [style=dashed]"
+				}
+				fmt.Fprintf(&sb, "  %s -> %s%s;\n", strconv.Quote(path), strconv.Quote(fmt.Sprintf("%s:%s", kind.prefix, d.Name)), style)
+			}
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -19,13 +19,18 @@ package template
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path"
+	"strings"
 
 	"github.com/psanford/memfs"
 
 	"github.com/elastic/harp/pkg/sdk/fsutil"
 	"github.com/elastic/harp/pkg/template/engine"
+	"github.com/elastic/harp/pkg/template/manifest"
+	"github.com/elastic/harp/pkg/template/values/schema"
 )
 
 // FileSystemTask implements filesystem template rendering task.
@@ -37,16 +42,20 @@ type FileSystemTask struct {
 	Values             []string
 	StringValues       []string
 	FileValues         []string
+	ValuesSchema       string
 	LeftDelims         string
 	RightDelims        string
 	AltDelims          bool
 	FileLoaderRootPath string
+	LibraryPaths       []string
+	PartialPatterns    []string
+	ManifestPath       string
 	DryRun             bool
+	Sandbox            *engine.Sandbox
+	Deterministic      *engine.Deterministic
 }
 
 // Run the task.
-//
-//nolint:revive // refactor use of ctx
 func (t *FileSystemTask) Run(ctx context.Context) error {
 	// Prepare input filesystem
 	inFS, err := fsutil.From(t.InputPath)
@@ -54,6 +63,15 @@ func (t *FileSystemTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to prepare input filesystem: %w", err)
 	}
 
+	// Load render manifest
+	var renderManifest *manifest.RenderManifest
+	if t.ManifestPath != "" {
+		renderManifest, err = loadManifest(t.ManifestPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Prepare embedded files
 	var (
 		fileRootFS fs.FS
@@ -66,22 +84,60 @@ func (t *FileSystemTask) Run(ctx context.Context) error {
 		}
 	}
 
+	// Use the values schema embedded in the input filesystem if not specified
+	var valuesSchema []byte
+	if t.ValuesSchema == "" {
+		valuesSchema, err = fs.ReadFile(inFS, schema.DefaultFileName)
+		switch {
+		case err == nil:
+		case errors.Is(err, fs.ErrNotExist):
+			valuesSchema = nil
+		default:
+			return fmt.Errorf("unable to read embedded values schema: %w", err)
+		}
+	}
+
+	// Check partial template globs
+	partialPatterns := t.PartialPatterns
+	for _, pattern := range partialPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid partial template glob '%s': %w", pattern, err)
+		}
+	}
+
+	// Load partial templates shared by all files
+	partials, err := loadPartials(inFS, partialPatterns)
+	if err != nil {
+		return fmt.Errorf("unable to load partial templates: %w", err)
+	}
+
 	// Prepare render context
-	renderCtx, err := prepareRenderContext(&renderContextConfig{
+	renderCtx, err := prepareRenderContext(ctx, &renderContextConfig{
 		ValueFiles:    t.ValueFiles,
 		SecretLoaders: t.SecretLoaders,
 		Values:        t.Values,
 		StringValues:  t.StringValues,
 		FileValues:    t.FileValues,
+		SchemaFile:    t.ValuesSchema,
+		Schema:        valuesSchema,
 		LeftDelims:    t.LeftDelims,
 		RightDelims:   t.RightDelims,
 		AltDelims:     t.AltDelims,
 		FileRootPath:  fileRootFS,
+		LibraryPaths:  t.LibraryPaths,
+		Partials:      partials,
+		Sandbox:       t.Sandbox,
+		Deterministic: t.Deterministic,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to prepare rendering context: %w", err)
 	}
 
+	// Render files declared in the manifest
+	if renderManifest != nil {
+		return t.renderManifest(ctx, inFS, renderCtx, renderManifest)
+	}
+
 	// Memory filesystem
 	outFs := memfs.New()
 
@@ -94,6 +150,16 @@ func (t *FileSystemTask) Run(ctx context.Context) error {
 			return outFs.MkdirAll(path, 0o755)
 		}
 
+		// Skip the embedded values schema
+		if len(valuesSchema) > 0 && path == schema.DefaultFileName {
+			return nil
+		}
+
+		// Skip partial templates
+		if isPartial(path, partialPatterns) {
+			return nil
+		}
+
 		// Get file content.
 		body, err := fs.ReadFile(inFS, path)
 		if err != nil {
@@ -120,3 +186,49 @@ func (t *FileSystemTask) Run(ctx context.Context) error {
 	// Dump filesystem
 	return fsutil.Dump(outFs, t.OutputPath)
 }
+
+// -----------------------------------------------------------------------------
+
+// isPartial returns true if the given file is a partial template, only used
+// to share named template definitions. Globs containing a separator are
+// matched against the file path, other globs against the file name.
+func isPartial(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := path.Base(filePath)
+		if strings.Contains(pattern, "/") {
+			name = filePath
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadPartials(inFS fs.FS, patterns []string) (map[string]string, error) {
+	partials := map[string]string{}
+
+	if err := fs.WalkDir(inFS, ".", func(filePath string, d fs.DirEntry, errWalk error) error {
+		if errWalk != nil {
+			return errWalk
+		}
+		if d.IsDir() || !isPartial(filePath, patterns) {
+			return nil
+		}
+
+		// Get file content.
+		body, err := fs.ReadFile(inFS, filePath)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve file content %q: %w", filePath, err)
+		}
+
+		partials[filePath] = string(body)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// No error
+	return partials, nil
+}
@@ -0,0 +1,161 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/elastic/harp/pkg/sdk/log"
+	"github.com/elastic/harp/pkg/template/engine"
+	"github.com/elastic/harp/pkg/template/manifest"
+)
+
+type manifestOutput struct {
+	file    *manifest.File
+	path    string
+	content []byte
+	opts    *manifest.FileOptions
+}
+
+// loadManifest opens and validates the render manifest.
+func loadManifest(manifestPath string) (*manifest.RenderManifest, error) {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open render manifest: %w", err)
+	}
+	defer f.Close()
+
+	m, err := manifest.Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid render manifest %q: %w", manifestPath, err)
+	}
+
+	// No error
+	return m, nil
+}
+
+// renderManifest renders templates declared in the manifest, writes changed
+// outputs and runs the notified hooks.
+func (t *FileSystemTask) renderManifest(ctx context.Context, inFS fs.FS, renderCtx engine.Context, m *manifest.RenderManifest) error {
+	// Render all templates before writing any file
+	outputs := make([]*manifestOutput, 0, len(m.Spec.Files))
+	for _, f := range m.Spec.Files {
+		// Get file content.
+		body, err := fs.ReadFile(inFS, f.Template)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve file content %q: %w", f.Template, err)
+		}
+
+		// Compile and execute template
+		out, err := engine.RenderContext(renderCtx, string(body))
+		if err != nil {
+			return fmt.Errorf("unable to produce output content for file %q: %w", f.Template, err)
+		}
+
+		opts, err := f.Options()
+		if err != nil {
+			return fmt.Errorf("invalid options for file %q: %w", f.Template, err)
+		}
+
+		// Resolve destination
+		destination := f.Destination
+		if !filepath.IsAbs(destination) {
+			destination = filepath.Join(t.OutputPath, destination)
+		}
+
+		outputs = append(outputs, &manifestOutput{
+			file:    f,
+			path:    destination,
+			content: []byte(out),
+			opts:    opts,
+		})
+	}
+
+	// Write changed files
+	notified := []*manifest.File{}
+	pending := []string{}
+	for _, o := range outputs {
+		updated, err := manifest.Changed(o.path, o.content, o.opts)
+		if err != nil {
+			return fmt.Errorf("unable to write file %q: %w", o.path, err)
+		}
+
+		// Hooks of a previous failed render are retried
+		retry, err := manifest.Pending(o.path)
+		if err != nil {
+			return err
+		}
+		if !updated && !retry {
+			log.For(ctx).Debug("File unchanged", zap.String("path", o.path))
+			continue
+		}
+
+		notified = append(notified, o.file)
+		if retry {
+			log.For(ctx).Info("File has pending hooks", zap.String("path", o.path), zap.Bool("dry-run", t.DryRun))
+		}
+		if t.DryRun {
+			if updated {
+				log.For(ctx).Info("File updated", zap.String("path", o.path), zap.Bool("dry-run", t.DryRun))
+			}
+			continue
+		}
+
+		// Mark hooks as pending before replacing the file
+		if err := manifest.MarkPending(o.path); err != nil {
+			return err
+		}
+		pending = append(pending, o.path)
+
+		if updated {
+			if _, err := manifest.WriteFile(o.path, o.content, o.opts); err != nil {
+				return fmt.Errorf("unable to write file %q: %w", o.path, err)
+			}
+			log.For(ctx).Info("File updated", zap.String("path", o.path), zap.Bool("dry-run", t.DryRun))
+		}
+	}
+
+	// Run notified hooks
+	for _, h := range m.Notified(notified) {
+		if t.DryRun {
+			log.For(ctx).Info("Hook notified", zap.String("hook", h.Name), zap.Bool("dry-run", t.DryRun))
+			continue
+		}
+
+		log.For(ctx).Info("Running hook ...", zap.String("hook", h.Name))
+		if err := h.Run(ctx, os.Stderr, os.Stderr); err != nil {
+			return fmt.Errorf("unable to run post-render hook: %w", err)
+		}
+	}
+
+	// Clear pending hooks once all of them succeeded
+	for _, p := range pending {
+		if err := manifest.ClearPending(p); err != nil {
+			return err
+		}
+	}
+
+	// No error
+	return nil
+}
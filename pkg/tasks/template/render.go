@@ -23,16 +23,12 @@ import (
 	"io"
 	"io/fs"
 
-	"github.com/hashicorp/vault/api"
-
-	"github.com/elastic/harp/pkg/bundle"
-	"github.com/elastic/harp/pkg/sdk/cmdutil"
 	"github.com/elastic/harp/pkg/sdk/fsutil"
 	"github.com/elastic/harp/pkg/sdk/types"
 	"github.com/elastic/harp/pkg/tasks"
 	tplcmdutil "github.com/elastic/harp/pkg/template/cmdutil"
 	"github.com/elastic/harp/pkg/template/engine"
-	"github.com/elastic/harp/pkg/vault/kv"
+	"github.com/elastic/harp/pkg/template/values/schema"
 )
 
 // RenderTask implements single template rendering task.
@@ -44,10 +40,14 @@ type RenderTask struct {
 	Values        []string
 	StringValues  []string
 	FileValues    []string
+	ValuesSchema  string
 	LeftDelims    string
 	RightDelims   string
 	AltDelims     bool
 	RootPath      string
+	LibraryPaths  []string
+	Sandbox       *engine.Sandbox
+	Deterministic *engine.Deterministic
 }
 
 // Run the task.
@@ -79,16 +79,20 @@ func (t *RenderTask) Run(ctx context.Context) error {
 	}
 
 	// Prepare render context
-	renderCtx, err := prepareRenderContext(&renderContextConfig{
+	renderCtx, err := prepareRenderContext(ctx, &renderContextConfig{
 		ValueFiles:    t.ValueFiles,
 		SecretLoaders: t.SecretLoaders,
 		Values:        t.Values,
 		StringValues:  t.StringValues,
 		FileValues:    t.FileValues,
+		SchemaFile:    t.ValuesSchema,
 		LeftDelims:    t.LeftDelims,
 		RightDelims:   t.RightDelims,
 		AltDelims:     t.AltDelims,
 		FileRootPath:  fileRootFS,
+		LibraryPaths:  t.LibraryPaths,
+		Sandbox:       t.Sandbox,
+		Deterministic: t.Deterministic,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to prepare rendering context: %w", err)
@@ -121,25 +125,39 @@ type renderContextConfig struct {
 	Values        []string
 	StringValues  []string
 	FileValues    []string
+	SchemaFile    string
+	Schema        []byte
 	LeftDelims    string
 	RightDelims   string
 	AltDelims     bool
 	FileRootPath  fs.FS
+	LibraryPaths  []string
+	Partials      map[string]string
+	Sandbox       *engine.Sandbox
+	Deterministic *engine.Deterministic
 }
 
-func prepareRenderContext(cfg *renderContextConfig) (engine.Context, error) {
+func prepareRenderContext(ctx context.Context, cfg *renderContextConfig) (engine.Context, error) {
 	// Load values
 	valueOpts := tplcmdutil.ValueOptions{
 		ValueFiles:   cfg.ValueFiles,
 		Values:       cfg.Values,
 		StringValues: cfg.StringValues,
 		FileValues:   cfg.FileValues,
+		SchemaFile:   cfg.SchemaFile,
 	}
 	values, err := valueOpts.MergeValues()
 	if err != nil {
 		return nil, fmt.Errorf("unable to process input values: %w", err)
 	}
 
+	// Validate values with the embedded schema
+	if len(cfg.Schema) > 0 {
+		if errSchema := schema.Validate(cfg.Schema, values); errSchema != nil {
+			return nil, fmt.Errorf("invalid input values: %w", errSchema)
+		}
+	}
+
 	// Load files
 	var files engine.Files
 	if !types.IsNil(cfg.FileRootPath) {
@@ -157,33 +175,18 @@ func prepareRenderContext(cfg *renderContextConfig) (engine.Context, error) {
 	}
 
 	// Process secret readers
-	secretReaders := []engine.SecretReaderFunc{}
-	for _, sr := range cfg.SecretLoaders {
-		if sr == "vault" {
-			// Initialize Vault connection
-			vaultClient, errVault := api.NewClient(api.DefaultConfig())
-			if errVault != nil {
-				return nil, fmt.Errorf("unable to initialize vault secret loader: %w", errVault)
-			}
-
-			secretReaders = append(secretReaders, kv.SecretGetter(vaultClient))
-			continue
-		}
-
-		// Read container
-		containerReader, errLoader := cmdutil.Reader(sr)
-		if errLoader != nil {
-			return nil, fmt.Errorf("unable to read secret container: %w", errLoader)
-		}
-
-		// Load container
-		b, errBundle := bundle.FromContainerReader(containerReader)
-		if errBundle != nil {
-			return nil, fmt.Errorf("unable to decode secret container: %w", err)
-		}
+	secretReaders, err := tplcmdutil.SecretReaders(ctx, cfg.SecretLoaders)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize secret readers: %w", err)
+	}
 
-		// Append secret loader
-		secretReaders = append(secretReaders, bundle.SecretReader(b))
+	// Load template libraries
+	libraries, err := tplcmdutil.Libraries(cfg.LibraryPaths)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load template libraries: %w", err)
+	}
+	for name, content := range cfg.Partials {
+		libraries[name] = content
 	}
 
 	// Create rendering context
@@ -193,6 +196,9 @@ func prepareRenderContext(cfg *renderContextConfig) (engine.Context, error) {
 		engine.WithValues(values),
 		engine.WithFiles(files),
 		engine.WithSecretReaders(secretReaders...),
+		engine.WithSandbox(cfg.Sandbox),
+		engine.WithDeterministic(cfg.Deterministic),
+		engine.WithLibraries(libraries),
 	)
 
 	// No error
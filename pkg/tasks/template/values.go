@@ -33,6 +33,7 @@ type ValueTask struct {
 	Values       []string
 	StringValues []string
 	FileValues   []string
+	ValuesSchema string
 }
 
 // Run the task.
@@ -43,6 +44,7 @@ func (t *ValueTask) Run(ctx context.Context) error {
 		Values:       t.Values,
 		StringValues: t.StringValues,
 		FileValues:   t.FileValues,
+		SchemaFile:   t.ValuesSchema,
 	}
 	values, err := valueOpts.MergeValues()
 	if err != nil {
@@ -40,6 +40,7 @@ type GithubActionTask struct {
 	Owner           string
 	Repository      string
 	SecretFilter    string
+	PolicyGate      *tasks.RuleSetGate
 }
 
 func (t *GithubActionTask) Run(ctx context.Context) error {
@@ -55,6 +56,11 @@ func (t *GithubActionTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to load bundle: %w", err)
 	}
 
+	// Enforce rulesets before publication
+	if err := t.PolicyGate.Enforce(ctx, b); err != nil {
+		return fmt.Errorf("unable to enforce rulesets: %w", err)
+	}
+
 	// Prepae github API client
 	client, err := t.prepareClient(ctx)
 	if err != nil {
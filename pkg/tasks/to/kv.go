@@ -34,6 +34,7 @@ type PublishKVTask struct {
 	Store           kv.Store
 	SecretAsKey     bool
 	Prefix          string
+	PolicyGate      *tasks.RuleSetGate
 }
 
 func (t *PublishKVTask) Run(ctx context.Context) error {
@@ -49,6 +50,11 @@ func (t *PublishKVTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to load bundle: %w", err)
 	}
 
+	// Enforce rulesets before publication
+	if err := t.PolicyGate.Enforce(ctx, b); err != nil {
+		return fmt.Errorf("unable to enforce rulesets: %w", err)
+	}
+
 	// Convert as map
 	bundleMap, err := bundle.AsMap(b)
 	if err != nil {
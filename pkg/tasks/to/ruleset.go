@@ -21,36 +21,39 @@ import (
 	"context"
 	"fmt"
 
+	bundlev1 "github.com/elastic/harp/api/gen/go/harp/bundle/v1"
 	"github.com/elastic/harp/pkg/bundle"
 	"github.com/elastic/harp/pkg/bundle/ruleset"
+	"github.com/elastic/harp/pkg/bundle/template"
 	"github.com/elastic/harp/pkg/sdk/convert"
 	"github.com/elastic/harp/pkg/tasks"
+	"github.com/elastic/harp/pkg/template/engine"
 )
 
-// RuleSetTask implements RuleSet generation from a bundle.
+// RuleSetTask implements RuleSet generation from a bundle or a bundle template.
 type RuleSetTask struct {
-	ContainerReader tasks.ReaderProvider
-	OutputWriter    tasks.WriterProvider
+	ContainerReader  tasks.ReaderProvider
+	TemplateReader   tasks.ReaderProvider
+	TemplateContext  engine.Context
+	OutputWriter     tasks.WriterProvider
+	InferValues      bool
+	CollapsePackages bool
 }
 
 // Run the task.
 func (t *RuleSetTask) Run(ctx context.Context) error {
-	// Create input reader
-	reader, err := t.ContainerReader(ctx)
-	if err != nil {
-		return fmt.Errorf("unable to initialize bundle reader: %w", err)
-	}
+	var (
+		rs  *bundlev1.RuleSet
+		err error
+	)
 
-	// Load bundle
-	b, err := bundle.FromContainerReader(reader)
-	if err != nil {
-		return fmt.Errorf("unable to load bundle content: %w", err)
+	if t.TemplateReader != nil {
+		rs, err = t.fromTemplate(ctx)
+	} else {
+		rs, err = t.fromBundle(ctx)
 	}
-
-	// Generate ruleset
-	rs, err := ruleset.FromBundle(b)
 	if err != nil {
-		return fmt.Errorf("unable to generate RuleSet from given bundle: %w", err)
+		return err
 	}
 
 	// Marshal as YAML
@@ -71,3 +74,54 @@ func (t *RuleSetTask) Run(ctx context.Context) error {
 	// No error
 	return nil
 }
+
+// -----------------------------------------------------------------------------
+
+func (t *RuleSetTask) fromBundle(ctx context.Context) (*bundlev1.RuleSet, error) {
+	// Create input reader
+	reader, err := t.ContainerReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize bundle reader: %w", err)
+	}
+
+	// Load bundle
+	b, err := bundle.FromContainerReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load bundle content: %w", err)
+	}
+
+	// Generate ruleset
+	rs, err := ruleset.FromBundle(b,
+		ruleset.WithValueInference(t.InferValues),
+		ruleset.WithPackageCollapse(t.CollapsePackages),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate RuleSet from given bundle: %w", err)
+	}
+
+	// No error
+	return rs, nil
+}
+
+func (t *RuleSetTask) fromTemplate(ctx context.Context) (*bundlev1.RuleSet, error) {
+	// Create input reader
+	reader, err := t.TemplateReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open input bundle template: %w", err)
+	}
+
+	// Parse the input specification
+	spec, err := template.YAML(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template: %w", err)
+	}
+
+	// Generate ruleset
+	rs, err := ruleset.FromTemplate(spec, t.TemplateContext)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate RuleSet from given bundle template: %w", err)
+	}
+
+	// No error
+	return rs, nil
+}
@@ -37,6 +37,7 @@ type VaultTask struct {
 	AsVaultMetadata bool
 	VaultNamespace  string
 	MaxWorkerCount  int64
+	PolicyGate      *tasks.RuleSetGate
 }
 
 // Run the task.
@@ -69,6 +70,11 @@ func (t *VaultTask) Run(ctx context.Context) error {
 		return fmt.Errorf("unable to load bundle: %w", err)
 	}
 
+	// Enforce rulesets before publication
+	if err := t.PolicyGate.Enforce(ctx, b); err != nil {
+		return fmt.Errorf("unable to enforce rulesets: %w", err)
+	}
+
 	// Process push operation
 	if err := bundlevault.Push(ctx, b, client,
 		bundlevault.WithPrefix(t.BackendPrefix),
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/elastic/harp/pkg/sdk/fsutil"
+	"github.com/elastic/harp/pkg/template/files"
+)
+
+// Libraries loads template libraries from the given directories or archives.
+//
+// Library names are prefixed by the library path to prevent collisions.
+func Libraries(paths []string) (map[string]string, error) {
+	libraries := map[string]string{}
+
+	for _, libPath := range paths {
+		// Prepare library filesystem
+		libFS, err := fsutil.From(libPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open template library '%s': %w", libPath, err)
+		}
+
+		// Crawl and load file content
+		fileList, err := files.LoadDir(libFS, ".")
+		if err != nil {
+			return nil, fmt.Errorf("unable to load template library '%s': %w", libPath, err)
+		}
+
+		for _, f := range fileList {
+			libraries[path.Join(libPath, f.Name)] = string(f.Data)
+		}
+	}
+
+	// No error
+	return libraries, nil
+}
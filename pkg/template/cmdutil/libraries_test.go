@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibraries(t *testing.T) {
+	libDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(libDir, "db"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "_helpers.tpl"), []byte(`{{ define "a" }}a{{ end }}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "db", "_postgres.tpl"), []byte(`{{ define "b" }}b{{ end }}`), 0o600))
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			want: map[string]string{},
+		},
+		{
+			name:  "directory",
+			paths: []string{libDir},
+			want: map[string]string{
+				path.Join(libDir, "_helpers.tpl"):     `{{ define "a" }}a{{ end }}`,
+				path.Join(libDir, "db/_postgres.tpl"): `{{ define "b" }}b{{ end }}`,
+			},
+		},
+		{
+			name:    "not found",
+			paths:   []string{filepath.Join(libDir, "missing")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Libraries(tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Libraries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/template/engine"
+	"github.com/elastic/harp/pkg/vault/kv"
+)
+
+// SecretReaders returns secret readers for the given loaders. A loader is
+// either :
+//
+//   - 'vault' to use the Vault KV backend;
+//   - a 'consul://', 'etcd3://' or 'zk://' URI to use a KV store;
+//   - a 'crate://' URI to use a sealed container pulled from a crate;
+//   - a 'sealed://' URI to use a sealed container unsealed in memory;
+//   - a secret container path ('-' for stdin).
+//
+// Secret readers are queried in the given order, the first one resolving a
+// secret path takes precedence. Backend specific not found errors are reported
+// as engine.ErrSecretNotFound to query the next secret reader. Remote secret
+// readers cache their results.
+func SecretReaders(ctx context.Context, loaders []string) ([]engine.SecretReaderFunc, error) {
+	secretReaders := []engine.SecretReaderFunc{}
+	for _, sr := range loaders {
+		var (
+			secretReader engine.SecretReaderFunc
+			err          error
+		)
+
+		switch {
+		case sr == "vault":
+			secretReader, err = vaultSecretReader()
+		case strings.Contains(sr, "://"):
+			secretReader, err = uriSecretReader(ctx, sr)
+		default:
+			secretReader, err = containerSecretReader(sr)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Append secret loader
+		secretReaders = append(secretReaders, secretReader)
+	}
+
+	// No error
+	return secretReaders, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func uriSecretReader(ctx context.Context, loader string) (engine.SecretReaderFunc, error) {
+	// Parse loader URI
+	u, err := url.Parse(loader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse secret loader URI: %w", err)
+	}
+
+	switch u.Scheme {
+	case "consul":
+		return consulSecretReader(ctx, u)
+	case "etcd3":
+		return etcd3SecretReader(ctx, u)
+	case "zk":
+		return zookeeperSecretReader(ctx, u)
+	case "crate":
+		return crateSecretReader(ctx, u)
+	case "sealed":
+		return sealedSecretReader(ctx, u)
+	default:
+		return nil, fmt.Errorf("unsupported secret loader scheme '%s'", u.Scheme)
+	}
+}
+
+func vaultSecretReader() (engine.SecretReaderFunc, error) {
+	// Initialize Vault connection
+	vaultClient, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize vault secret loader: %w", err)
+	}
+
+	// No error
+	return engine.CachedSecretReader(engine.NotFoundAs(kv.SecretGetter(vaultClient), kv.ErrPathNotFound)), nil
+}
+
+func containerSecretReader(path string) (engine.SecretReaderFunc, error) {
+	// Read container
+	containerReader, err := cmdutil.Reader(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read secret container: %w", err)
+	}
+
+	// Load container
+	b, err := bundle.FromContainerReader(containerReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode secret container: %w", err)
+	}
+
+	// No error
+	return engine.NotFoundAs(bundle.SecretReader(b), bundle.ErrSecretNotFound), nil
+}
@@ -0,0 +1,172 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	zk "github.com/go-zookeeper/zk"
+	consulapi "github.com/hashicorp/consul/api"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/elastic/harp/pkg/kv"
+	"github.com/elastic/harp/pkg/kv/consul"
+	"github.com/elastic/harp/pkg/kv/etcd3"
+	"github.com/elastic/harp/pkg/kv/zookeeper"
+	"github.com/elastic/harp/pkg/sdk/tlsconfig"
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+const defaultKVDialTimeout = 15 * time.Second
+
+// consulSecretReader builds a secret reader from a
+// `consul://[host:port]/prefix?tls=true` URI.
+//
+// Consul client settings are read from the environment (CONSUL_HTTP_ADDR,
+// CONSUL_HTTP_TOKEN, etc.) and overridden by the URI host.
+func consulSecretReader(ctx context.Context, u *url.URL) (engine.SecretReaderFunc, error) {
+	// Create Consul client config from environment.
+	config := consulapi.DefaultConfig()
+	if u.Host != "" {
+		config.Address = u.Host
+	}
+	if u.Query().Get("tls") == "true" {
+		config.Scheme = "https"
+	}
+
+	// Creates a new client
+	client, err := consulapi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize consul secret loader: %w", err)
+	}
+
+	// No error
+	return kvSecretReader(ctx, consul.Store(client.KV()), u.Path), nil
+}
+
+// etcd3SecretReader builds a secret reader from a
+// `etcd3://[user:password@]host:port[,host:port]/prefix` URI.
+//
+// Supported query parameters are `dial-timeout`, `tls`, `ca-file`,
+// `cert-file`, `key-file` and `insecure-skip-verify`.
+func etcd3SecretReader(ctx context.Context, u *url.URL) (engine.SecretReaderFunc, error) {
+	q := u.Query()
+
+	// Parse dial timeout
+	dialTimeout, err := queryDuration(q, "dial-timeout", defaultKVDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create config
+	scheme := "http"
+	if q.Get("tls") == "true" {
+		scheme = "https"
+	}
+	config := clientv3.Config{
+		Context:     ctx,
+		Endpoints:   kv.CreateEndpoints(hosts(u, "localhost:2379"), scheme),
+		DialTimeout: dialTimeout,
+	}
+	if u.User != nil {
+		config.Username = u.User.Username()
+		config.Password, _ = u.User.Password()
+	}
+
+	if q.Get("tls") == "true" {
+		tlsConfig, errTLS := tlsconfig.Client(&tlsconfig.Options{
+			InsecureSkipVerify: q.Get("insecure-skip-verify") == "true",
+			CAFile:             q.Get("ca-file"),
+			CertFile:           q.Get("cert-file"),
+			KeyFile:            q.Get("key-file"),
+		})
+		if errTLS != nil {
+			return nil, fmt.Errorf("unable to initialize etcd3 TLS settings: %w", errTLS)
+		}
+
+		// Assign TLS settings
+		config.TLS = tlsConfig
+	}
+
+	// Creates a new client
+	client, err := clientv3.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize etcd3 secret loader: %w", err)
+	}
+
+	// No error
+	return kvSecretReader(ctx, etcd3.Store(client), u.Path), nil
+}
+
+// zookeeperSecretReader builds a secret reader from a
+// `zk://host:port[,host:port]/prefix?dial-timeout=15s` URI.
+func zookeeperSecretReader(ctx context.Context, u *url.URL) (engine.SecretReaderFunc, error) {
+	// Parse dial timeout
+	dialTimeout, err := queryDuration(u.Query(), "dial-timeout", defaultKVDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creates a new client
+	client, _, err := zk.Connect(hosts(u, "127.0.0.1:2181"), dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize zookeeper secret loader: %w", err)
+	}
+
+	// No error
+	return kvSecretReader(ctx, zookeeper.Store(client), u.Path), nil
+}
+
+// -----------------------------------------------------------------------------
+
+func kvSecretReader(ctx context.Context, store kv.Store, prefix string) engine.SecretReaderFunc {
+	prefix = strings.Trim(prefix, "/")
+	secretReader := engine.NotFoundAs(kv.SecretReader(ctx, store), kv.ErrKeyNotFound)
+
+	return engine.CachedSecretReader(func(secretPath string) (map[string]interface{}, error) {
+		return secretReader(path.Join(prefix, secretPath))
+	})
+}
+
+func hosts(u *url.URL, defaultHost string) []string {
+	if u.Host == "" {
+		return []string{defaultHost}
+	}
+
+	return strings.Split(u.Host, ",")
+}
+
+func queryDuration(q url.Values, name string, defaultValue time.Duration) (time.Duration, error) {
+	raw := q.Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse '%s' parameter: %w", name, err)
+	}
+
+	// No error
+	return d, nil
+}
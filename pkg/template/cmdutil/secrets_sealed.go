@@ -0,0 +1,213 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/awnumar/memguard"
+	"oras.land/oras-go/pkg/content"
+
+	containerv1 "github.com/elastic/harp/api/gen/go/harp/container/v1"
+	"github.com/elastic/harp/pkg/bundle"
+	"github.com/elastic/harp/pkg/container"
+	"github.com/elastic/harp/pkg/container/identity"
+	"github.com/elastic/harp/pkg/crate"
+	"github.com/elastic/harp/pkg/sdk/cmdutil"
+	"github.com/elastic/harp/pkg/sdk/value"
+	"github.com/elastic/harp/pkg/sdk/value/encryption/jwe"
+	"github.com/elastic/harp/pkg/template/engine"
+	"github.com/elastic/harp/pkg/vault"
+)
+
+const (
+	defaultContainerKeyEnv       = "HARP_CONTAINER_KEY"
+	defaultIdentityPassphraseEnv = "HARP_IDENTITY_PASSPHRASE"
+)
+
+// sealedSecretReader builds a secret reader from a `sealed://path` URI.
+//
+// The container is unsealed in memory, see containerKey for supported query
+// parameters.
+func sealedSecretReader(ctx context.Context, u *url.URL) (engine.SecretReaderFunc, error) {
+	// Read container
+	reader, err := cmdutil.Reader(u.Host + u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read sealed container: %w", err)
+	}
+
+	// Load container
+	c, err := container.Load(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode sealed container: %w", err)
+	}
+
+	// No error
+	return unsealedSecretReader(ctx, c, u.Query())
+}
+
+// crateSecretReader builds a secret reader from a
+// `crate://registry/repository:tag#container` URI.
+//
+// The crate is pulled in memory, and the named container (or the only one if
+// not specified) is unsealed, see containerKey for supported query
+// parameters. Registry credentials are read from the Docker configuration.
+func crateSecretReader(ctx context.Context, u *url.URL) (engine.SecretReaderFunc, error) {
+	q := u.Query()
+
+	// Create registry resolver
+	registry, err := content.NewRegistry(content.RegistryOptions{
+		Insecure:  q.Get("insecure") == "true",
+		PlainHTTP: q.Get("plain-http") == "true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize registry resolver: %w", err)
+	}
+
+	// Pull the crate
+	store := crate.NewDescriptorStore()
+	if _, err := crate.Pull(ctx, registry, u.Host+u.Path, store); err != nil {
+		return nil, fmt.Errorf("unable to pull crate '%s': %w", u.Host+u.Path, err)
+	}
+
+	// Extract crate content
+	image, err := crate.ExtractImage(store)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract crate '%s': %w", u.Host+u.Path, err)
+	}
+
+	// Select the container
+	var c *containerv1.Container
+	for _, sc := range image.Containers {
+		if sc.Name == u.Fragment || (u.Fragment == "" && len(image.Containers) == 1) {
+			c = sc.Container
+			break
+		}
+	}
+	if c == nil {
+		return nil, fmt.Errorf("unable to find container '%s' in crate '%s'", u.Fragment, u.Host+u.Path)
+	}
+
+	// No error
+	return unsealedSecretReader(ctx, c, q)
+}
+
+// -----------------------------------------------------------------------------
+
+func unsealedSecretReader(ctx context.Context, c *containerv1.Container, q url.Values) (engine.SecretReaderFunc, error) {
+	// Retrieve the container key
+	containerKey, err := containerKey(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer containerKey.Destroy()
+
+	// Unseal the container
+	out, err := container.Unseal(c, containerKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unseal container: %w", err)
+	}
+
+	// Extract bundle
+	b, err := bundle.FromContainer(out)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode secret container: %w", err)
+	}
+
+	// No error
+	return engine.NotFoundAs(bundle.SecretReader(b), bundle.ErrSecretNotFound), nil
+}
+
+// containerKey resolves the container key from query parameters.
+//
+// With `identity=<path>`, the container key is recovered from the identity
+// file. The identity private key is decrypted with Vault transit when
+// `vault-transit-key` (and optionally `vault-transit-path`) is given, or
+// with the passphrase read from the environment variable named by
+// `passphrase-env` (HARP_IDENTITY_PASSPHRASE by default).
+//
+// Without identity, the container key is read from the environment variable
+// named by `key-env` (HARP_CONTAINER_KEY by default).
+func containerKey(ctx context.Context, q url.Values) (*memguard.LockedBuffer, error) {
+	identityPath := q.Get("identity")
+	if identityPath == "" {
+		keyEnv := queryString(q, "key-env", defaultContainerKeyEnv)
+		key := os.Getenv(keyEnv)
+		if key == "" {
+			return nil, fmt.Errorf("unable to unseal container, container key must be provided with '%s' environment variable or an identity", keyEnv)
+		}
+
+		// No error
+		return memguard.NewBufferFromBytes([]byte(key)), nil
+	}
+
+	// Prepare value transformer
+	var (
+		transformer value.Transformer
+		err         error
+	)
+	if transitKey := q.Get("vault-transit-key"); transitKey != "" {
+		transformer, err = vault.Transformer(queryString(q, "vault-transit-path", "transit"), transitKey, vault.Chacha20Poly1305)
+	} else {
+		passphraseEnv := queryString(q, "passphrase-env", defaultIdentityPassphraseEnv)
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("unable to recover container key, identity passphrase must be provided with '%s' environment variable", passphraseEnv)
+		}
+		transformer, err = jwe.Transformer(jwe.PBES2_HS512_A256KW, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize identity transformer: %w", err)
+	}
+
+	// Read identity
+	reader, err := cmdutil.Reader(identityPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read identity: %w", err)
+	}
+	id, err := identity.FromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode identity: %w", err)
+	}
+
+	// Try to decrypt the private key
+	privateKey, err := id.Decrypt(ctx, transformer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt identity private key: %w", err)
+	}
+
+	// Retrieve recovery key
+	recoveryKey, err := privateKey.RecoveryKey()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve recovery key from identity: %w", err)
+	}
+
+	// No error
+	return memguard.NewBufferFromBytes([]byte(recoveryKey)), nil
+}
+
+func queryString(q url.Values, name, defaultValue string) string {
+	if v := q.Get(name); v != "" {
+		return v
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elastic/harp/pkg/template/engine"
+)
+
+const testSecretPath = "app/production/customer1/ece/v1.0.0/adminconsole/authentication/otp/okta_api_key"
+
+func TestSecretReaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		loaders []string
+		env     map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "unsupported scheme",
+			loaders: []string{"foo://bar"},
+			wantErr: true,
+		},
+		{
+			name:    "container not found",
+			loaders: []string{"non-existent.bundle"},
+			wantErr: true,
+		},
+		{
+			name:    "sealed without container key",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v1.sealed?key-env=HARP_TEST_UNDEFINED_KEY"},
+			wantErr: true,
+		},
+		{
+			name:    "sealed with invalid container key",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v2.sealed"},
+			env: map[string]string{
+				"HARP_CONTAINER_KEY": "v1.ck.MiVGh4KOmdzZbej17BZGChkCPZ9uK9uBWdPNU0GlBNg",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "sealed with identity without passphrase",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v2.sealed?identity=../../../test/fixtures/identity/security.v2.json&passphrase-env=HARP_TEST_UNDEFINED_PASSPHRASE"},
+			wantErr: true,
+		},
+		// ---------------------------------------------------------------------
+		{
+			name:    "container",
+			loaders: []string{"../../../test/fixtures/bundles/complete.bundle"},
+		},
+		{
+			name:    "sealed with container key",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v1.sealed"},
+			env: map[string]string{
+				"HARP_CONTAINER_KEY": "v1.ck.MiVGh4KOmdzZbej17BZGChkCPZ9uK9uBWdPNU0GlBNg",
+			},
+		},
+		{
+			name:    "sealed with container key from custom variable",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v2.sealed?key-env=TEST_CONTAINER_KEY"},
+			env: map[string]string{
+				"TEST_CONTAINER_KEY": "v2.ck.CLMEUoY-EgvMGKCcKeByPdJjQDod6fqTnqvxtD_Z0_SX4PMITu_emttDL91z_61D",
+			},
+		},
+		{
+			name:    "sealed with identity",
+			loaders: []string{"sealed://../../../test/fixtures/bundles/complete.v2.sealed?identity=../../../test/fixtures/identity/security.v2.json"},
+			env: map[string]string{
+				"HARP_IDENTITY_PASSPHRASE": "test",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := SecretReaders(context.Background(), tt.loaders)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretReaders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.loaders) {
+				t.Fatalf("SecretReaders() returned %d readers, expected %d", len(got), len(tt.loaders))
+			}
+
+			// Resolve a secret from the loaded container
+			if _, err := got[0](testSecretPath); err != nil {
+				t.Errorf("unable to read secret: %v", err)
+			}
+
+			// Unknown secrets are reported as not found to query the next loader
+			if _, err := got[0]("app/unknown"); !errors.Is(err, engine.ErrSecretNotFound) {
+				t.Errorf("expected a not found error, got %v", err)
+			}
+		})
+	}
+}
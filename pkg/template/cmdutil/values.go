@@ -30,6 +30,7 @@ import (
 	"github.com/elastic/harp/pkg/sdk/flags/strvals"
 	"github.com/elastic/harp/pkg/sdk/log"
 	"github.com/elastic/harp/pkg/template/values"
+	"github.com/elastic/harp/pkg/template/values/schema"
 )
 
 // Inspired from Helm v3
@@ -41,10 +42,12 @@ type ValueOptions struct {
 	StringValues []string
 	Values       []string
 	FileValues   []string
+	SchemaFile   string
 }
 
 // MergeValues merges values from files specified via -f/--values and directly
-// via --set, --set-string, or --set-file, marshaling them to YAML
+// via --set, --set-string, or --set-file, marshaling them to YAML. Merged values
+// are validated using the JSON schema specified via --values-schema.
 func (opts *ValueOptions) MergeValues() (map[string]interface{}, error) {
 	base := map[string]interface{}{}
 
@@ -92,11 +95,40 @@ func (opts *ValueOptions) MergeValues() (map[string]interface{}, error) {
 		}
 	}
 
+	// User specified a schema via --values-schema
+	if opts.SchemaFile != "" {
+		if err := validateValues(opts.SchemaFile, base); err != nil {
+			return nil, err
+		}
+	}
+
 	return base, nil
 }
 
 // -----------------------------------------------------------------------------
 
+func validateValues(schemaPath string, values map[string]interface{}) error {
+	// Open schema file
+	reader, err := cmdutil.Reader(schemaPath)
+	if err != nil {
+		return fmt.Errorf("unable to open values schema '%s': %w", schemaPath, err)
+	}
+
+	// Read schema definition
+	definition, err := schema.FromReader(reader)
+	if err != nil {
+		return fmt.Errorf("unable to read values schema '%s': %w", schemaPath, err)
+	}
+
+	// Validate values
+	if err := schema.Validate(definition, values); err != nil {
+		return fmt.Errorf("invalid values: %w", err)
+	}
+
+	// No error
+	return nil
+}
+
 func processFilePath(currentDirectory, filePath string, result interface{}) error {
 	defer func() {
 		log.CheckErr("unable to reset to current working directory", os.Chdir(currentDirectory))
@@ -41,6 +41,10 @@ func Render(input string, data interface{}) (content string, err error) {
 	if err != nil {
 		return "", fmt.Errorf("unable to compile attribute template '%s': %w", input, err)
 	}
+	t.Funcs((&templateSet{
+		t:     t,
+		depth: new(int),
+	}).funcs())
 
 	// Fail on missing key
 	t.Option("missingkey=error")
@@ -72,15 +76,46 @@ func RenderContextWithData(templateContext Context, input string, data interface
 	// Retrieve delimiters
 	leftDelim, rightDelim := templateContext.Delims()
 
+	// Prepare functions and files
+	funcs := FuncMap(templateContext.SecretReaders())
+	files := templateContext.Files()
+	var sandbox *sandboxRun
+	if templateContext.Sandbox() != nil {
+		sandbox = templateContext.Sandbox().start()
+		funcs, err = sandbox.funcMap(templateContext.SecretReaders())
+		if err != nil {
+			return "", fmt.Errorf("unable to prepare sandbox: %w", err)
+		}
+		files = Files{}
+	}
+
 	// Prepare the template
-	t, err := template.New(templateContext.Name()).
+	t := template.New(templateContext.Name()).
 		Delims(leftDelim, rightDelim).
-		Funcs(FuncMap(templateContext.SecretReaders())).
-		Parse(input)
-	if err != nil {
+		Funcs(funcs)
+
+	// Load named template libraries
+	if err := parseLibraries(t, templateContext.Libraries()); err != nil {
+		return "", err
+	}
+	if _, err := t.Parse(input); err != nil {
 		return "", fmt.Errorf("unable to compile attribute template '%s': %w", input, err)
 	}
 
+	// Bind template set dependent functions
+	deterministic := templateContext.Deterministic()
+	t.Funcs((&templateSet{
+		t:             t,
+		deterministic: deterministic,
+		sandbox:       sandbox,
+		depth:         new(int),
+	}).funcs())
+
+	// Bind generator functions to the deterministic random source
+	if deterministic != nil {
+		t.Funcs(deterministic.bind(t.Templates()...))
+	}
+
 	// Check strict mode
 	if templateContext.StrictMode() {
 		// Fail on missing key
@@ -93,11 +128,17 @@ func RenderContextWithData(templateContext Context, input string, data interface
 
 	// Merge with values
 	var out bytes.Buffer
-	if err := t.Execute(&out, map[string]interface{}{
+	model := map[string]interface{}{
 		"Data":   data,
 		"Values": templateContext.Values(),
-		"Files":  templateContext.Files(),
-	}); err != nil {
+		"Files":  files,
+	}
+	if sandbox != nil {
+		err = sandbox.execute(t, &out, model)
+	} else {
+		err = t.Execute(&out, model)
+	}
+	if err != nil {
 		return "", fmt.Errorf("unable to merge values with template '%s': %w", input, err)
 	}
 
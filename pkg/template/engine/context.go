@@ -25,6 +25,9 @@ type Context interface {
 	SecretReaders() []SecretReaderFunc
 	Values() Values
 	Files() Files
+	Sandbox() *Sandbox
+	Deterministic() *Deterministic
+	Libraries() map[string]string
 }
 
 // -----------------------------------------------------------------------------
@@ -78,6 +81,29 @@ func WithFiles(files Files) ContextOption {
 	}
 }
 
+// WithSandbox enables sandbox mode with the given restrictions.
+func WithSandbox(sandbox *Sandbox) ContextOption {
+	return func(ctx *context) {
+		ctx.sandbox = sandbox
+	}
+}
+
+// WithDeterministic binds generator functions to a deterministic random
+// source.
+func WithDeterministic(deterministic *Deterministic) ContextOption {
+	return func(ctx *context) {
+		ctx.deterministic = deterministic
+	}
+}
+
+// WithLibraries defines template libraries (name / content) loaded before
+// template compilation to share named template definitions.
+func WithLibraries(libraries map[string]string) ContextOption {
+	return func(ctx *context) {
+		ctx.libraries = libraries
+	}
+}
+
 // NewContext returns a template rendering context.
 func NewContext(opts ...ContextOption) Context {
 	defaultContext := &context{
@@ -97,6 +123,16 @@ func NewContext(opts ...ContextOption) Context {
 	return defaultContext
 }
 
+// NamedContext returns the given context with another template name. The
+// template name scopes deterministic generator call sites, and is used in
+// rendering error messages.
+func NamedContext(templateContext Context, name string) Context {
+	return &namedContext{
+		Context: templateContext,
+		name:    name,
+	}
+}
+
 // -----------------------------------------------------------------------------
 
 // Context describes rendering context.
@@ -108,6 +144,9 @@ type context struct {
 	secretReaders []SecretReaderFunc
 	values        Values
 	files         Files
+	sandbox       *Sandbox
+	deterministic *Deterministic
+	libraries     map[string]string
 }
 
 // Name returns template name
@@ -139,3 +178,31 @@ func (ctx *context) Values() Values {
 func (ctx *context) Files() Files {
 	return ctx.files
 }
+
+// Sandbox returns sandbox restrictions, nil if sandbox mode is disabled.
+func (ctx *context) Sandbox() *Sandbox {
+	return ctx.sandbox
+}
+
+// Deterministic returns deterministic random source settings, nil if
+// deterministic mode is disabled.
+func (ctx *context) Deterministic() *Deterministic {
+	return ctx.deterministic
+}
+
+// Libraries returns template libraries loaded before template compilation.
+func (ctx *context) Libraries() map[string]string {
+	return ctx.libraries
+}
+
+// -----------------------------------------------------------------------------
+
+type namedContext struct {
+	Context
+	name string
+}
+
+// Name returns overridden template name
+func (ctx *namedContext) Name() string {
+	return ctx.name
+}
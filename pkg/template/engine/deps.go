@@ -0,0 +1,352 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Dependency describes a template external reference.
+type Dependency struct {
+	// Name is the secret path, the value key or the file path. It holds the
+	// expression source when the reference can't be statically resolved.
+	Name string `json:"name"`
+	// Unresolved is true when the reference is a dynamic expression.
+	Unresolved bool `json:"unresolved,omitempty"`
+	// Locations lists reference positions as `template:line:col`.
+	Locations []string `json:"locations"`
+}
+
+// Dependencies describes all external references used by templates.
+type Dependencies struct {
+	Secrets []*Dependency `json:"secrets"`
+	Values  []*Dependency `json:"values"`
+	Files   []*Dependency `json:"files"`
+}
+
+// AnalyzeContext parses the given template and extracts `secret` lookups,
+// `.Values` keys and `.Files` accesses.
+func AnalyzeContext(templateContext Context, input string) (*Dependencies, error) {
+	// Retrieve delimiters
+	leftDelim, rightDelim := templateContext.Delims()
+
+	// Parse the template
+	t, err := template.New(templateContext.Name()).
+		Delims(leftDelim, rightDelim).
+		Funcs(FuncMap(nil)).
+		Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template '%s': %w", templateContext.Name(), err)
+	}
+
+	a := &analyzer{
+		secrets: map[string]*Dependency{},
+		values:  map[string]*Dependency{},
+		files:   map[string]*Dependency{},
+	}
+
+	// Visit all defined templates
+	templates := t.Templates()
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+	for _, tmpl := range templates {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+		a.tree = tmpl.Tree
+		a.walk(tmpl.Tree.Root)
+	}
+
+	// No error
+	return a.dependencies(), nil
+}
+
+// Merge appends references from the given dependencies.
+func (d *Dependencies) Merge(other *Dependencies) {
+	if other == nil {
+		return
+	}
+
+	d.Secrets = mergeDependencies(d.Secrets, other.Secrets)
+	d.Values = mergeDependencies(d.Values, other.Values)
+	d.Files = mergeDependencies(d.Files, other.Files)
+}
+
+// -----------------------------------------------------------------------------
+
+type analyzer struct {
+	tree    *parse.Tree
+	secrets map[string]*Dependency
+	values  map[string]*Dependency
+	files   map[string]*Dependency
+}
+
+//nolint:gocyclo // parse tree visitor
+func (a *analyzer) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			a.walk(child)
+		}
+	case *parse.ActionNode:
+		a.walk(n.Pipe)
+	case *parse.IfNode:
+		a.walkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		a.walkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		a.walkBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		a.walk(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i, cmd := range n.Cmds {
+			var previous *parse.CommandNode
+			if i > 0 {
+				previous = n.Cmds[i-1]
+			}
+			a.walkCommand(cmd, previous)
+		}
+	case *parse.ChainNode:
+		a.walk(n.Node)
+	case *parse.FieldNode:
+		a.field(n, n.Ident, nil)
+	case *parse.VariableNode:
+		if len(n.Ident) > 0 && n.Ident[0] == "$" {
+			a.field(n, n.Ident[1:], nil)
+		}
+	}
+}
+
+func (a *analyzer) walkBranch(n *parse.BranchNode) {
+	a.walk(n.Pipe)
+	a.walk(n.List)
+	if n.ElseList != nil {
+		a.walk(n.ElseList)
+	}
+}
+
+func (a *analyzer) walkCommand(cmd, previous *parse.CommandNode) {
+	if len(cmd.Args) == 0 {
+		return
+	}
+
+	switch first := cmd.Args[0].(type) {
+	case *parse.IdentifierNode:
+		switch first.Ident {
+		case "secret":
+			a.secret(cmd, previous)
+		case "index":
+			if a.index(cmd) {
+				return
+			}
+		}
+	case *parse.FieldNode:
+		// Method call with arguments (.Files.Get "path")
+		a.field(first, first.Ident, cmd.Args[1:])
+		for _, arg := range cmd.Args[1:] {
+			a.walk(arg)
+		}
+		return
+	case *parse.VariableNode:
+		if len(first.Ident) > 0 && first.Ident[0] == "$" {
+			a.field(first, first.Ident[1:], cmd.Args[1:])
+			for _, arg := range cmd.Args[1:] {
+				a.walk(arg)
+			}
+			return
+		}
+	}
+
+	for _, arg := range cmd.Args {
+		a.walk(arg)
+	}
+}
+
+func (a *analyzer) secret(cmd, previous *parse.CommandNode) {
+	switch {
+	case len(cmd.Args) == 2:
+		// secret "path"
+		if s, ok := cmd.Args[1].(*parse.StringNode); ok {
+			a.add(a.secrets, s.Text, false, cmd)
+			return
+		}
+		a.add(a.secrets, cmd.Args[1].String(), true, cmd)
+	case len(cmd.Args) == 1 && previous != nil:
+		// "path" | secret
+		if len(previous.Args) == 1 {
+			if s, ok := previous.Args[0].(*parse.StringNode); ok {
+				a.add(a.secrets, s.Text, false, cmd)
+				return
+			}
+		}
+		a.add(a.secrets, previous.String(), true, cmd)
+	default:
+		a.add(a.secrets, cmd.String(), true, cmd)
+	}
+}
+
+// index handles `index .Values "key" ...` lookups.
+func (a *analyzer) index(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) < 3 {
+		return false
+	}
+
+	var ident []string
+	switch n := cmd.Args[1].(type) {
+	case *parse.FieldNode:
+		ident = n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) == 0 || n.Ident[0] != "$" {
+			return false
+		}
+		ident = n.Ident[1:]
+	default:
+		return false
+	}
+	if len(ident) == 0 || ident[0] != "Values" {
+		return false
+	}
+
+	keys := append([]string{}, ident[1:]...)
+	for _, arg := range cmd.Args[2:] {
+		s, ok := arg.(*parse.StringNode)
+		if !ok {
+			a.add(a.values, cmd.String(), true, cmd)
+			for _, arg := range cmd.Args[2:] {
+				a.walk(arg)
+			}
+			return true
+		}
+		keys = append(keys, s.Text)
+	}
+	a.add(a.values, strings.Join(keys, "."), false, cmd)
+
+	return true
+}
+
+func (a *analyzer) field(node parse.Node, ident []string, args []parse.Node) {
+	if len(ident) == 0 {
+		return
+	}
+
+	switch ident[0] {
+	case "Values":
+		if len(ident) == 1 {
+			// Whole values object
+			a.add(a.values, node.String(), true, node)
+			return
+		}
+		a.add(a.values, strings.Join(ident[1:], "."), false, node)
+	case "Files":
+		if len(ident) == 1 {
+			// Whole files object
+			a.add(a.files, node.String(), true, node)
+			return
+		}
+		if len(args) > 0 {
+			if s, ok := args[0].(*parse.StringNode); ok {
+				name := s.Text
+				if ident[1] == "Glob" {
+					name = fmt.Sprintf("glob:%s", s.Text)
+				}
+				a.add(a.files, name, false, node)
+				return
+			}
+			a.add(a.files, fmt.Sprintf("%s %s", node, args[0]), true, node)
+			return
+		}
+		a.add(a.files, node.String(), true, node)
+	}
+}
+
+func (a *analyzer) add(deps map[string]*Dependency, name string, unresolved bool, node parse.Node) {
+	key := fmt.Sprintf("%t/%s", unresolved, name)
+	d, ok := deps[key]
+	if !ok {
+		d = &Dependency{
+			Name:       name,
+			Unresolved: unresolved,
+			Locations:  []string{},
+		}
+		deps[key] = d
+	}
+
+	location, _ := a.tree.ErrorContext(node)
+	d.Locations = append(d.Locations, location)
+}
+
+func (a *analyzer) dependencies() *Dependencies {
+	return &Dependencies{
+		Secrets: sortedDependencies(a.secrets),
+		Values:  sortedDependencies(a.values),
+		Files:   sortedDependencies(a.files),
+	}
+}
+
+func sortedDependencies(deps map[string]*Dependency) []*Dependency {
+	res := make([]*Dependency, 0, len(deps))
+	for _, d := range deps {
+		res = append(res, d)
+	}
+	sortDependencies(res)
+
+	return res
+}
+
+func mergeDependencies(a, b []*Dependency) []*Dependency {
+	if a == nil {
+		a = []*Dependency{}
+	}
+
+	index := map[string]*Dependency{}
+	for _, d := range a {
+		index[fmt.Sprintf("%t/%s", d.Unresolved, d.Name)] = d
+	}
+	for _, d := range b {
+		key := fmt.Sprintf("%t/%s", d.Unresolved, d.Name)
+		if existing, ok := index[key]; ok {
+			existing.Locations = append(existing.Locations, d.Locations...)
+			continue
+		}
+		index[key] = d
+		a = append(a, d)
+	}
+	sortDependencies(a)
+
+	return a
+}
+
+func sortDependencies(deps []*Dependency) {
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Unresolved != deps[j].Unresolved {
+			return !deps[i].Unresolved
+		}
+		return deps[i].Name < deps[j].Name
+	})
+}
@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *Dependencies
+		wantErr bool
+	}{
+		{
+			name:    "invalid template",
+			input:   `{{ secret "foo" }`,
+			wantErr: true,
+		},
+		{
+			name:  "secrets",
+			input: "{{ (secret \"app/production/db\").user }}\n{{ with \"app/production/cache\" | secret }}{{ .password }}{{ end }}\n{{ secret (printf \"app/%s/db\" .Values.env) }}",
+			want: &Dependencies{
+				Secrets: []*Dependency{
+					{Name: "app/production/cache", Locations: []string{"test:2:33"}},
+					{Name: "app/production/db", Locations: []string{"test:1:4"}},
+					{Name: `printf "app/%s/db" .Values.env`, Unresolved: true, Locations: []string{"test:3:3"}},
+				},
+				Values: []*Dependency{
+					{Name: "env", Locations: []string{"test:3:37"}},
+				},
+				Files: []*Dependency{},
+			},
+		},
+		{
+			name:  "values",
+			input: `{{ .Values.db.host }}:{{ $.Values.db.port }}{{ index .Values "db" "user" }}{{ index .Values .Values.key }}{{ toYaml .Values }}{{ define "sub" }}{{ .Values.db.host }}{{ end }}`,
+			want: &Dependencies{
+				Secrets: []*Dependency{},
+				Values: []*Dependency{
+					{Name: "db.host", Locations: []string{"test:1:154", "test:1:10"}},
+					{Name: "db.port", Locations: []string{"test:1:26"}},
+					{Name: "db.user", Locations: []string{"test:1:47"}},
+					{Name: "key", Locations: []string{"test:1:99"}},
+					{Name: ".Values", Unresolved: true, Locations: []string{"test:1:116"}},
+					{Name: "index .Values .Values.key", Unresolved: true, Locations: []string{"test:1:78"}},
+				},
+				Files: []*Dependency{},
+			},
+		},
+		{
+			name:  "files",
+			input: `{{ .Files.Get "config/app.yaml" }}{{ range $path, $_ := .Files.Glob "certs/*.pem" }}{{ $.Files.GetBytes $path }}{{ end }}`,
+			want: &Dependencies{
+				Secrets: []*Dependency{},
+				Values:  []*Dependency{},
+				Files: []*Dependency{
+					{Name: "config/app.yaml", Locations: []string{"test:1:9"}},
+					{Name: "glob:certs/*.pem", Locations: []string{"test:1:62"}},
+					{Name: "$.Files.GetBytes $path", Unresolved: true, Locations: []string{"test:1:88"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AnalyzeContext(NewContext(WithName("test")), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AnalyzeContext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,354 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/google/uuid"
+
+	"github.com/elastic/harp/build/testbuild"
+	"github.com/elastic/harp/pkg/sdk/security/crypto"
+	"github.com/elastic/harp/pkg/sdk/security/diceware"
+	"github.com/elastic/harp/pkg/sdk/security/otp"
+	"github.com/elastic/harp/pkg/sdk/security/password"
+	"github.com/elastic/harp/pkg/template/engine/internal/drbg"
+)
+
+// ErrDeterministicDisabled is raised when deterministic mode is requested
+// from a binary not built with the `testbuild` tag.
+var ErrDeterministicDisabled = errors.New("deterministic mode is only available in test builds (-tags testbuild)")
+
+// ErrNonDeterministic is raised when a function whose output can't be
+// reproduced is called in deterministic mode.
+var ErrNonDeterministic = errors.New("function output can't be reproduced in deterministic mode")
+
+const (
+	randAlphaChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	randNumericChars = "0123456789"
+	randASCIIChars   = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+)
+
+// deterministicFuncs lists generator functions bound to the deterministic
+// random source. Each factory receives a function returning a fresh random
+// source per invocation.
+var deterministicFuncs = map[string]func(random func() io.Reader) interface{}{
+	// Password
+	"customPassword": func(random func() io.Reader) interface{} {
+		return func(length, numDigits, numSymbol int, noUpper, allowRepeat bool) (string, error) {
+			return password.GenerateFrom(random(), length, numDigits, numSymbol, noUpper, allowRepeat)
+		}
+	},
+	"paranoidPassword": passwordProfile(password.ProfileParanoid),
+	"noSymbolPassword": passwordProfile(password.ProfileNoSymbol),
+	"strongPassword":   passwordProfile(password.ProfileStrong),
+	// Diceware
+	"customDiceware": func(random func() io.Reader) interface{} {
+		return func(count int) (string, error) {
+			return diceware.DicewareFrom(random(), count)
+		}
+	},
+	"basicDiceware":    dicewareCount(diceware.BasicWordCount),
+	"strongDiceware":   dicewareCount(diceware.StrongWordCount),
+	"paranoidDiceware": dicewareCount(diceware.ParanoidWordCount),
+	// One-time password
+	"otpSeed": func(random func() io.Reader) interface{} {
+		return func(algorithm string) (string, error) {
+			return otp.SeedFrom(random(), algorithm)
+		}
+	},
+	// Crypto
+	"cryptoKey": func(random func() io.Reader) interface{} {
+		return func(keyType string) (string, error) {
+			return crypto.GenerateKey(random(), keyType)
+		}
+	},
+	"cryptoPair": func(random func() io.Reader) interface{} {
+		return func(keyType string) (interface{}, error) {
+			return crypto.GenerateKeypair(random(), keyType)
+		}
+	},
+	// UUID
+	"uuidv4": func(random func() io.Reader) interface{} {
+		return func() (string, error) {
+			id, err := uuid.NewRandomFromReader(random())
+			if err != nil {
+				return "", fmt.Errorf("unable to generate UUID: %w", err)
+			}
+			return id.String(), nil
+		}
+	},
+	// Random strings
+	"randAlphaNum": randString(randAlphaChars + randNumericChars),
+	"randAlpha":    randString(randAlphaChars),
+	"randNumeric":  randString(randNumericChars),
+	"randAscii":    randString(randASCIIChars),
+	"randBytes": func(random func() io.Reader) interface{} {
+		return func(count int) (string, error) {
+			buf := make([]byte, count)
+			if _, err := io.ReadFull(random(), buf); err != nil {
+				return "", fmt.Errorf("unable to generate random bytes: %w", err)
+			}
+			return base64.StdEncoding.EncodeToString(buf), nil
+		}
+	},
+	"randInt": func(random func() io.Reader) interface{} {
+		return func(minValue, maxValue int) (int, error) {
+			if maxValue <= minValue {
+				return 0, fmt.Errorf("invalid random integer range [%d, %d)", minValue, maxValue)
+			}
+			n, err := randIntn(random(), maxValue-minValue)
+			if err != nil {
+				return 0, err
+			}
+			return minValue + n, nil
+		}
+	},
+	"shuffle": func(random func() io.Reader) interface{} {
+		return func(in string) (string, error) {
+			r := random()
+			runes := []rune(in)
+			for i := len(runes) - 1; i > 0; i-- {
+				j, err := randIntn(r, i+1)
+				if err != nil {
+					return "", err
+				}
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return string(runes), nil
+		}
+	},
+}
+
+// nonDeterministicFuncs lists functions relying on the system random source
+// or the wall clock, which are disabled in deterministic mode.
+var nonDeterministicFuncs = []string{
+	// X.509 and OpenSSH certificates (serial, signature, validity)
+	"x509SelfSignedCA", "x509IssueCertificate", "x509SignCSR",
+	"sshUserCertificate", "sshHostCertificate",
+	// Encryption
+	"encryptJwe", "encryptPem", "encryptAES",
+	// Sprig crypto
+	"genPrivateKey", "genCA", "genCAWithKey", "genSelfSignedCert",
+	"genSelfSignedCertWithKey", "genSignedCert", "genSignedCertWithKey",
+	"bcrypt", "htpasswd",
+}
+
+// Deterministic binds all generator template functions to a deterministic
+// random source derived from a seed and the function call site.
+//
+// Rendering the same templates in the same order with the same seed produces
+// the same output. It must only be used for testing purpose.
+type Deterministic struct {
+	seed     []byte
+	mu       sync.Mutex
+	counters map[string]uint64
+}
+
+// NewDeterministic returns a deterministic rendering configuration for the
+// given seed. It is refused if the binary is not built with the `testbuild`
+// tag.
+func NewDeterministic(seed string) (*Deterministic, error) {
+	// Check arguments
+	if !testbuild.Enabled() {
+		return nil, ErrDeterministicDisabled
+	}
+	if seed == "" {
+		return nil, errors.New("deterministic seed must not be blank")
+	}
+
+	// No error
+	return newDeterministic(seed), nil
+}
+
+func newDeterministic(seed string) *Deterministic {
+	return &Deterministic{
+		seed:     []byte(seed),
+		counters: map[string]uint64{},
+	}
+}
+
+// bind replaces generator function calls of the given templates by call site
+// specific aliases and returns the alias function map.
+func (d *Deterministic) bind(templates ...*template.Template) template.FuncMap {
+	funcs := template.FuncMap{}
+	for _, tmpl := range templates {
+		if tmpl.Tree == nil {
+			continue
+		}
+		b := &binder{
+			d:           d,
+			name:        tmpl.Name(),
+			funcs:       funcs,
+			occurrences: map[string]int{},
+		}
+		b.walk(tmpl.Tree.Root)
+	}
+
+	// Disable functions that can't be reproduced
+	for _, name := range nonDeterministicFuncs {
+		funcName := name
+		funcs[name] = func(...interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("unable to call '%s': %w", funcName, ErrNonDeterministic)
+		}
+	}
+
+	return funcs
+}
+
+// reader returns a random source for the next invocation of the given call
+// site.
+func (d *Deterministic) reader(site string) io.Reader {
+	d.mu.Lock()
+	counter := d.counters[site]
+	d.counters[site]++
+	d.mu.Unlock()
+
+	return drbg.New(d.seed, site+"#"+strconv.FormatUint(counter, 10))
+}
+
+// -----------------------------------------------------------------------------
+
+// binder rewrites generator function calls of a template tree.
+type binder struct {
+	d     *Deterministic
+	name  string
+	funcs template.FuncMap
+	// Identical commands are distinguished by their occurrence index.
+	occurrences map[string]int
+}
+
+func (b *binder) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			b.walk(child)
+		}
+	case *parse.ActionNode:
+		b.walk(n.Pipe)
+	case *parse.IfNode:
+		b.walkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		b.walkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		b.walkBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		b.walk(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			b.walk(cmd)
+		}
+	case *parse.ChainNode:
+		b.walk(n.Node)
+	case *parse.CommandNode:
+		// Compute call site before rewriting nested commands
+		command := n.String()
+		site := b.name + "|" + command + "|" + strconv.Itoa(b.occurrences[command])
+		b.occurrences[command]++
+		for _, arg := range n.Args {
+			b.walk(arg)
+		}
+
+		// Only the command operator is a function call
+		if len(n.Args) == 0 {
+			return
+		}
+		ident, ok := n.Args[0].(*parse.IdentifierNode)
+		if !ok {
+			return
+		}
+		factory, ok := deterministicFuncs[ident.Ident]
+		if !ok {
+			return
+		}
+
+		// Bind the call site to the alias
+		h := sha256.Sum256([]byte(site))
+		alias := "deterministic_" + hex.EncodeToString(h[:8])
+		ident.Ident = alias
+		b.funcs[alias] = factory(func() io.Reader {
+			return b.d.reader(site)
+		})
+	}
+}
+
+func (b *binder) walkBranch(n *parse.BranchNode) {
+	b.walk(n.Pipe)
+	b.walk(n.List)
+	b.walk(n.ElseList)
+}
+
+// -----------------------------------------------------------------------------
+
+func passwordProfile(p *password.Profile) func(random func() io.Reader) interface{} {
+	return func(random func() io.Reader) interface{} {
+		return func() (string, error) {
+			return password.GenerateFrom(random(), p.Length, p.NumDigits, p.NumSymbol, p.NoUpper, p.AllowRepeat)
+		}
+	}
+}
+
+func dicewareCount(count int) func(random func() io.Reader) interface{} {
+	return func(random func() io.Reader) interface{} {
+		return func() (string, error) {
+			return diceware.DicewareFrom(random(), count)
+		}
+	}
+}
+
+func randString(chars string) func(random func() io.Reader) interface{} {
+	return func(random func() io.Reader) interface{} {
+		return func(count int) (string, error) {
+			r := random()
+			out := make([]byte, count)
+			for i := range out {
+				idx, err := randIntn(r, len(chars))
+				if err != nil {
+					return "", err
+				}
+				out[i] = chars[idx]
+			}
+			return string(out), nil
+		}
+	}
+}
+
+func randIntn(random io.Reader, n int) (int, error) {
+	v, err := rand.Int(random, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to generate random integer: %w", err)
+	}
+	return int(v.Int64()), nil
+}
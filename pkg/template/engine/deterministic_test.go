@@ -0,0 +1,138 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/harp/build/testbuild"
+)
+
+func TestNewDeterministic(t *testing.T) {
+	d, err := NewDeterministic("seed")
+	if !testbuild.Enabled() {
+		assert.True(t, errors.Is(err, ErrDeterministicDisabled))
+		assert.Nil(t, d)
+		return
+	}
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+
+	_, err = NewDeterministic("")
+	assert.Error(t, err)
+}
+
+func TestDeterministic(t *testing.T) {
+	render := func(t *testing.T, seed, input string) string {
+		t.Helper()
+		out, err := RenderContext(NewContext(WithDeterministic(newDeterministic(seed))), input)
+		require.NoError(t, err)
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		// Keys are derived from the random source in test builds only
+		testBuild bool
+	}{
+		{name: "password", input: `{{ paranoidPassword }}|{{ strongPassword }}|{{ noSymbolPassword }}|{{ customPassword 16 4 4 false true }}`},
+		{name: "diceware", input: `{{ basicDiceware }}|{{ strongDiceware }}|{{ paranoidDiceware }}|{{ customDiceware 6 }}`},
+		{name: "otp", input: `{{ otpSeed "SHA1" }}`},
+		{name: "uuid", input: `{{ uuidv4 }}`},
+		{name: "sprig", input: `{{ randAlphaNum 16 }}|{{ randAlpha 16 }}|{{ randNumeric 16 }}|{{ randAscii 16 }}|{{ randBytes 16 }}|{{ randInt 0 1000000 }}|{{ shuffle "abcdefghijklmnop" }}`},
+		{name: "symmetric key", input: `{{ cryptoKey "aes:256" }}`},
+		{name: "keypair", input: `{{ $kp := cryptoPair "ed25519" }}{{ $kp.Private | toJwk }}`, testBuild: true},
+		{name: "nested", input: `{{ if true }}{{ range $i := until 3 }}{{ randAlphaNum 8 }}{{ end }}{{ end }}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.testBuild && !testbuild.Enabled() {
+				t.Skip("requires a test build")
+			}
+			first := render(t, "seed", tt.input)
+			assert.Equal(t, first, render(t, "seed", tt.input))
+			assert.NotEqual(t, first, render(t, "other", tt.input))
+		})
+	}
+}
+
+func TestDeterministic_NonDeterministic(t *testing.T) {
+	for _, input := range []string{
+		`{{ genPrivateKey "ecdsa" }}`,
+		`{{ bcrypt "password" }}`,
+		`{{ $ca := x509SelfSignedCA "Root CA" }}{{ $ca.Certificate }}`,
+		`{{ $ca := cryptoPair "ed25519" }}{{ $user := cryptoPair "ed25519" }}{{ sshUserCertificate $ca.Private $user.Public "alice" (dict "principals" "alice") }}`,
+	} {
+		_, err := RenderContext(NewContext(WithDeterministic(newDeterministic("seed"))), input)
+		assert.True(t, errors.Is(err, ErrNonDeterministic), input)
+	}
+}
+
+func TestDeterministic_CallSite(t *testing.T) {
+	ctx := NewContext(WithDeterministic(newDeterministic("seed")))
+
+	// Distinct call sites and invocations produce distinct values
+	out, err := RenderContext(ctx, `{{ randAlphaNum 16 }}|{{ randAlphaNum 16 }}|{{ range until 2 }}{{ uuidv4 }}|{{ end }}`)
+	require.NoError(t, err)
+	parts := strings.Split(out, "|")
+	require.Len(t, parts, 5)
+	assert.NotEqual(t, parts[0], parts[1])
+	assert.NotEqual(t, parts[2], parts[3])
+
+	// Adding a call site does not change other call site values
+	ctx = NewContext(WithDeterministic(newDeterministic("seed")))
+	prefixed, err := RenderContext(ctx, `{{ randNumeric 4 }}|{{ randAlphaNum 16 }}|{{ randAlphaNum 16 }}|{{ range until 2 }}{{ uuidv4 }}|{{ end }}`)
+	require.NoError(t, err)
+	assert.Equal(t, out, strings.SplitN(prefixed, "|", 2)[1])
+}
+
+func TestDeterministic_NamedContext(t *testing.T) {
+	render := func(t *testing.T, d *Deterministic, names ...string) []string {
+		t.Helper()
+		ctx := NewContext(WithDeterministic(d))
+		res := []string{}
+		for _, name := range names {
+			out, err := RenderContext(NamedContext(ctx, name), `{{ randAlphaNum 16 }}`)
+			require.NoError(t, err)
+			res = append(res, out)
+		}
+		return res
+	}
+
+	// Call sites are scoped by the template name
+	out := render(t, newDeterministic("seed"), "app/database", "app/server")
+	assert.NotEqual(t, out[0], out[1])
+
+	// Rendering another template before doesn't change the values
+	prefixed := render(t, newDeterministic("seed"), "app/cache", "app/database", "app/server")
+	assert.Equal(t, out, prefixed[1:])
+}
+
+func TestDeterministic_Disabled(t *testing.T) {
+	first, err := RenderContext(NewContext(), `{{ randAlphaNum 16 }}`)
+	require.NoError(t, err)
+	second, err := RenderContext(NewContext(), `{{ randAlphaNum 16 }}`)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
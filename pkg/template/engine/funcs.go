@@ -35,6 +35,7 @@ import (
 	"github.com/elastic/harp/pkg/sdk/security/crypto"
 	"github.com/elastic/harp/pkg/sdk/security/crypto/bech32"
 	"github.com/elastic/harp/pkg/sdk/security/diceware"
+	"github.com/elastic/harp/pkg/sdk/security/otp"
 	"github.com/elastic/harp/pkg/sdk/security/password"
 	"github.com/elastic/harp/pkg/template/engine/internal/codec"
 )
@@ -55,6 +56,12 @@ func FuncMap(secretReaders []SecretReaderFunc) template.FuncMap {
 		"basicDiceware":    diceware.Basic,
 		"strongDiceware":   diceware.Strong,
 		"paranoidDiceware": diceware.Paranoid,
+		// One-time password
+		"otpSeed":  otp.Seed,
+		"totpCode": otp.TOTP,
+		"hotpCode": otp.HOTP,
+		"totpURI":  otp.TOTPURI,
+		"hotpURI":  otp.HOTPURI,
 		// Encoder
 		"toToml":        codec.ToTOML,
 		"toYaml":        codec.ToYAML,
@@ -72,6 +79,10 @@ func FuncMap(secretReaders []SecretReaderFunc) template.FuncMap {
 		"keyToBytes": crypto.KeyToBytes,
 		// Secret
 		"secret": SecretReaders(secretReaders),
+		// Template
+		"include":  includeUnavailable,
+		"tpl":      includeUnavailable,
+		"required": required,
 		// JWT/JWE
 		"encryptJwe": crypto.EncryptJWE,
 		"decryptJwe": crypto.DecryptJWE,
@@ -124,6 +135,17 @@ func FuncMap(secretReaders []SecretReaderFunc) template.FuncMap {
 			return pemutil.ParseCertificateRequest([]byte(pemData))
 		},
 		"toTLSA": crypto.ToTLSA,
+		// X.509
+		"x509SelfSignedCA":     crypto.X509SelfSignedCA,
+		"x509LoadCA":           crypto.X509LoadCA,
+		"x509IssueCertificate": crypto.X509IssueCertificate,
+		"x509SignCSR":          crypto.X509SignCSR,
+		"x509FullChain":        crypto.X509FullChain,
+		// OpenSSH certificates
+		"sshUserCertificate":  crypto.SSHUserCertificate,
+		"sshHostCertificate":  crypto.SSHHostCertificate,
+		"sshKnownHostsCA":     crypto.SSHKnownHostsCA,
+		"sshTrustedUserCAKey": crypto.SSHTrustedUserCAKey,
 		"isodate": func(date interface{}) string {
 			var t time.Time
 			switch date := date.(type) {
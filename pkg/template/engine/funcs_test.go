@@ -23,6 +23,7 @@ import (
 	"text/template"
 
 	"github.com/stretchr/testify/assert"
+	"go.step.sm/crypto/pemutil"
 )
 
 func TestFuncs(t *testing.T) {
@@ -122,3 +123,64 @@ func TestFuncs(t *testing.T) {
 		assert.Equal(t, tt.expect, b.String(), tt.tpl)
 	}
 }
+
+func TestFuncs_X509(t *testing.T) {
+	tpl := `{{- $ca := x509SelfSignedCA "Harp Root CA" (dict "pathLen" 1) -}}
+{{- $int := x509IssueCertificate $ca "Harp Intermediate CA" (dict "isCA" true) -}}
+{{- $loaded := x509LoadCA (x509FullChain $int) ($int.Private | toPem) -}}
+{{- $leaf := x509IssueCertificate $loaded "server.example.com" (dict "sans" (list "server.example.com" "10.0.0.1")) -}}
+{{- x509FullChain $leaf -}}`
+
+	var b strings.Builder
+	err := template.Must(template.New("test").Funcs(FuncMap(nil)).Parse(tpl)).Execute(&b, nil)
+	assert.NoError(t, err)
+
+	// Decode the full chain
+	certs, err := pemutil.ParseCertificateBundle([]byte(b.String()))
+	assert.NoError(t, err)
+	if assert.Len(t, certs, 3) {
+		assert.Equal(t, "server.example.com", certs[0].Subject.CommonName)
+		assert.Equal(t, "Harp Intermediate CA", certs[1].Subject.CommonName)
+		assert.Equal(t, "Harp Root CA", certs[2].Subject.CommonName)
+		assert.NoError(t, certs[0].CheckSignatureFrom(certs[1]))
+		assert.NoError(t, certs[1].CheckSignatureFrom(certs[2]))
+	}
+}
+
+func TestFuncs_SSHCertificate(t *testing.T) {
+	tpl := `{{- $ca := cryptoPair "ed25519" -}}
+{{- $caKey := toSSH $ca.Private -}}
+{{- $user := cryptoPair "ec" -}}
+{{- sshUserCertificate $caKey (toSSH $user.Public) "alice" (dict "principals" (list "alice")) -}}
+{{- sshTrustedUserCAKey $caKey -}}
+{{- sshKnownHostsCA $caKey "*.example.com" -}}`
+
+	var b strings.Builder
+	err := template.Must(template.New("test").Funcs(FuncMap(nil)).Parse(tpl)).Execute(&b, nil)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "ecdsa-sha2-nistp256-cert-v01@openssh.com "))
+		assert.True(t, strings.HasPrefix(lines[1], "ssh-ed25519 "))
+		assert.Equal(t, "@cert-authority *.example.com "+lines[1], lines[2])
+	}
+}
+
+func TestFuncs_OTP(t *testing.T) {
+	tpl := `{{- $seed := otpSeed "sha256" -}}
+{{- totpURI "Elastic" "break-glass" $seed (dict "algorithm" "sha256") }}
+{{ totpCode $seed (dict "algorithm" "sha256" "digits" 8) }}
+{{ hotpCode $seed 0 }}`
+
+	var b strings.Builder
+	err := template.Must(template.New("test").Funcs(FuncMap(nil)).Parse(tpl)).Execute(&b, nil)
+	assert.NoError(t, err)
+
+	lines := strings.Split(b.String(), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "otpauth://totp/Elastic:break-glass?algorithm=SHA256&digits=6&issuer=Elastic&period=30&secret="))
+		assert.Regexp(t, `^\d{8}$`, lines[1])
+		assert.Regexp(t, `^\d{6}$`, lines[2])
+	}
+}
@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/template"
+)
+
+// MaxIncludeDepth defines the maximum nesting level of `include` and `tpl`
+// calls.
+const MaxIncludeDepth = 1000
+
+// templateSet binds `include` and `tpl` functions to a template and its
+// associated named templates.
+type templateSet struct {
+	t             *template.Template
+	deterministic *Deterministic
+	sandbox       *sandboxRun
+	depth         *int
+}
+
+// funcs returns template set dependent functions.
+func (s *templateSet) funcs() template.FuncMap {
+	return template.FuncMap{
+		"include": s.include,
+		"tpl":     s.tpl,
+	}
+}
+
+// include executes the named template and returns the result as a string.
+func (s *templateSet) include(name string, data interface{}) (string, error) {
+	out, err := s.execute(s.t, name, data)
+	if err != nil {
+		return "", fmt.Errorf("unable to include template '%s': %w", name, err)
+	}
+
+	// No error
+	return out, nil
+}
+
+// tpl renders the given string as a template, with access to the named
+// templates of the current set.
+func (s *templateSet) tpl(text string, data interface{}) (string, error) {
+	// Clone the current set to isolate template definitions
+	c, err := s.t.Clone()
+	if err != nil {
+		return "", fmt.Errorf("unable to prepare tpl rendering: %w", err)
+	}
+
+	// Parse the given string
+	name := s.t.Name() + ":tpl"
+	nt, err := c.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("unable to compile tpl template: %w", err)
+	}
+
+	// Bind functions to the cloned set
+	child := &templateSet{
+		t:             c,
+		deterministic: s.deterministic,
+		sandbox:       s.sandbox,
+		depth:         s.depth,
+	}
+	c.Funcs(child.funcs())
+	if s.deterministic != nil {
+		c.Funcs(s.deterministic.bind(nt))
+	}
+
+	out, err := child.execute(c, name, data)
+	if err != nil {
+		return "", fmt.Errorf("unable to render tpl template: %w", err)
+	}
+
+	// No error
+	return out, nil
+}
+
+func (s *templateSet) execute(t *template.Template, name string, data interface{}) (string, error) {
+	// Check nesting level
+	if *s.depth >= MaxIncludeDepth {
+		return "", fmt.Errorf("maximum template nesting depth (%d) exceeded", MaxIncludeDepth)
+	}
+	*s.depth++
+	defer func() {
+		*s.depth--
+	}()
+
+	var (
+		out bytes.Buffer
+		w   io.Writer = &out
+	)
+	if s.sandbox != nil {
+		// Nested outputs are subject to the same limits
+		w = s.sandbox.writer(&out)
+	}
+	if err := t.ExecuteTemplate(w, name, data); err != nil {
+		return "", err
+	}
+
+	// No error
+	return out.String(), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// parseLibraries loads named template definitions from libraries in a stable
+// order.
+func parseLibraries(t *template.Template, libraries map[string]string) error {
+	names := make([]string, 0, len(libraries))
+	for name := range libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := t.New(name).Parse(libraries[name]); err != nil {
+			return fmt.Errorf("unable to compile template library '%s': %w", name, err)
+		}
+	}
+
+	// No error
+	return nil
+}
+
+// required returns the given value or raises an error with the given message
+// if the value is nil or an empty string.
+func required(msg string, val interface{}) (interface{}, error) {
+	if val == nil {
+		return nil, errors.New(msg)
+	}
+	if s, ok := val.(string); ok && s == "" {
+		return nil, errors.New(msg)
+	}
+
+	// No error
+	return val, nil
+}
+
+// includeUnavailable is the placeholder of template set dependent functions
+// used for template parsing.
+func includeUnavailable(string, interface{}) (string, error) {
+	return "", errors.New("function is not available outside template rendering")
+}
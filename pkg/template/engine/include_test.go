@@ -0,0 +1,201 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclude(t *testing.T) {
+	libraries := map[string]string{
+		"_helpers.tpl": `{{- define "postgres.dsn" -}}
+postgres://{{ .user }}@{{ .host }}:{{ get . "port" | default 5432 }}/{{ .database }}
+{{- end -}}
+{{- define "recursive" }}{{ include "recursive" . }}{{ end -}}`,
+	}
+
+	type args struct {
+		input     string
+		libraries map[string]string
+		values    Values
+		sandbox   *Sandbox
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "include library template",
+			args: args{
+				input:     `{{ include "postgres.dsn" .Values.db | quote }}`,
+				libraries: libraries,
+				values:    Values{"db": map[string]interface{}{"user": "app", "host": "db.local", "database": "billing"}},
+			},
+			want: `"postgres://app@db.local:5432/billing"`,
+		},
+		{
+			name: "include local definition",
+			args: args{
+				input: `{{ define "greeting" }}Hello {{ . }}{{ end }}{{ include "greeting" "harp" | upper }}`,
+			},
+			want: "HELLO HARP",
+		},
+		{
+			name: "local definition overrides library",
+			args: args{
+				input:     `{{ define "postgres.dsn" }}overridden{{ end }}{{ include "postgres.dsn" . }}`,
+				libraries: libraries,
+			},
+			want: "overridden",
+		},
+		{
+			name: "undefined template",
+			args: args{
+				input: `{{ include "missing" . }}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "recursion",
+			args: args{
+				input:     `{{ include "recursive" . }}`,
+				libraries: libraries,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid library",
+			args: args{
+				input:     `{{ "test" }}`,
+				libraries: map[string]string{"_invalid.tpl": `{{ define "unclosed" }}`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tpl",
+			args: args{
+				input:  `{{ tpl .Values.template .Values }}`,
+				values: Values{"template": "Hello {{ .name }}", "name": "harp"},
+			},
+			want: "Hello harp",
+		},
+		{
+			name: "tpl with include",
+			args: args{
+				input:     `{{ tpl .Values.template .Values }}`,
+				libraries: libraries,
+				values:    Values{"template": `{{ include "postgres.dsn" .db }}`, "db": map[string]interface{}{"user": "app", "host": "db.local", "port": 6432, "database": "billing"}},
+			},
+			want: "postgres://app@db.local:6432/billing",
+		},
+		{
+			name: "tpl with invalid template",
+			args: args{
+				input:  `{{ tpl .Values.template . }}`,
+				values: Values{"template": "{{ .name "},
+			},
+			wantErr: true,
+		},
+		{
+			name: "required",
+			args: args{
+				input:  `{{ required "name is required" .Values.name }}`,
+				values: Values{"name": "harp"},
+			},
+			want: "harp",
+		},
+		{
+			name: "required missing",
+			args: args{
+				input:  `{{ required "name is required" .Values.name }}`,
+				values: Values{"name": ""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sandbox output limit",
+			args: args{
+				input:     `{{ include "postgres.dsn" .Values.db | len }}`,
+				libraries: libraries,
+				values:    Values{"db": map[string]interface{}{"user": strings.Repeat("a", 2048)}},
+				sandbox:   &Sandbox{MaxOutputSize: 1024},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(
+				WithLibraries(tt.args.libraries),
+				WithValues(tt.args.values),
+				WithSandbox(tt.args.sandbox),
+			)
+			got, err := RenderContext(ctx, tt.args.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderContext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInclude_SandboxViolation(t *testing.T) {
+	ctx := NewContext(
+		WithLibraries(map[string]string{
+			"_helpers.tpl": `{{ define "large" }}{{ repeat 2048 "a" }}{{ end }}`,
+		}),
+		WithSandbox(&Sandbox{MaxOutputSize: 1024}),
+	)
+	_, err := RenderContext(ctx, `{{ include "large" . | len }}`)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSandboxViolation))
+}
+
+func TestInclude_Deterministic(t *testing.T) {
+	render := func() string {
+		ctx := NewContext(
+			WithLibraries(map[string]string{
+				"_helpers.tpl": `{{ define "password" }}{{ strongPassword }}{{ end }}`,
+			}),
+			WithValues(Values{"template": "{{ randAlphaNum 16 }}"}),
+			WithDeterministic(newDeterministic("seed")),
+		)
+		out, err := RenderContext(ctx, `{{ include "password" . }}|{{ tpl .Values.template . }}`)
+		require.NoError(t, err)
+		return out
+	}
+
+	assert.Equal(t, render(), render())
+}
+
+func TestRender_Include(t *testing.T) {
+	got, err := Render(`{{ define "name" }}{{ .name }}{{ end }}{{ include "name" . | upper }}`, map[string]interface{}{"name": "harp"})
+	require.NoError(t, err)
+	assert.Equal(t, "HARP", got)
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package drbg provides a deterministic random bit generator.
+//
+// It must only be used to produce reproducible outputs for testing purpose.
+package drbg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// New returns a deterministic random bit generator keyed by the given seed
+// and label. The generator is a ChaCha20 keystream using
+// HMAC-SHA256(seed, label) as key.
+func New(seed []byte, label string) io.Reader {
+	// Derive the stream key
+	mac := hmac.New(sha256.New, seed)
+	mac.Write([]byte(label))
+
+	// Key and nonce sizes are valid, no error can be raised.
+	c, _ := chacha20.NewUnauthenticatedCipher(mac.Sum(nil), make([]byte, chacha20.NonceSize))
+
+	return &reader{
+		cipher: c,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type reader struct {
+	cipher *chacha20.Cipher
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	// Clear the buffer to return the raw keystream
+	for i := range p {
+		p[i] = 0
+	}
+	r.cipher.XORKeyStream(p, p)
+
+	// No error
+	return len(p), nil
+}
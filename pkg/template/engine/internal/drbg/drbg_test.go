@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package drbg
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	read := func(r io.Reader, n int) []byte {
+		out := make([]byte, n)
+		if _, err := io.ReadFull(r, out); err != nil {
+			t.Fatalf("unable to read: %v", err)
+		}
+		return out
+	}
+
+	// Same seed and label
+	if !bytes.Equal(read(New([]byte("seed"), "label"), 1024), read(New([]byte("seed"), "label"), 1024)) {
+		t.Error("same seed and label must produce the same stream")
+	}
+
+	// Chunked reads produce the same stream
+	r := New([]byte("seed"), "label")
+	chunked := append(read(r, 7), read(r, 1017)...)
+	if !bytes.Equal(chunked, read(New([]byte("seed"), "label"), 1024)) {
+		t.Error("chunked reads must produce the same stream")
+	}
+
+	// Different label
+	if bytes.Equal(read(New([]byte("seed"), "label"), 32), read(New([]byte("seed"), "other"), 32)) {
+		t.Error("different labels must produce different streams")
+	}
+
+	// Different seed
+	if bytes.Equal(read(New([]byte("seed"), "label"), 32), read(New([]byte("other"), "label"), 32)) {
+		t.Error("different seeds must produce different streams")
+	}
+}
@@ -0,0 +1,424 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/gobwas/glob"
+)
+
+const (
+	// DefaultSandboxMaxOutputSize defines the default rendered output size
+	// limit in sandbox mode.
+	DefaultSandboxMaxOutputSize = 1024 * 1024
+	// DefaultSandboxTimeout defines the default template execution time limit
+	// in sandbox mode.
+	DefaultSandboxTimeout = 10 * time.Second
+	// SandboxMaxListSize defines the maximum item count of lists generated by
+	// `until`, `untilStep` and `seq` in sandbox mode.
+	SandboxMaxListSize = 10000
+)
+
+// ErrSandboxViolation is raised when a template tries to bypass sandbox
+// restrictions.
+var ErrSandboxViolation = errors.New("sandbox violation")
+
+// Sprig list generators, capped in sandbox mode.
+var (
+	sprigUntilStep = sprig.TxtFuncMap()["untilStep"].(func(int, int, int) []int)
+	sprigSeq       = sprig.TxtFuncMap()["seq"].(func(...int) string)
+)
+
+// Functions available in sandbox mode. Functions exposing the execution
+// environment (environment variables, network, wall clock, local timezone)
+// are not listed, and all functions not listed are disabled.
+var sandboxAllowedFuncs = map[string]bool{}
+
+func init() {
+	for _, names := range [][]string{
+		// Strings
+		{
+			"abbrev", "abbrevboth", "camelcase", "cat", "contains", "hasPrefix", "hasSuffix",
+			"indent", "initials", "kebabcase", "lower", "nindent", "nospace", "plural", "quote",
+			"repeat", "replace", "shuffle", "snakecase", "split", "splitList", "splitn", "squote",
+			"substr", "swapcase", "title", "toString", "toStrings", "trim", "trimAll", "trimPrefix",
+			"trimSuffix", "trimall", "trunc", "untitle", "upper", "wrap", "wrapWith", "hello",
+		},
+		// Regular expressions
+		{
+			"regexFind", "regexFindAll", "regexMatch", "regexQuoteMeta", "regexReplaceAll",
+			"regexReplaceAllLiteral", "regexSplit", "mustRegexFind", "mustRegexFindAll",
+			"mustRegexMatch", "mustRegexReplaceAll", "mustRegexReplaceAllLiteral", "mustRegexSplit",
+		},
+		// Math and conversions
+		{
+			"add", "add1", "add1f", "addf", "atoi", "biggest", "ceil", "div", "divf", "float64",
+			"floor", "int", "int64", "max", "maxf", "min", "minf", "mod", "mul", "mulf", "round",
+			"seq", "sub", "subf", "toDecimal", "until", "untilStep",
+		},
+		// Dates computed from given values
+		{
+			"dateModify", "date_modify", "mustDateModify", "must_date_modify", "duration", "unixEpoch",
+		},
+		// Defaults, flow control and reflection
+		{
+			"coalesce", "compact", "default", "empty", "fail", "ternary", "all", "any",
+			"deepEqual", "kindIs", "kindOf", "typeIs", "typeIsLike", "typeOf", "required",
+		},
+		// Lists and dictionaries
+		{
+			"append", "chunk", "concat", "dict", "dig", "first", "get", "has", "hasKey", "initial",
+			"keys", "last", "list", "merge", "mergeOverwrite", "omit", "pick", "pluck", "prepend",
+			"push", "rest", "reverse", "set", "slice", "sortAlpha", "tuple", "uniq", "unset",
+			"values", "without", "deepCopy", "mustAppend", "mustChunk", "mustCompact",
+			"mustDeepCopy", "mustFirst", "mustHas", "mustInitial", "mustLast", "mustMerge",
+			"mustMergeOverwrite", "mustPrepend", "mustPush", "mustRest", "mustReverse", "mustSlice",
+			"mustUniq", "mustWithout",
+		},
+		// Paths, URLs and versions
+		{
+			"base", "clean", "dir", "ext", "isAbs", "osBase", "osClean", "osDir", "osExt",
+			"osIsAbs", "urlJoin", "urlParse", "semver", "semverCompare",
+		},
+		// Encoding and escaping
+		{
+			"b32dec", "b32enc", "b64dec", "b64enc", "b64urldec", "b64urlenc", "bech32dec",
+			"bech32enc", "hexdec", "hexenc", "fromJson", "fromJsonArray", "fromYaml",
+			"fromYamlArray", "jsonEscape", "jsonUnescape", "mustFromJson", "mustToJson",
+			"mustToPrettyJson", "mustToRawJson", "shellEscape", "toJson", "toPrettyJson",
+			"toRawJson", "toToml", "toYaml", "unquote", "urlPathEscape", "urlPathUnescape",
+			"urlQueryEscape", "urlQueryUnescape",
+		},
+		// Hashes and symmetric encryption
+		{
+			"adler32sum", "bcrypt", "decryptAES", "derivePassword", "encryptAES", "htpasswd",
+			"sha1sum", "sha256sum", "sha512sum",
+		},
+		// Generators
+		{
+			"basicDiceware", "customDiceware", "paranoidDiceware", "strongDiceware",
+			"customPassword", "noSymbolPassword", "paranoidPassword", "strongPassword",
+			"randAlpha", "randAlphaNum", "randAscii", "randBytes", "randInt", "randNumeric",
+			"uuidv4", "otpSeed",
+		},
+		// One-time password without clock
+		{
+			"hotpCode", "hotpURI", "totpURI",
+		},
+		// Keys, tokens and certificates parsing
+		{
+			"buildCustomCert", "cryptoKey", "cryptoPair", "decryptJwe", "encryptJwe", "encryptPem",
+			"fromJwk", "genPrivateKey", "keyToBytes", "parseJwt", "parsePemCertificate",
+			"parsePemCertificateBundle", "parsePemCertificateRequest", "toJwk", "toJws", "toPem",
+			"toSSH", "toTLSA", "verifyJwt", "x509FullChain", "x509LoadCA", "sshKnownHostsCA",
+			"sshTrustedUserCAKey",
+		},
+		// Template and secrets, replaced by restricted implementations
+		{
+			"include", "tpl", "secret",
+		},
+	} {
+		for _, name := range names {
+			sandboxAllowedFuncs[name] = true
+		}
+	}
+}
+
+// Sandbox defines restrictions applied to untrusted template execution.
+//
+// In sandbox mode, environment, network and clock dependent functions are
+// disabled, and template files (`.Files`) are not exposed.
+type Sandbox struct {
+	// MaxOutputSize limits the rendered output size in bytes (0 for unlimited).
+	MaxOutputSize int
+	// Timeout limits the template execution time (0 for unlimited).
+	Timeout time.Duration
+	// SecretPaths defines path globs allowed for `secret` lookups. All
+	// lookups are denied if empty.
+	SecretPaths []string
+}
+
+// DefaultSandbox returns a sandbox profile with default limits and no secret
+// access.
+func DefaultSandbox() *Sandbox {
+	return &Sandbox{
+		MaxOutputSize: DefaultSandboxMaxOutputSize,
+		Timeout:       DefaultSandboxTimeout,
+	}
+}
+
+// FuncMap returns the template function map restricted by the sandbox.
+func (s *Sandbox) FuncMap(secretReaders []SecretReaderFunc) (template.FuncMap, error) {
+	return s.start().funcMap(secretReaders)
+}
+
+// -----------------------------------------------------------------------------
+
+// sandboxRun holds the limits of a sandboxed template execution, shared with
+// nested `include` and `tpl` executions.
+type sandboxRun struct {
+	sandbox  *Sandbox
+	deadline time.Time
+	stopped  atomic.Bool
+}
+
+// start returns the limits of a new sandboxed template execution.
+func (s *Sandbox) start() *sandboxRun {
+	r := &sandboxRun{
+		sandbox: s,
+	}
+	if s.Timeout > 0 {
+		r.deadline = time.Now().Add(s.Timeout)
+	}
+
+	return r
+}
+
+// check returns an error if the execution must be stopped.
+func (r *sandboxRun) check() error {
+	if r.stopped.Load() || (!r.deadline.IsZero() && time.Now().After(r.deadline)) {
+		return fmt.Errorf("template execution exceeded %s: %w", r.sandbox.Timeout, ErrSandboxViolation)
+	}
+
+	return nil
+}
+
+// checkSize returns an error if the given size exceeds the output size limit.
+func (r *sandboxRun) checkSize(size int) error {
+	if r.sandbox.MaxOutputSize > 0 && size > r.sandbox.MaxOutputSize {
+		return fmt.Errorf("rendered output exceeds %d bytes: %w", r.sandbox.MaxOutputSize, ErrSandboxViolation)
+	}
+
+	return nil
+}
+
+// funcMap returns the function map restricted by the sandbox. Allowed
+// functions check the execution deadline before each call.
+func (r *sandboxRun) funcMap(secretReaders []SecretReaderFunc) (template.FuncMap, error) {
+	// Compile secret path globs
+	matchers := make([]glob.Glob, 0, len(r.sandbox.SecretPaths))
+	for _, pattern := range r.sandbox.SecretPaths {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile sandbox secret path glob '%s': %w", pattern, err)
+		}
+		matchers = append(matchers, g)
+	}
+
+	// Disable all functions not explicitly allowed
+	f := template.FuncMap{}
+	for name, fn := range FuncMap(secretReaders) {
+		if !sandboxAllowedFuncs[name] {
+			funcName := name
+			fn = func(...interface{}) (interface{}, error) {
+				return nil, fmt.Errorf("function '%s' is disabled: %w", funcName, ErrSandboxViolation)
+			}
+		}
+		f[name] = fn
+	}
+
+	// Cap generated list and string sizes
+	f["until"] = func(count int) ([]int, error) {
+		step := 1
+		if count < 0 {
+			step = -1
+		}
+		if err := checkListSize(0, count, step); err != nil {
+			return nil, err
+		}
+		return sprigUntilStep(0, count, step), nil
+	}
+	f["untilStep"] = func(start, stop, step int) ([]int, error) {
+		if err := checkListSize(start, stop, step); err != nil {
+			return nil, err
+		}
+		return sprigUntilStep(start, stop, step), nil
+	}
+	f["seq"] = func(params ...int) (string, error) {
+		start, stop, step := 1, 0, 1
+		switch len(params) {
+		case 1:
+			stop = params[0]
+		case 2:
+			start, stop = params[0], params[1]
+		case 3:
+			start, step, stop = params[0], params[1], params[2]
+		}
+		if err := checkListSize(start, stop, step); err != nil {
+			return "", err
+		}
+		return sprigSeq(params...), nil
+	}
+	f["repeat"] = func(count int, str string) (string, error) {
+		if count > 0 && len(str) > 0 {
+			if len(str) > math.MaxInt/count {
+				return "", fmt.Errorf("repeat count %d is too large: %w", count, ErrSandboxViolation)
+			}
+			if err := r.checkSize(count * len(str)); err != nil {
+				return "", err
+			}
+		}
+		return strings.Repeat(str, max(count, 0)), nil
+	}
+
+	// Restrict secret lookups
+	lookup := SecretReaders(secretReaders)
+	f["secret"] = func(secretPath string) (map[string]interface{}, error) {
+		for _, m := range matchers {
+			if m.Match(secretPath) {
+				return lookup(secretPath)
+			}
+		}
+		return nil, fmt.Errorf("secret path '%s' is not allowed: %w", secretPath, ErrSandboxViolation)
+	}
+
+	// Check execution limits before each call
+	for name, fn := range f {
+		f[name] = r.guard(fn)
+	}
+
+	// No error
+	return f, nil
+}
+
+// guard wraps the given function to check the execution deadline before each
+// call, and the size of returned strings.
+func (r *sandboxRun) guard(fn interface{}) interface{} {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fn
+	}
+
+	t := v.Type()
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		// Errors are converted to execution errors by the template engine
+		if err := r.check(); err != nil {
+			panic(err)
+		}
+
+		var out []reflect.Value
+		if t.IsVariadic() {
+			out = v.CallSlice(args)
+		} else {
+			out = v.Call(args)
+		}
+
+		if len(out) > 0 && out[0].Kind() == reflect.String {
+			if err := r.checkSize(out[0].Len()); err != nil {
+				panic(err)
+			}
+		}
+
+		return out
+	}).Interface()
+}
+
+func checkListSize(start, stop, step int) error {
+	if step == 0 {
+		return nil
+	}
+
+	count := math.Abs((float64(stop) - float64(start)) / float64(step))
+	if count > SandboxMaxListSize {
+		return fmt.Errorf("generated list exceeds %d items: %w", SandboxMaxListSize, ErrSandboxViolation)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// execute runs the template with output size and execution time limits.
+//
+// Execution limits are checked on each function call and output write, a
+// template stops at the next check once the execution time limit is exceeded.
+func (r *sandboxRun) execute(t *template.Template, out *bytes.Buffer, data interface{}) error {
+	var result bytes.Buffer
+	w := r.writer(&result)
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("template rendering failed: %v", rec)
+			}
+		}()
+		done <- t.Execute(w, data)
+	}()
+
+	// Wait for completion
+	var timeout <-chan time.Time
+	if !r.deadline.IsZero() {
+		timer := time.NewTimer(time.Until(r.deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-timeout:
+		// Stop the template execution at the next check
+		r.stopped.Store(true)
+		return fmt.Errorf("template execution exceeded %s: %w", r.sandbox.Timeout, ErrSandboxViolation)
+	}
+
+	// Copy result
+	_, err := out.Write(result.Bytes())
+	return err
+}
+
+// writer returns an output writer checking the execution limits.
+func (r *sandboxRun) writer(w io.Writer) *sandboxWriter {
+	return &sandboxWriter{
+		w:   w,
+		run: r,
+	}
+}
+
+type sandboxWriter struct {
+	w       io.Writer
+	run     *sandboxRun
+	written int
+}
+
+func (sw *sandboxWriter) Write(p []byte) (int, error) {
+	if err := sw.run.check(); err != nil {
+		return 0, err
+	}
+	if err := sw.run.checkSize(sw.written + len(p)); err != nil {
+		return 0, err
+	}
+
+	n, err := sw.w.Write(p)
+	sw.written += n
+	return n, err
+}
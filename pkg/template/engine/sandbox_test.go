@@ -0,0 +1,280 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandbox(t *testing.T) {
+	secretReader := func(secretPath string) (map[string]interface{}, error) {
+		return map[string]interface{}{"path": secretPath}, nil
+	}
+
+	type args struct {
+		input   string
+		sandbox *Sandbox
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          string
+		wantErr       bool
+		wantViolation bool
+	}{
+		{
+			name: "without sandbox",
+			args: args{
+				input: `{{ .Files.Get "secret.txt" }}{{ (secret "infra/aws/db").path }}`,
+			},
+			want: "content" + "infra/aws/db",
+		},
+		{
+			name: "env",
+			args: args{
+				input:   `{{ env "HOME" }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "clock",
+			args: args{
+				input:   `{{ now }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "clock dependent duration",
+			args: args{
+				input:   `{{ durationRound "2h10m5s" }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "clock dependent certificate",
+			args: args{
+				input:   `{{ genCA "root" 365 }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "clock dependent x509 authority",
+			args: args{
+				input:   `{{ x509SelfSignedCA "root" 365 }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "allowed function",
+			args: args{
+				input:   `{{ "harp" | upper | b64enc }}`,
+				sandbox: DefaultSandbox(),
+			},
+			want: "SEFSUA==",
+		},
+		{
+			name: "files are hidden",
+			args: args{
+				input:   `{{ .Files.Get "secret.txt" }}`,
+				sandbox: DefaultSandbox(),
+			},
+			want: "",
+		},
+		{
+			name: "secret denied by default",
+			args: args{
+				input:   `{{ (secret "infra/aws/db").path }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "secret allowed",
+			args: args{
+				input:   `{{ (secret "app/production/billing/database").path }}`,
+				sandbox: &Sandbox{SecretPaths: []string{"app/production/billing/**"}},
+			},
+			want: "app/production/billing/database",
+		},
+		{
+			name: "secret not allowed",
+			args: args{
+				input:   `{{ (secret "app/production/customer/database").path }}`,
+				sandbox: &Sandbox{SecretPaths: []string{"app/production/billing/**"}},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "invalid secret path glob",
+			args: args{
+				input:   `{{ "foo" }}`,
+				sandbox: &Sandbox{SecretPaths: []string{"app/[production"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "output size",
+			args: args{
+				input:   `{{ repeat 11 "a" }}`,
+				sandbox: &Sandbox{MaxOutputSize: 10},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "repeat size",
+			args: args{
+				input:   `{{ repeat 1000000 "aa" }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "string result size",
+			args: args{
+				input:   `{{ $s := repeat 6 "a" | nindent 6 }}`,
+				sandbox: &Sandbox{MaxOutputSize: 10},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "until size",
+			args: args{
+				input:   `{{ range until 100000 }}{{ end }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "untilStep size",
+			args: args{
+				input:   `{{ untilStep 0 100000 2 }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "seq size",
+			args: args{
+				input:   `{{ seq 1 100000 }}`,
+				sandbox: DefaultSandbox(),
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "list generators",
+			args: args{
+				input:   `{{ until 3 }}{{ until -2 }}{{ untilStep 0 6 2 }}{{ seq 3 }}`,
+				sandbox: DefaultSandbox(),
+			},
+			want: "[0 1 2][0 -1][0 2 4]1 2 3",
+		},
+		{
+			name: "execution time",
+			args: args{
+				input:   `{{ range until 10000 }}{{ range until 10000 }}a{{ end }}{{ end }}`,
+				sandbox: &Sandbox{Timeout: 50 * time.Millisecond},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "execution time without output",
+			args: args{
+				input:   `{{ range until 10000 }}{{ range until 10000 }}{{ $x := add1 1 }}{{ end }}{{ end }}`,
+				sandbox: &Sandbox{Timeout: 50 * time.Millisecond},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "nested include limits",
+			args: args{
+				input:   `{{ define "loop" }}{{ range until 10000 }}{{ range until 10000 }}a{{ end }}{{ end }}{{ end }}{{ $x := include "loop" . }}`,
+				sandbox: &Sandbox{Timeout: 50 * time.Millisecond},
+			},
+			wantErr:       true,
+			wantViolation: true,
+		},
+		{
+			name: "valid",
+			args: args{
+				input:   `{{ .Values.foo | upper }}`,
+				sandbox: DefaultSandbox(),
+			},
+			want: "BAR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateContext := NewContext(
+				WithValues(Values{"foo": "bar"}),
+				WithFiles(Files{"secret.txt": []byte("content")}),
+				WithSecretReaders(secretReader),
+				WithSandbox(tt.args.sandbox),
+			)
+
+			got, err := RenderContext(templateContext, tt.args.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderContext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantViolation {
+				assert.True(t, errors.Is(err, ErrSandboxViolation), "expected a sandbox violation, got %v", err)
+			}
+			assert.Equal(t, tt.want, strings.TrimSpace(got))
+		})
+	}
+}
+
+func TestSandbox_AllowedFuncs(t *testing.T) {
+	funcs := FuncMap(nil)
+
+	// Allowed functions must exist
+	for name := range sandboxAllowedFuncs {
+		_, ok := funcs[name]
+		assert.True(t, ok, "allowed function '%s' is not defined", name)
+	}
+
+	// Deterministic bindings must not bypass the sandbox
+	for name := range deterministicFuncs {
+		assert.True(t, sandboxAllowedFuncs[name], "deterministic function '%s' is not allowed in sandbox", name)
+	}
+}
@@ -18,28 +18,95 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 )
 
+// ErrSecretNotFound is raised by secret readers when the secret path doesn't
+// exist. Other secret reader errors are considered as lookup failures.
+var ErrSecretNotFound = errors.New("secret not found")
+
 // SecretReaderFunc is a function to retrieve a secret from a given path.
 type SecretReaderFunc func(path string) (map[string]interface{}, error)
 
 // SecretReaders uses given secret reader funcs to resolve secret path.
+//
+// Secret readers are queried in order, the next one is queried only if the
+// secret path is not found. Lookup failures are returned immediately.
 func SecretReaders(secretReaders []SecretReaderFunc) func(string) (map[string]interface{}, error) {
 	return func(secretPath string) (map[string]interface{}, error) {
 		// For all secret readers
 		for _, sr := range secretReaders {
 			value, err := sr(secretPath)
-			if err != nil {
+			switch {
+			case err == nil:
+				// No error
+				return value, nil
+			case errors.Is(err, ErrSecretNotFound):
 				// Check next secret reader
 				continue
+			default:
+				return nil, fmt.Errorf("unable to read secret '%s': %w", secretPath, err)
 			}
-
-			// No error
-			return value, nil
 		}
 
 		// Return error
-		return nil, fmt.Errorf("no value found for '%s', check secret path or secret reader settings", secretPath)
+		return nil, fmt.Errorf("no value found for '%s', check secret path or secret reader settings: %w", secretPath, ErrSecretNotFound)
+	}
+}
+
+// CachedSecretReader wraps the given secret reader to query each secret path
+// only once. Not found errors are also cached, other lookup failures are not
+// cached to be retried.
+func CachedSecretReader(secretReader SecretReaderFunc) SecretReaderFunc {
+	type entry struct {
+		value map[string]interface{}
+		err   error
+	}
+
+	var (
+		mu    sync.Mutex
+		cache = map[string]entry{}
+	)
+
+	return func(secretPath string) (map[string]interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		e, ok := cache[secretPath]
+		if !ok {
+			value, err := secretReader(secretPath)
+			if err != nil && !errors.Is(err, ErrSecretNotFound) {
+				return nil, err
+			}
+			e = entry{value: value, err: err}
+			cache[secretPath] = e
+		}
+		if e.err != nil {
+			return nil, e.err
+		}
+
+		// Return a copy to prevent cached value alteration
+		value := make(map[string]interface{}, len(e.value))
+		for k, v := range e.value {
+			value[k] = v
+		}
+
+		// No error
+		return value, nil
+	}
+}
+
+// NotFoundAs wraps the given secret reader to report errors matching the
+// given backend specific not found error as ErrSecretNotFound.
+func NotFoundAs(secretReader SecretReaderFunc, notFound error) SecretReaderFunc {
+	return func(secretPath string) (map[string]interface{}, error) {
+		value, err := secretReader(secretPath)
+		if err != nil && errors.Is(err, notFound) && !errors.Is(err, ErrSecretNotFound) {
+			return nil, fmt.Errorf("%w: %w", ErrSecretNotFound, err)
+		}
+
+		return value, err
 	}
 }
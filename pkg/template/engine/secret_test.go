@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCachedSecretReader(t *testing.T) {
+	calls := map[string]int{}
+	secretReader := CachedSecretReader(func(path string) (map[string]interface{}, error) {
+		calls[path]++
+		switch path {
+		case "unknown":
+			return nil, ErrSecretNotFound
+		case "unavailable":
+			return nil, errors.New("connection refused")
+		}
+		return map[string]interface{}{"key": path}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		value, err := secretReader("app/secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if value["key"] != "app/secret" {
+			t.Fatalf("unexpected value: %v", value)
+		}
+
+		// Altering the returned value must not alter the cache
+		value["key"] = "altered"
+
+		if _, err := secretReader("unknown"); !errors.Is(err, ErrSecretNotFound) {
+			t.Fatalf("not found error should be raised, got %v", err)
+		}
+		if _, err := secretReader("unavailable"); err == nil {
+			t.Fatal("error should be raised")
+		}
+	}
+
+	if calls["app/secret"] != 1 {
+		t.Errorf("secret reader called %d times for 'app/secret', expected 1", calls["app/secret"])
+	}
+	if calls["unknown"] != 1 {
+		t.Errorf("secret reader called %d times for 'unknown', expected 1", calls["unknown"])
+	}
+	if calls["unavailable"] != 3 {
+		t.Errorf("secret reader called %d times for 'unavailable', expected 3", calls["unavailable"])
+	}
+}
+
+func TestSecretReaders(t *testing.T) {
+	notFound := func(path string) (map[string]interface{}, error) {
+		return nil, fmt.Errorf("unable to lookup '%s': %w", path, ErrSecretNotFound)
+	}
+	unavailable := func(path string) (map[string]interface{}, error) {
+		return nil, errors.New("connection refused")
+	}
+	found := func(path string) (map[string]interface{}, error) {
+		return map[string]interface{}{"key": path}, nil
+	}
+
+	tests := []struct {
+		name          string
+		secretReaders []SecretReaderFunc
+		want          map[string]interface{}
+		wantNotFound  bool
+		wantErr       bool
+	}{
+		{
+			name:         "empty",
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:          "not found",
+			secretReaders: []SecretReaderFunc{notFound, notFound},
+			wantErr:       true,
+			wantNotFound:  true,
+		},
+		{
+			name:          "fallback on not found",
+			secretReaders: []SecretReaderFunc{notFound, found},
+			want:          map[string]interface{}{"key": "app/secret"},
+		},
+		{
+			name:          "lookup failure",
+			secretReaders: []SecretReaderFunc{unavailable, found},
+			wantErr:       true,
+		},
+		{
+			name:          "translated not found",
+			secretReaders: []SecretReaderFunc{NotFoundAs(func(string) (map[string]interface{}, error) { return nil, errBackendNotFound }, errBackendNotFound), found},
+			want:          map[string]interface{}{"key": "app/secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SecretReaders(tt.secretReaders)("app/secret")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretReaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrSecretNotFound) != tt.wantNotFound {
+				t.Fatalf("SecretReaders() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SecretReaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+var errBackendNotFound = errors.New("backend: path not found")
@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Run executes the hook command with its timeout.
+func (h *Hook) Run(ctx context.Context, stdout, stderr io.Writer) error {
+	// Check arguments
+	if len(h.Command) == 0 {
+		return fmt.Errorf("unable to run hook '%s' without command", h.Name)
+	}
+
+	// Apply timeout
+	timeout, err := h.timeout()
+	if err != nil {
+		return fmt.Errorf("unable to run hook '%s': %w", h.Name, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Prepare command
+	//nolint:gosec // command is declared by the render manifest
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// Execute
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("hook '%s' exceeded %s timeout: %w", h.Name, timeout, err)
+		}
+		return fmt.Errorf("hook '%s' failed: %w", h.Name, err)
+	}
+
+	// No error
+	return nil
+}
+
+// Notified returns hooks notified by the given changed files, in declaration
+// order.
+func (m *RenderManifest) Notified(changed []*File) []*Hook {
+	notified := map[string]struct{}{}
+	for _, f := range changed {
+		for _, name := range f.Notify {
+			notified[name] = struct{}{}
+		}
+	}
+
+	hooks := []*Hook{}
+	for _, h := range m.Spec.Hooks {
+		if _, ok := notified[h.Name]; ok {
+			hooks = append(hooks, h)
+		}
+	}
+
+	return hooks
+}
+
+// MarkPending records that hooks notified by the given file have not been
+// executed yet, so that they are run again on the next render even if the
+// file content is unchanged.
+func MarkPending(filename string) error {
+	// Create intermediate directories
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create intermediate directories for path '%s': %w", dir, err)
+	}
+
+	if err := os.WriteFile(pendingMarker(filename), nil, 0o600); err != nil {
+		return fmt.Errorf("unable to mark hooks of '%s' as pending: %w", filename, err)
+	}
+
+	// No error
+	return nil
+}
+
+// Pending returns true if hooks notified by the given file are still pending.
+func Pending(filename string) (bool, error) {
+	_, err := os.Lstat(pendingMarker(filename))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("unable to check pending hooks of '%s': %w", filename, err)
+	}
+
+	return true, nil
+}
+
+// ClearPending removes the pending hooks marker of the given file.
+func ClearPending(filename string) error {
+	if err := os.Remove(pendingMarker(filename)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to clear pending hooks of '%s': %w", filename, err)
+	}
+
+	// No error
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func pendingMarker(filename string) string {
+	return filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+".harp-pending")
+}
@@ -0,0 +1,88 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix commands are not available")
+	}
+
+	tests := []struct {
+		name    string
+		hook    *Hook
+		wantErr bool
+	}{
+		{
+			name: "success",
+			hook: &Hook{Name: "ok", Command: []string{"true"}},
+		},
+		{
+			name:    "failure",
+			hook:    &Hook{Name: "ko", Command: []string{"false"}},
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			hook:    &Hook{Name: "slow", Command: []string{"sleep", "5"}, Timeout: "100ms"},
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			hook:    &Hook{Name: "missing", Command: []string{"harp-missing-command"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hook.Run(context.Background(), nil, nil); (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "conf.d", "app.conf")
+
+	pending, err := Pending(filename)
+	require.NoError(t, err)
+	assert.False(t, pending)
+
+	require.NoError(t, MarkPending(filename))
+	pending, err = Pending(filename)
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	require.NoError(t, ClearPending(filename))
+	pending, err = Pending(filename)
+	require.NoError(t, err)
+	assert.False(t, pending)
+
+	// Clearing twice is not an error
+	assert.NoError(t, ClearPending(filename))
+}
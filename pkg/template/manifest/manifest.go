@@ -0,0 +1,228 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion defines the supported render manifest API version.
+	APIVersion = "harp.elastic.co/v1"
+	// Kind defines the render manifest kind.
+	Kind = "RenderManifest"
+	// DefaultFileMode defines the mode of created files without explicit mode.
+	DefaultFileMode fs.FileMode = 0o644
+	// DefaultHookTimeout defines the hook execution time limit.
+	DefaultHookTimeout = 30 * time.Second
+)
+
+// RenderManifest describes templates to render, their destinations and the
+// hooks to run when outputs change.
+type RenderManifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Meta       *Meta  `json:"meta,omitempty"`
+	Spec       *Spec  `json:"spec"`
+}
+
+// Meta holds manifest metadata.
+type Meta struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec holds render manifest specification.
+type Spec struct {
+	Files []*File `json:"files"`
+	Hooks []*Hook `json:"hooks,omitempty"`
+}
+
+// File maps a template to its destination.
+type File struct {
+	// Template path in the input filesystem.
+	Template string `json:"template"`
+	// Destination path, relative paths are resolved from the output path.
+	Destination string `json:"destination"`
+	// Mode as an octal string (default: existing file mode or 0644).
+	Mode string `json:"mode,omitempty"`
+	// UID sets the file owner user id.
+	UID *int `json:"uid,omitempty"`
+	// GID sets the file owner group id.
+	GID *int `json:"gid,omitempty"`
+	// Notify lists hooks to run when the file changes.
+	Notify []string `json:"notify,omitempty"`
+}
+
+// Hook describes a command to run when notified by a changed file.
+type Hook struct {
+	Name string `json:"name"`
+	// Command and its arguments, executed without shell.
+	Command []string `json:"command"`
+	// Timeout as a duration (default: 30s).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+
+// Load reads and validates a YAML or JSON encoded render manifest.
+func Load(r io.Reader) (*RenderManifest, error) {
+	// Check arguments
+	if r == nil {
+		return nil, errors.New("unable to load manifest from a nil reader")
+	}
+
+	// Drain reader
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest: %w", err)
+	}
+
+	// Decode manifest
+	var m RenderManifest
+	if err := yaml.UnmarshalStrict(payload, &m); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest: %w", err)
+	}
+
+	// Validate manifest
+	if err := Validate(&m); err != nil {
+		return nil, err
+	}
+
+	// No error
+	return &m, nil
+}
+
+// Validate render manifest.
+//
+//nolint:gocyclo // validation rules
+func Validate(m *RenderManifest) error {
+	// Check arguments
+	if m == nil {
+		return errors.New("unable to validate render manifest: manifest is nil")
+	}
+	if m.APIVersion != APIVersion {
+		return fmt.Errorf("apiVersion should be '%s'", APIVersion)
+	}
+	if m.Kind != Kind {
+		return fmt.Errorf("kind should be '%s'", Kind)
+	}
+	if m.Spec == nil {
+		return errors.New("spec should not be nil")
+	}
+	if len(m.Spec.Files) == 0 {
+		return errors.New("spec.files should not be empty")
+	}
+
+	// Check hooks
+	hooks := map[string]struct{}{}
+	for i, h := range m.Spec.Hooks {
+		if h == nil || h.Name == "" {
+			return fmt.Errorf("spec.hooks[%d]: name should not be blank", i)
+		}
+		if _, ok := hooks[h.Name]; ok {
+			return fmt.Errorf("spec.hooks[%d]: duplicate hook '%s'", i, h.Name)
+		}
+		if len(h.Command) == 0 || h.Command[0] == "" {
+			return fmt.Errorf("spec.hooks[%d]: command should not be empty", i)
+		}
+		if _, err := h.timeout(); err != nil {
+			return fmt.Errorf("spec.hooks[%d]: %w", i, err)
+		}
+		hooks[h.Name] = struct{}{}
+	}
+
+	// Check files
+	destinations := map[string]struct{}{}
+	for i, f := range m.Spec.Files {
+		if f == nil || f.Template == "" {
+			return fmt.Errorf("spec.files[%d]: template should not be blank", i)
+		}
+		if !fs.ValidPath(f.Template) {
+			return fmt.Errorf("spec.files[%d]: template '%s' is not a valid path", i, f.Template)
+		}
+		if f.Destination == "" {
+			return fmt.Errorf("spec.files[%d]: destination should not be blank", i)
+		}
+		if _, ok := destinations[f.Destination]; ok {
+			return fmt.Errorf("spec.files[%d]: duplicate destination '%s'", i, f.Destination)
+		}
+		if _, err := f.Options(); err != nil {
+			return fmt.Errorf("spec.files[%d]: %w", i, err)
+		}
+		for _, name := range f.Notify {
+			if _, ok := hooks[name]; !ok {
+				return fmt.Errorf("spec.files[%d]: unknown hook '%s'", i, name)
+			}
+		}
+		destinations[f.Destination] = struct{}{}
+	}
+
+	// No error
+	return nil
+}
+
+// Options returns file write options.
+func (f *File) Options() (*FileOptions, error) {
+	opts := &FileOptions{
+		UID: f.UID,
+		GID: f.GID,
+	}
+
+	// Parse mode
+	if f.Mode != "" {
+		mode, err := strconv.ParseUint(f.Mode, 8, 32)
+		if err != nil || mode > 0o777 {
+			return nil, fmt.Errorf("invalid file mode '%s'", f.Mode)
+		}
+		fileMode := fs.FileMode(mode)
+		opts.Mode = &fileMode
+	}
+
+	// Check ownership
+	if f.UID != nil && *f.UID < 0 {
+		return nil, fmt.Errorf("invalid uid '%d'", *f.UID)
+	}
+	if f.GID != nil && *f.GID < 0 {
+		return nil, fmt.Errorf("invalid gid '%d'", *f.GID)
+	}
+
+	// No error
+	return opts, nil
+}
+
+func (h *Hook) timeout() (time.Duration, error) {
+	if h.Timeout == "" {
+		return DefaultHookTimeout, nil
+	}
+
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout '%s'", h.Timeout)
+	}
+
+	// No error
+	return d, nil
+}
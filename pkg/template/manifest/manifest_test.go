@@ -0,0 +1,227 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			input: `apiVersion: harp.elastic.co/v1
+kind: RenderManifest
+meta:
+  name: nginx
+spec:
+  files:
+    - template: nginx.conf
+      destination: /etc/nginx/nginx.conf
+      mode: "0640"
+      uid: 0
+      gid: 33
+      notify: [reload]
+  hooks:
+    - name: reload
+      command: ["nginx", "-s", "reload"]
+      timeout: 10s
+`,
+		},
+		{
+			name:    "invalid yaml",
+			input:   `apiVersion: [`,
+			wantErr: true,
+		},
+		{
+			name: "unknown field",
+			input: `apiVersion: harp.elastic.co/v1
+kind: RenderManifest
+spec:
+  files:
+    - template: nginx.conf
+      destination: nginx.conf
+      owner: root
+`,
+			wantErr: true,
+		},
+		{
+			name: "invalid kind",
+			input: `apiVersion: harp.elastic.co/v1
+kind: BundlePatch
+spec:
+  files:
+    - template: nginx.conf
+      destination: nginx.conf
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	manifest := func(files []*File, hooks []*Hook) *RenderManifest {
+		return &RenderManifest{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+			Spec: &Spec{
+				Files: files,
+				Hooks: hooks,
+			},
+		}
+	}
+	negative := -1
+
+	tests := []struct {
+		name    string
+		m       *RenderManifest
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name:    "invalid apiVersion",
+			m:       &RenderManifest{APIVersion: "v1", Kind: Kind, Spec: &Spec{}},
+			wantErr: true,
+		},
+		{
+			name:    "nil spec",
+			m:       &RenderManifest{APIVersion: APIVersion, Kind: Kind},
+			wantErr: true,
+		},
+		{
+			name:    "no files",
+			m:       manifest(nil, nil),
+			wantErr: true,
+		},
+		{
+			name:    "blank template",
+			m:       manifest([]*File{{Destination: "a"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "invalid template path",
+			m:       manifest([]*File{{Template: "../a", Destination: "a"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "blank destination",
+			m:       manifest([]*File{{Template: "a"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate destination",
+			m:       manifest([]*File{{Template: "a", Destination: "a"}, {Template: "b", Destination: "a"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			m:       manifest([]*File{{Template: "a", Destination: "a", Mode: "0999"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "special mode bits",
+			m:       manifest([]*File{{Template: "a", Destination: "a", Mode: "4755"}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "negative uid",
+			m:       manifest([]*File{{Template: "a", Destination: "a", UID: &negative}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "unknown hook",
+			m:       manifest([]*File{{Template: "a", Destination: "a", Notify: []string{"reload"}}}, nil),
+			wantErr: true,
+		},
+		{
+			name:    "blank hook name",
+			m:       manifest([]*File{{Template: "a", Destination: "a"}}, []*Hook{{Command: []string{"true"}}}),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate hook",
+			m:       manifest([]*File{{Template: "a", Destination: "a"}}, []*Hook{{Name: "a", Command: []string{"true"}}, {Name: "a", Command: []string{"true"}}}),
+			wantErr: true,
+		},
+		{
+			name:    "empty hook command",
+			m:       manifest([]*File{{Template: "a", Destination: "a"}}, []*Hook{{Name: "a"}}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid hook timeout",
+			m:       manifest([]*File{{Template: "a", Destination: "a"}}, []*Hook{{Name: "a", Command: []string{"true"}, Timeout: "-1s"}}),
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			m:    manifest([]*File{{Template: "a", Destination: "a", Mode: "0600", Notify: []string{"a"}}}, []*Hook{{Name: "a", Command: []string{"true"}}}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.m); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFile_Options(t *testing.T) {
+	uid := 1000
+	opts, err := (&File{Mode: "0640", UID: &uid}).Options()
+	require.NoError(t, err)
+	require.NotNil(t, opts.Mode)
+	assert.Equal(t, fs.FileMode(0o640), *opts.Mode)
+	assert.Equal(t, &uid, opts.UID)
+	assert.Nil(t, opts.GID)
+}
+
+func TestRenderManifest_Notified(t *testing.T) {
+	m := &RenderManifest{
+		Spec: &Spec{
+			Hooks: []*Hook{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		},
+	}
+
+	hooks := m.Notified([]*File{{Notify: []string{"c", "a"}}, {Notify: []string{"a"}}})
+	require.Len(t, hooks, 2)
+	assert.Equal(t, "a", hooks[0].Name)
+	assert.Equal(t, "c", hooks[1].Name)
+	assert.Empty(t, m.Notified(nil))
+}
@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileOptions defines written file attributes, nil attributes are left
+// unchanged.
+type FileOptions struct {
+	Mode *fs.FileMode
+	UID  *int
+	GID  *int
+}
+
+// Changed returns true if the given file content or attributes differ from
+// the expected ones. A symbolic link destination is compared through its
+// target.
+func Changed(filename string, content []byte, opts *FileOptions) (bool, error) {
+	// Check arguments
+	if opts == nil {
+		opts = &FileOptions{}
+	}
+
+	// Follow symbolic links
+	filename, err := resolveLink(filename)
+	if err != nil {
+		return false, err
+	}
+
+	// Retrieve current file state
+	fi, err := os.Lstat(filename)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("unable to retrieve file information for '%s': %w", filename, err)
+	case !fi.Mode().IsRegular():
+		return false, fmt.Errorf("destination '%s' is not a regular file", filename)
+	}
+
+	// Compare attributes
+	if opts.Mode != nil && fi.Mode().Perm() != *opts.Mode {
+		return true, nil
+	}
+	if uid, gid, ok := fileOwner(fi); ok {
+		if (opts.UID != nil && uid != *opts.UID) || (opts.GID != nil && gid != *opts.GID) {
+			return true, nil
+		}
+	}
+
+	// Compare content
+	current, err := os.ReadFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("unable to read file '%s': %w", filename, err)
+	}
+
+	return !bytes.Equal(current, content), nil
+}
+
+// WriteFile atomically replaces the file content and attributes if they
+// differ from the expected ones. It returns true if the file has been written.
+//
+// The content is written to a temporary file in the destination directory,
+// which is renamed to the destination once synced. A symbolic link
+// destination is preserved and its target is replaced.
+func WriteFile(filename string, content []byte, opts *FileOptions) (bool, error) {
+	// Check arguments
+	if opts == nil {
+		opts = &FileOptions{}
+	}
+
+	// Follow symbolic links
+	filename, err := resolveLink(filename)
+	if err != nil {
+		return false, err
+	}
+
+	// Skip unchanged files
+	changed, err := Changed(filename, content, opts)
+	if err != nil {
+		return false, err
+	}
+	if !changed {
+		return false, nil
+	}
+
+	// Keep the current mode if not specified
+	mode := DefaultFileMode
+	if opts.Mode != nil {
+		mode = *opts.Mode
+	} else if fi, errStat := os.Stat(filename); errStat == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	// Create intermediate directories
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("unable to create intermediate directories for path '%s': %w", dir, err)
+	}
+
+	// Write to a temporary file
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".harp-*")
+	if err != nil {
+		return false, fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	if err := writeTemp(tmp, content, mode, opts); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return false, err
+	}
+
+	// Replace destination
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		_ = os.Remove(tmp.Name())
+		return false, fmt.Errorf("unable to replace file '%s': %w", filename, err)
+	}
+
+	// No error
+	return true, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func resolveLink(filename string) (string, error) {
+	fi, err := os.Lstat(filename)
+	if err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		return filename, nil
+	}
+
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve symbolic link '%s': %w", filename, err)
+	}
+
+	return target, nil
+}
+
+func writeTemp(tmp *os.File, content []byte, mode fs.FileMode, opts *FileOptions) error {
+	if _, err := tmp.Write(content); err != nil {
+		return fmt.Errorf("unable to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("unable to set file mode: %w", err)
+	}
+	if opts.UID != nil || opts.GID != nil {
+		uid, gid := -1, -1
+		if opts.UID != nil {
+			uid = *opts.UID
+		}
+		if opts.GID != nil {
+			gid = *opts.GID
+		}
+		if err := tmp.Chown(uid, gid); err != nil {
+			return fmt.Errorf("unable to set file owner: %w", err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("unable to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to close temporary file: %w", err)
+	}
+
+	// No error
+	return nil
+}
@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package manifest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf.d", "app.conf")
+	mode := fs.FileMode(0o600)
+	opts := &FileOptions{Mode: &mode}
+
+	// Create
+	changed, err := WriteFile(filename, []byte("v1"), opts)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(content))
+	fi, err := os.Stat(filename)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, mode, fi.Mode().Perm())
+	}
+
+	// Unchanged
+	changed, err = WriteFile(filename, []byte("v1"), opts)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Content update
+	changed, err = WriteFile(filename, []byte("v2"), opts)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// Mode update
+	if runtime.GOOS != "windows" {
+		newMode := fs.FileMode(0o640)
+		changed, err = WriteFile(filename, []byte("v2"), &FileOptions{Mode: &newMode})
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		// Mode is kept when not specified
+		changed, err = WriteFile(filename, []byte("v3"), nil)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		fi, err = os.Stat(filename)
+		require.NoError(t, err)
+		assert.Equal(t, newMode, fi.Mode().Perm())
+	}
+
+	// No temporary file left
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestChanged_NotRegular(t *testing.T) {
+	_, err := Changed(t.TempDir(), []byte("test"), nil)
+	assert.Error(t, err)
+}
+
+func TestWriteFile_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on windows")
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "app.conf.v1")
+	link := filepath.Join(dir, "app.conf")
+	require.NoError(t, os.WriteFile(target, []byte("v1"), 0o600))
+	require.NoError(t, os.Symlink(target, link))
+
+	// Unchanged target
+	changed, err := Changed(link, []byte("v1"), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Target is replaced, link is preserved
+	changed, err = WriteFile(link, []byte("v2"), nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	fi, err := os.Lstat(link)
+	require.NoError(t, err)
+	assert.NotZero(t, fi.Mode()&fs.ModeSymlink)
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+
+	// Dangling link
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing", "file"), filepath.Join(dir, "dangling")))
+	_, err = WriteFile(filepath.Join(dir, "dangling"), []byte("v1"), nil)
+	assert.Error(t, err)
+}
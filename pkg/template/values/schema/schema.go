@@ -0,0 +1,183 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/elastic/harp/pkg/sdk/convert"
+)
+
+// DefaultFileName is the conventional values schema file name.
+const DefaultFileName = "values.schema.json"
+
+// Violation describes a value which doesn't conform to the schema.
+type Violation struct {
+	// Path is the JSON pointer of the invalid value.
+	Path string `json:"path"`
+	// Message describes the violated constraint.
+	Message string `json:"message"`
+}
+
+// ValidationError is raised when values don't conform to the schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns the error message with all violations.
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("values don't conform to the schema:")
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = gojsonschema.STRING_CONTEXT_ROOT
+		}
+		fmt.Fprintf(&sb, "\n - %s: %s", path, v.Message)
+	}
+
+	return sb.String()
+}
+
+// -----------------------------------------------------------------------------
+
+// FromReader reads a JSON or YAML encoded schema definition.
+func FromReader(r io.Reader) ([]byte, error) {
+	// Convert to JSON
+	jsonReader, err := convert.YAMLtoJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse schema definition: %w", err)
+	}
+
+	// Drain reader
+	definition, err := io.ReadAll(jsonReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to drain all json reader content: %w", err)
+	}
+
+	// No error
+	return definition, nil
+}
+
+// FromStruct encodes a schema definition embedded in a specification.
+func FromStruct(s *structpb.Struct) ([]byte, error) {
+	// Check arguments
+	if s == nil {
+		return nil, fmt.Errorf("schema definition is nil")
+	}
+
+	// Encode as JSON
+	definition, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode schema definition: %w", err)
+	}
+
+	// No error
+	return definition, nil
+}
+
+// Validate the given values using the given JSON schema definition.
+// A *ValidationError is returned when values don't conform to the schema.
+func Validate(definition []byte, values map[string]interface{}) error {
+	// Check arguments
+	if len(definition) == 0 {
+		return fmt.Errorf("schema definition is blank")
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	// Compile schema
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(definition))
+	if err != nil {
+		return fmt.Errorf("unable to compile values schema: %w", err)
+	}
+
+	// Normalize values as JSON document
+	var doc bytes.Buffer
+	if err := json.NewEncoder(&doc).Encode(values); err != nil {
+		return fmt.Errorf("unable to encode values as JSON: %w", err)
+	}
+
+	// Validate values
+	result, err := s.Validate(gojsonschema.NewBytesLoader(doc.Bytes()))
+	if err != nil {
+		return fmt.Errorf("unable to validate values: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	// Collect violations
+	violations := make([]Violation, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		violations = append(violations, Violation{
+			Path:    pointer(e),
+			Message: e.Description(),
+		})
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+
+	return &ValidationError{
+		Violations: violations,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+const contextSeparator = "\x00"
+
+// pointer returns the RFC 6901 JSON pointer of the value related to the
+// given result error.
+func pointer(e gojsonschema.ResultError) string {
+	var parts []string
+	if ctx := e.Context(); ctx != nil {
+		parts = strings.Split(ctx.String(contextSeparator), contextSeparator)
+	}
+
+	// Remove root element
+	if len(parts) > 0 && parts[0] == gojsonschema.STRING_CONTEXT_ROOT {
+		parts = parts[1:]
+	}
+
+	// Point to the property itself when the error is raised by the parent.
+	switch e.Type() {
+	case "required", "additional_property_not_allowed":
+		if property, ok := e.Details()["property"].(string); ok {
+			parts = append(parts, property)
+		}
+	}
+
+	var sb strings.Builder
+	for _, p := range parts {
+		sb.WriteString("/")
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(p))
+	}
+
+	return sb.String()
+}
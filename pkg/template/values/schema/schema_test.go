@@ -0,0 +1,118 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSchema = `
+type: object
+required:
+  - database
+properties:
+  database:
+    type: object
+    additionalProperties: false
+    required:
+      - host
+    properties:
+      host:
+        type: string
+      port:
+        type: integer
+      "tls/mode":
+        type: string
+        enum: [disable, require]
+`
+
+func TestValidate(t *testing.T) {
+	definition, err := FromReader(strings.NewReader(testSchema))
+	if err != nil {
+		t.Fatalf("unable to read schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr bool
+		want    []Violation
+	}{
+		{
+			name:   "valid",
+			values: map[string]interface{}{"database": map[string]interface{}{"host": "localhost", "port": 5432}},
+		},
+		{
+			name:    "nil values",
+			wantErr: true,
+			want: []Violation{
+				{Path: "/database", Message: "database is required"},
+			},
+		},
+		{
+			name: "invalid values",
+			values: map[string]interface{}{
+				"database": map[string]interface{}{
+					"port":     "5432",
+					"hots":     "localhost",
+					"tls/mode": "verify",
+				},
+			},
+			wantErr: true,
+			want: []Violation{
+				{Path: "/database/host", Message: "host is required"},
+				{Path: "/database/hots", Message: "Additional property hots is not allowed"},
+				{Path: "/database/port", Message: "Invalid type. Expected: integer, given: string"},
+				{Path: "/database/tls~1mode", Message: `database.tls/mode must be one of the following: "disable", "require"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(definition, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want a ValidationError", err)
+			}
+			if diff := cmp.Diff(tt.want, verr.Violations); diff != "" {
+				t.Errorf("Validate() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidSchema(t *testing.T) {
+	if err := Validate(nil, nil); err == nil {
+		t.Error("Validate() expected error for blank schema")
+	}
+	if err := Validate([]byte(`{"type": 1}`), nil); err == nil {
+		t.Error("Validate() expected error for invalid schema")
+	}
+}
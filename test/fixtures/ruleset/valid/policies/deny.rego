@@ -0,0 +1,19 @@
+package harp
+
+import data.harp.lib
+
+deny[msg] {
+    not lib.has_owner(input)
+    msg := "package must declare an owner"
+}
+
+deny[msg] {
+    owner := lib.owner(input)
+    not data.owners[owner]
+    msg := sprintf("owner '%s' is not registered", [owner])
+}
+
+deny[msg] {
+    not input.bundle.labels.environment
+    msg := "bundle must declare its environment"
+}
@@ -0,0 +1,11 @@
+package harp.lib
+
+owner_annotation := "harp.elastic.co/v1/package#owner"
+
+has_owner(p) {
+    p.annotations[owner_annotation]
+}
+
+owner(p) = o {
+    o := p.annotations[owner_annotation]
+}